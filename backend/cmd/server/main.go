@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	lobbycache "mini-meeting/internal/cache"
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/database"
 	"mini-meeting/internal/handlers"
@@ -38,13 +40,21 @@ func main() {
 	}
 	defer cache.Close()
 
+	// Deliver lobby events published by any backend instance to local sockets
+	go lobbycache.Hub.Run(context.Background())
+
 	// Run migrations
 	if err := database.Migrate(); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
 	// Initialize Fiber app
+	// Request bodies are streamed so that recordings uploaded to /sessions/import
+	// are not held in memory. Multipart forms are parsed by the handlers that
+	// read them, after the body limits set up with the routes have been checked.
 	app := fiber.New(fiber.Config{
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -67,28 +77,55 @@ func main() {
 	sessionRepo := repositories.NewSummarizerSessionRepository(database.GetDB())
 	chunkRepo := repositories.NewAudioChunkRepository(database.GetDB())
 	transcriptRepo := repositories.NewTranscriptRepository(database.GetDB())
+	meetingSettingsRepo := repositories.NewMeetingSettingsRepository(database.GetDB())
+	meetingInviteeRepo := repositories.NewMeetingInviteeRepository(database.GetDB())
+	meetingBanRepo := repositories.NewMeetingBanRepository(database.GetDB())
+	meetingCohostRepo := repositories.NewMeetingCohostRepository(database.GetDB())
+	meetingOverrideRepo := repositories.NewMeetingOccurrenceOverrideRepository(database.GetDB())
+	meetingRoleRepo := repositories.NewMeetingRoleAssignmentRepository(database.GetDB())
+	meetingAttendanceRepo := repositories.NewMeetingAttendanceRepository(database.GetDB())
+	summarizerConsentRepo := repositories.NewSummarizerConsentRepository(database.GetDB())
+	chatMessageRepo := repositories.NewChatMessageRepository(database.GetDB())
+	speakerCallRepo := repositories.NewSpeakerCallRepository(database.GetDB())
+	pollRepo := repositories.NewPollRepository(database.GetDB())
+	recordingRepo := repositories.NewRecordingRepository(database.GetDB())
 
 	// Initialize services
-	meetingService := services.NewMeetingService(meetingRepo)
+	meetingService := services.NewMeetingService(meetingRepo, meetingSettingsRepo, meetingInviteeRepo, meetingBanRepo, meetingCohostRepo, userRepo, meetingOverrideRepo, meetingRoleRepo, cfg)
 	userService := services.NewUserService(userRepo, meetingService)
 	livekitService := services.NewLiveKitService(cfg)
 	openRouterService := services.NewOpenRouterService(cfg)
 	emailService := services.NewEmailService(cfg)
+	attendanceService := services.NewAttendanceService(meetingAttendanceRepo, meetingService)
+	chatService := services.NewChatService(chatMessageRepo, meetingService, livekitService)
+	handRaiseService := services.NewHandRaiseService(speakerCallRepo, meetingService, livekitService)
+	pollService := services.NewPollService(pollRepo, meetingService, livekitService)
+	recordingService := services.NewRecordingService(recordingRepo, meetingService, livekitService, cfg)
+	invitationService := services.NewInvitationService(meetingService, meetingRepo, meetingInviteeRepo, userRepo, emailService, cfg)
 
 	// Dependency chain: SummarizationService <- NormalizationService <- TranscriptionService <- SummarizerService
-	summarizationService := services.NewSummarizationService(sessionRepo, userService, openRouterService, emailService, cfg)
-	normalizationService := services.NewNormalizationService(sessionRepo, transcriptRepo, summarizationService)
+	summarizationService := services.NewSummarizationService(sessionRepo, pollRepo, userService, openRouterService, emailService, cfg)
+	normalizationService := services.NewNormalizationService(sessionRepo, transcriptRepo, chatMessageRepo, summarizerConsentRepo, summarizationService)
 	transcriptionService := services.NewTranscriptionService(sessionRepo, chunkRepo, transcriptRepo, normalizationService, cfg)
-	summarizerService := services.NewSummarizerService(sessionRepo, chunkRepo, transcriptRepo, meetingService, livekitService, transcriptionService, cfg)
+	summarizerService := services.NewSummarizerService(sessionRepo, chunkRepo, transcriptRepo, summarizerConsentRepo, meetingService, livekitService, recordingService, transcriptionService, cfg)
+	breakoutService := services.NewBreakoutService(meetingService, livekitService, summarizerService)
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService)
 	authHandler := handlers.NewAuthHandler(userService, cfg)
-	meetingHandler := handlers.NewMeetingHandler(meetingService, cfg)
+	meetingHandler := handlers.NewMeetingHandler(meetingService, invitationService, cfg)
 	livekitHandler := handlers.NewLiveKitHandler(livekitService, meetingService, userService, summarizerService, cfg)
 	lobbyHandler := handlers.NewLobbyHandler(livekitService, meetingService, userService, cfg)
 	lobbyWSHandler := handlers.NewLobbyWSHandler(livekitService, meetingService, userService, cfg)
 	summarizerHandler := handlers.NewSummarizerHandler(summarizerService)
+	calendarHandler := handlers.NewCalendarHandler(meetingService, userService)
+	attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+	webhookHandler := handlers.NewWebhookHandler(livekitService, attendanceService, summarizerService, chatService, handRaiseService, recordingService)
+	breakoutHandler := handlers.NewBreakoutHandler(breakoutService)
+	chatHandler := handlers.NewChatHandler(chatService)
+	handRaiseHandler := handlers.NewHandRaiseHandler(handRaiseService)
+	pollHandler := handlers.NewPollHandler(pollService)
+	recordingHandler := handlers.NewRecordingHandler(recordingService)
 
 	// Initialize workers
 	// Transcription worker: Run every 60 minutes, process sessions stuck for > 15 minutes
@@ -100,9 +137,20 @@ func main() {
 	// Summarization worker: Run every 60 minutes, process sessions stuck for > 15 minutes
 	summarizationWorker := workers.NewSummarizationWorker(sessionRepo, summarizationService, 60*time.Minute, 15*time.Minute)
 	go summarizationWorker.Start()
+	// Lobby timeout worker: Run every 10 seconds, expire requests that waited past their meeting's lobby timeout
+	lobbyTimeoutWorker := workers.NewLobbyTimeoutWorker(lobbyWSHandler, 10*time.Second)
+	go lobbyTimeoutWorker.Start()
+	// Reminder worker: Run every minute, remind invitees of meetings starting within MEETING_REMINDER_MINUTES
+	reminderWorker := workers.NewReminderWorker(invitationService, time.Minute)
+	go reminderWorker.Start()
+	// Breakout deadline worker: Run every second, recall breakout rooms that ran out of time and delete closed ones
+	breakoutDeadlineWorker := workers.NewBreakoutDeadlineWorker(breakoutService, time.Second)
+	go breakoutDeadlineWorker.Start()
+	// Apply consent answers recorded on other instances to the summarizer bots running here
+	go summarizerService.RunConsentListener(context.Background())
 
 	// Setup routes
-	routes.SetupRoutes(app, userHandler, authHandler, meetingHandler, livekitHandler, lobbyHandler, lobbyWSHandler, summarizerHandler, cfg)
+	routes.SetupRoutes(app, userHandler, authHandler, meetingHandler, livekitHandler, lobbyHandler, lobbyWSHandler, summarizerHandler, calendarHandler, attendanceHandler, webhookHandler, breakoutHandler, chatHandler, handRaiseHandler, pollHandler, recordingHandler, cfg)
 
 	// Health check route
 	app.Get("/api/v1/health", func(c *fiber.Ctx) error {
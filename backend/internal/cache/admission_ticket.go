@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"mini-meeting/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// AdmissionTicketKeyPrefix stores one-time tickets handed out when the lobby admits someone
+	AdmissionTicketKeyPrefix = "lobby:ticket:"
+
+	// AdmissionTicketExpiration is how long an admitted visitor has to redeem their ticket (2 minutes)
+	AdmissionTicketExpiration = 2 * time.Minute
+)
+
+// AdmissionTicket lets a participant admitted through the lobby get a LiveKit token
+// from /livekit/token once, as the identity the lobby admitted
+type AdmissionTicket struct {
+	MeetingCode string `json:"meeting_code"`
+	Identity    string `json:"identity"`
+	Name        string `json:"name"`
+	UserID      uint   `json:"user_id,omitempty"`
+}
+
+// Admits reports whether the ticket was issued for joining meetingCode as
+// userID, 0 for a guest. A ticket cannot be used by anyone else.
+func (t *AdmissionTicket) Admits(meetingCode string, userID uint) bool {
+	return t.MeetingCode == meetingCode && t.UserID == userID
+}
+
+func admissionTicketKey(ticket string) string {
+	return fmt.Sprintf("%s%s", AdmissionTicketKeyPrefix, ticket)
+}
+
+// IssueAdmissionTicket stores a ticket for an admitted participant and returns its value
+func IssueAdmissionTicket(t *AdmissionTicket) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate ticket: %w", err)
+	}
+	ticket := hex.EncodeToString(b)
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal ticket: %w", err)
+	}
+	if err := cache.SetString(admissionTicketKey(ticket), string(data), AdmissionTicketExpiration); err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// GetAdmissionTicket returns the participant a ticket was issued to, leaving the
+// ticket in place so that it can still be redeemed once the join is allowed
+func GetAdmissionTicket(ticket string) (*AdmissionTicket, error) {
+	data, err := cache.GetString(admissionTicketKey(ticket))
+	if err != nil {
+		return nil, errors.New("admission ticket is invalid or expired")
+	}
+	return parseAdmissionTicket(data)
+}
+
+// RedeemAdmissionTicket returns the participant a ticket was issued to and
+// deletes it, so that each ticket works once
+func RedeemAdmissionTicket(ticket string) (*AdmissionTicket, error) {
+	data, err := cache.Client.GetDel(context.Background(), admissionTicketKey(ticket)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("admission ticket is invalid or expired")
+		}
+		return nil, fmt.Errorf("failed to redeem ticket: %w", err)
+	}
+	return parseAdmissionTicket(data)
+}
+
+func parseAdmissionTicket(data string) (*AdmissionTicket, error) {
+	var t AdmissionTicket
+	if err := json.Unmarshal([]byte(data), &t); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAdmissionTicketRedeemedOnce(t *testing.T) {
+	useTestRedis(t)
+
+	ticket, err := IssueAdmissionTicket(&AdmissionTicket{MeetingCode: "abc-defg-hij", Identity: "Alice_12", Name: "Alice", UserID: 12})
+	if err != nil {
+		t.Fatalf("IssueAdmissionTicket: %v", err)
+	}
+
+	// Looking a ticket up leaves it to be redeemed
+	for i := 0; i < 2; i++ {
+		got, err := GetAdmissionTicket(ticket)
+		if err != nil {
+			t.Fatalf("GetAdmissionTicket: %v", err)
+		}
+		if got.Identity != "Alice_12" || got.UserID != 12 {
+			t.Errorf("ticket %+v, want Alice_12 for user 12", got)
+		}
+	}
+
+	got, err := RedeemAdmissionTicket(ticket)
+	if err != nil {
+		t.Fatalf("RedeemAdmissionTicket: %v", err)
+	}
+	if got.Identity != "Alice_12" {
+		t.Errorf("redeemed identity %q, want Alice_12", got.Identity)
+	}
+
+	if _, err := RedeemAdmissionTicket(ticket); err == nil {
+		t.Error("ticket redeemed twice")
+	}
+	if _, err := GetAdmissionTicket(ticket); err == nil {
+		t.Error("redeemed ticket can still be looked up")
+	}
+}
+
+// Concurrent joins with the same ticket get a single token between them
+func TestAdmissionTicketConcurrentRedeem(t *testing.T) {
+	useTestRedis(t)
+
+	ticket, err := IssueAdmissionTicket(&AdmissionTicket{MeetingCode: "abc-defg-hij", Identity: "Guest_1", Name: "Guest"})
+	if err != nil {
+		t.Fatalf("IssueAdmissionTicket: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	redeemed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := RedeemAdmissionTicket(ticket); err == nil {
+				mu.Lock()
+				redeemed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if redeemed != 1 {
+		t.Errorf("ticket redeemed %d times, want once", redeemed)
+	}
+}
+
+func TestAdmissionTicketExpires(t *testing.T) {
+	server := useTestRedis(t)
+
+	ticket, err := IssueAdmissionTicket(&AdmissionTicket{MeetingCode: "abc-defg-hij", Identity: "Guest_1", Name: "Guest"})
+	if err != nil {
+		t.Fatalf("IssueAdmissionTicket: %v", err)
+	}
+
+	server.FastForward(AdmissionTicketExpiration)
+
+	if _, err := GetAdmissionTicket(ticket); err == nil {
+		t.Error("expired ticket can be looked up")
+	}
+	if _, err := RedeemAdmissionTicket(ticket); err == nil {
+		t.Error("expired ticket redeemed")
+	}
+}
+
+func TestRedeemUnknownAdmissionTicket(t *testing.T) {
+	useTestRedis(t)
+
+	for _, ticket := range []string{"", "deadbeef", "../lobby:request:p1"} {
+		if _, err := RedeemAdmissionTicket(ticket); err == nil {
+			t.Errorf("unknown ticket %q redeemed", ticket)
+		}
+	}
+}
+
+func TestAdmissionTicketAdmits(t *testing.T) {
+	userTicket := &AdmissionTicket{MeetingCode: "abc-defg-hij", Identity: "Alice_12", UserID: 12}
+	guestTicket := &AdmissionTicket{MeetingCode: "abc-defg-hij", Identity: "Guest_1"}
+
+	tests := []struct {
+		name        string
+		ticket      *AdmissionTicket
+		meetingCode string
+		userID      uint
+		want        bool
+	}{
+		{"user", userTicket, "abc-defg-hij", 12, true},
+		{"other user", userTicket, "abc-defg-hij", 13, false},
+		{"user ticket used by a guest", userTicket, "abc-defg-hij", 0, false},
+		{"user ticket for another meeting", userTicket, "xyz-wxyz-xyz", 12, false},
+		{"guest", guestTicket, "abc-defg-hij", 0, true},
+		{"guest ticket used by a user", guestTicket, "abc-defg-hij", 12, false},
+		{"guest ticket for another meeting", guestTicket, "xyz-wxyz-xyz", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ticket.Admits(tt.meetingCode, tt.userID); got != tt.want {
+				t.Errorf("Admits(%q, %d) = %v, want %v", tt.meetingCode, tt.userID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"mini-meeting/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// BreakoutKeyPrefix stores the breakout rooms of a meeting
+	BreakoutKeyPrefix = "breakout:"
+
+	// BreakoutExpiration bounds how long breakout rooms stay open (24 hours)
+	BreakoutExpiration = 24 * time.Hour
+
+	// BreakoutRecallsKey holds the meetings whose breakout rooms close at a set
+	// time, scored by that time in Unix milliseconds
+	BreakoutRecallsKey = "breakout:recalls"
+
+	// BreakoutRoomDeletionsKey holds the breakout rooms to delete once their grace
+	// period is over, scored by that time in Unix milliseconds
+	BreakoutRoomDeletionsKey = "breakout:room_deletions"
+)
+
+// Breakouts are the breakout rooms open under a meeting and who is assigned where.
+// EndsAt is set while a countdown announced to the rooms is running.
+type Breakouts struct {
+	MeetingCode string         `json:"meeting_code"`
+	Rooms       []BreakoutRoom `json:"rooms"`
+	// Assignments maps LiveKit identities to room numbers
+	Assignments map[string]int `json:"assignments"`
+	Opened      bool           `json:"opened"`
+	EndsAt      *time.Time     `json:"ends_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// BreakoutRoom is one child LiveKit room of a meeting
+type BreakoutRoom struct {
+	Number   int    `json:"number"`
+	Name     string `json:"name"`
+	RoomName string `json:"room_name"`
+}
+
+// Room returns the breakout room with the given number, or nil
+func (b *Breakouts) Room(number int) *BreakoutRoom {
+	for i := range b.Rooms {
+		if b.Rooms[i].Number == number {
+			return &b.Rooms[i]
+		}
+	}
+	return nil
+}
+
+func breakoutKey(meetingCode string) string {
+	return fmt.Sprintf("%s%s", BreakoutKeyPrefix, meetingCode)
+}
+
+// StoreBreakouts saves the breakout rooms of a meeting
+func StoreBreakouts(b *Breakouts) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to marshal breakouts: %w", err)
+	}
+
+	if err := cache.SetString(breakoutKey(b.MeetingCode), string(data), BreakoutExpiration); err != nil {
+		return fmt.Errorf("failed to store breakouts: %w", err)
+	}
+	return nil
+}
+
+// GetBreakouts returns the breakout rooms of a meeting
+func GetBreakouts(meetingCode string) (*Breakouts, error) {
+	data, err := cache.GetString(breakoutKey(meetingCode))
+	if err != nil {
+		return nil, fmt.Errorf("breakouts not found: %s", meetingCode)
+	}
+
+	var b Breakouts
+	if err := json.Unmarshal([]byte(data), &b); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal breakouts: %w", err)
+	}
+	if b.Assignments == nil {
+		b.Assignments = map[string]int{}
+	}
+	return &b, nil
+}
+
+// DeleteBreakouts removes the breakout rooms of a meeting
+func DeleteBreakouts(meetingCode string) error {
+	return cache.Delete(breakoutKey(meetingCode))
+}
+
+// ScheduleBreakoutRecall records that the breakout rooms of a meeting close at,
+// replacing the time set before
+func ScheduleBreakoutRecall(meetingCode string, at time.Time) error {
+	return scheduleDeadline(BreakoutRecallsKey, meetingCode, at)
+}
+
+// CancelBreakoutRecall forgets the time the breakout rooms of a meeting close at
+func CancelBreakoutRecall(meetingCode string) error {
+	return cache.Client.ZRem(context.Background(), BreakoutRecallsKey, meetingCode).Err()
+}
+
+// ClaimDueBreakoutRecalls returns the meetings whose breakout rooms are due to
+// close. Each meeting is returned to a single backend instance.
+func ClaimDueBreakoutRecalls(now time.Time) ([]string, error) {
+	return claimDueDeadlines(BreakoutRecallsKey, now)
+}
+
+// ScheduleRoomDeletion records that a breakout room is to be deleted at
+func ScheduleRoomDeletion(roomName string, at time.Time) error {
+	return scheduleDeadline(BreakoutRoomDeletionsKey, roomName, at)
+}
+
+// CancelRoomDeletion keeps a breakout room that was to be deleted
+func CancelRoomDeletion(roomName string) error {
+	return cache.Client.ZRem(context.Background(), BreakoutRoomDeletionsKey, roomName).Err()
+}
+
+// ClaimDueRoomDeletions returns the breakout rooms due to be deleted. Each room
+// is returned to a single backend instance.
+func ClaimDueRoomDeletions(now time.Time) ([]string, error) {
+	return claimDueDeadlines(BreakoutRoomDeletionsKey, now)
+}
+
+func scheduleDeadline(key string, member string, at time.Time) error {
+	err := cache.Client.ZAdd(context.Background(), key, redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: member,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", member, err)
+	}
+	return nil
+}
+
+// claimDueDeadlines removes and returns the members of key whose time has come.
+// Only the instance whose ZREM removed a member gets it.
+func claimDueDeadlines(key string, now time.Time) ([]string, error) {
+	ctx := context.Background()
+	due, err := cache.Client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due deadlines: %w", err)
+	}
+
+	claimed := make([]string, 0, len(due))
+	for _, member := range due {
+		removed, err := cache.Client.ZRem(ctx, key, member).Result()
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim %s: %w", member, err)
+		}
+		if removed == 1 {
+			claimed = append(claimed, member)
+		}
+	}
+	return claimed, nil
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mini-meeting/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// HandQueueKeyPrefix stores the raised hands of a room, a sorted set of
+	// identities scored by when they raised their hand (unix milliseconds)
+	HandQueueKeyPrefix = "hands:queue:"
+
+	// HandNamesKeyPrefix stores the display names of the raised hands of a room
+	HandNamesKeyPrefix = "hands:names:"
+
+	// ReactionRateKeyPrefix counts the reactions a participant sent in a room
+	// during the current rate window
+	ReactionRateKeyPrefix = "reactions:rate:"
+
+	// HandRaiseExpiration bounds how long a room's hands are kept (24 hours)
+	HandRaiseExpiration = 24 * time.Hour
+
+	// ReactionRateLimit is how many reactions a participant may send per ReactionRateWindow
+	ReactionRateLimit = 10
+
+	// ReactionRateWindow is the window reactions are counted over (10 seconds)
+	ReactionRateWindow = 10 * time.Second
+)
+
+// RaisedHand is a participant waiting in a room's speaker queue
+type RaisedHand struct {
+	Identity string    `json:"identity"`
+	Name     string    `json:"name"`
+	RaisedAt time.Time `json:"raised_at"`
+}
+
+func handQueueKey(roomName string) string {
+	return fmt.Sprintf("%s%s", HandQueueKeyPrefix, roomName)
+}
+
+func handNamesKey(roomName string) string {
+	return fmt.Sprintf("%s%s", HandNamesKeyPrefix, roomName)
+}
+
+func reactionRateKey(roomName string, identity string) string {
+	return fmt.Sprintf("%s%s:%s", ReactionRateKeyPrefix, roomName, identity)
+}
+
+// RaiseHand adds a participant to the end of a room's queue. A participant whose
+// hand is already raised keeps their place; raised reports whether they were added.
+func RaiseHand(roomName string, identity string, name string) (bool, error) {
+	ctx := context.Background()
+
+	added, err := cache.Client.ZAddNX(ctx, handQueueKey(roomName), redis.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: identity,
+	}).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to raise hand: %w", err)
+	}
+
+	if err := cache.Client.HSet(ctx, handNamesKey(roomName), identity, name).Err(); err != nil {
+		return false, fmt.Errorf("failed to store hand name: %w", err)
+	}
+
+	cache.Client.Expire(ctx, handQueueKey(roomName), HandRaiseExpiration)
+	cache.Client.Expire(ctx, handNamesKey(roomName), HandRaiseExpiration)
+
+	return added > 0, nil
+}
+
+// LowerHand removes a participant from a room's queue and returns their hand, or
+// nil if it was not raised
+func LowerHand(roomName string, identity string) (*RaisedHand, error) {
+	ctx := context.Background()
+
+	score, err := cache.Client.ZScore(ctx, handQueueKey(roomName), identity).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hand: %w", err)
+	}
+
+	removed, err := cache.Client.ZRem(ctx, handQueueKey(roomName), identity).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lower hand: %w", err)
+	}
+	if removed == 0 {
+		// Lowered concurrently
+		return nil, nil
+	}
+
+	name, _ := cache.Client.HGet(ctx, handNamesKey(roomName), identity).Result()
+	cache.Client.HDel(ctx, handNamesKey(roomName), identity)
+
+	return &RaisedHand{
+		Identity: identity,
+		Name:     name,
+		RaisedAt: time.UnixMilli(int64(score)),
+	}, nil
+}
+
+// PopNextHand removes the participant who has waited longest from a room's queue
+// and returns their hand, or nil when the queue is empty
+func PopNextHand(roomName string) (*RaisedHand, error) {
+	ctx := context.Background()
+
+	popped, err := cache.Client.ZPopMin(ctx, handQueueKey(roomName), 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pop hand: %w", err)
+	}
+	if len(popped) == 0 {
+		return nil, nil
+	}
+
+	identity, _ := popped[0].Member.(string)
+	name, _ := cache.Client.HGet(ctx, handNamesKey(roomName), identity).Result()
+	cache.Client.HDel(ctx, handNamesKey(roomName), identity)
+
+	return &RaisedHand{
+		Identity: identity,
+		Name:     name,
+		RaisedAt: time.UnixMilli(int64(popped[0].Score)),
+	}, nil
+}
+
+// ListRaisedHands returns a room's queue, longest waiting first
+func ListRaisedHands(roomName string) ([]RaisedHand, error) {
+	ctx := context.Background()
+
+	entries, err := cache.Client.ZRangeWithScores(ctx, handQueueKey(roomName), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hands: %w", err)
+	}
+
+	names, err := cache.Client.HGetAll(ctx, handNamesKey(roomName)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hand names: %w", err)
+	}
+
+	hands := make([]RaisedHand, 0, len(entries))
+	for _, entry := range entries {
+		identity, _ := entry.Member.(string)
+		hands = append(hands, RaisedHand{
+			Identity: identity,
+			Name:     names[identity],
+			RaisedAt: time.UnixMilli(int64(entry.Score)),
+		})
+	}
+	return hands, nil
+}
+
+// ClearHands lowers every hand of a room
+func ClearHands(roomName string) error {
+	return cache.Delete(handQueueKey(roomName), handNamesKey(roomName))
+}
+
+// AllowReaction counts a reaction of a participant in a room and reports whether
+// it stays within ReactionRateLimit reactions per ReactionRateWindow
+func AllowReaction(roomName string, identity string) (bool, error) {
+	ctx := context.Background()
+	key := reactionRateKey(roomName, identity)
+
+	count, err := cache.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to count reaction: %w", err)
+	}
+	if count == 1 {
+		cache.Client.Expire(ctx, key, ReactionRateWindow)
+	}
+	return count <= ReactionRateLimit, nil
+}
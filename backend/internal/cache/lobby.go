@@ -2,11 +2,17 @@ package cache
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"mini-meeting/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
 )
 
 const (
@@ -16,8 +22,27 @@ const (
 	// LobbyMeetingRequestsKeyPrefix stores the set of request IDs per meeting
 	LobbyMeetingRequestsKeyPrefix = "lobby:meeting:"
 
-	// LobbyExpiration is the TTL for all lobby data (1 hour)
+	// LobbyExpirationsKey is a sorted set of request IDs scored by their expiry time (unix seconds)
+	LobbyExpirationsKey = "lobby:expirations"
+
+	// LobbyExpiration is the default time a request may wait in the lobby (1 hour)
 	LobbyExpiration = 1 * time.Hour
+
+	// LobbyMaxTimeout is the longest wait a meeting may configure (24 hours)
+	LobbyMaxTimeout = 24 * time.Hour
+
+	// lobbyExpiryGrace keeps request data around after it times out,
+	// so that the timeout sweeper can still notify the visitor and admins
+	lobbyExpiryGrace = 5 * time.Minute
+
+	// lobbyWaitSamples is the number of recent waits used to estimate the wait time
+	lobbyWaitSamples = 20
+
+	// LobbyMessageMaxLength is the maximum length of a visitor's knock message
+	LobbyMessageMaxLength = 200
+
+	// LobbyReconnectGrace is how long a disconnected visitor keeps their place in the queue
+	LobbyReconnectGrace = 30 * time.Second
 )
 
 // LobbyRequestStatus represents the status of a lobby join request
@@ -27,6 +52,7 @@ const (
 	LobbyStatusPending  LobbyRequestStatus = "pending"
 	LobbyStatusApproved LobbyRequestStatus = "approved"
 	LobbyStatusRejected LobbyRequestStatus = "rejected"
+	LobbyStatusTimedOut LobbyRequestStatus = "timed_out"
 )
 
 // LobbyRequest represents a join request stored in Redis
@@ -38,8 +64,31 @@ type LobbyRequest struct {
 	AvatarURL   string             `json:"avatar_url,omitempty"`
 	Identity    string             `json:"identity"`
 	Role        string             `json:"role"`
+	Fingerprint string             `json:"fingerprint,omitempty"`
+	Message     string             `json:"message,omitempty"`
+	ResumeToken string             `json:"resume_token,omitempty"`
 	Status      LobbyRequestStatus `json:"status"`
 	CreatedAt   int64              `json:"created_at"`
+	ExpiresAt   int64              `json:"expires_at"`
+
+	// Token and Ticket are what an approved visitor was given, kept so that a
+	// visitor who reconnects gets them again rather than new ones
+	Token  string `json:"token,omitempty"`
+	Ticket string `json:"ticket,omitempty"`
+}
+
+// ttl returns how long the request data is kept in Redis
+func (r *LobbyRequest) ttl() time.Duration {
+	ttl := time.Until(time.Unix(r.ExpiresAt, 0)) + lobbyExpiryGrace
+	if r.ExpiresAt == 0 || ttl <= 0 {
+		return LobbyExpiration
+	}
+	return ttl
+}
+
+// ValidResumeToken reports whether token is the secret handed out with the request
+func (r *LobbyRequest) ValidResumeToken(token string) bool {
+	return r.ResumeToken != "" && subtle.ConstantTimeCompare([]byte(r.ResumeToken), []byte(token)) == 1
 }
 
 // --- Key helpers ---
@@ -52,17 +101,29 @@ func lobbyMeetingRequestsKey(meetingCode string) string {
 	return fmt.Sprintf("%s%s:requests", LobbyMeetingRequestsKeyPrefix, meetingCode)
 }
 
+func lobbyVisitorConnKey(requestID string) string {
+	return fmt.Sprintf("%s%s:conn", LobbyRequestKeyPrefix, requestID)
+}
+
+func lobbyMeetingWaitsKey(meetingCode string) string {
+	return fmt.Sprintf("%s%s:waits", LobbyMeetingRequestsKeyPrefix, meetingCode)
+}
+
 // --- Operations ---
 
 // StoreLobbyRequest stores a new join request in Redis
 func StoreLobbyRequest(req *LobbyRequest) error {
+	if req.ExpiresAt == 0 {
+		req.ExpiresAt = time.Unix(req.CreatedAt, 0).Add(LobbyExpiration).Unix()
+	}
+
 	data, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("failed to marshal lobby request: %w", err)
 	}
 
 	// Store the request data
-	if err := cache.SetString(lobbyRequestKey(req.ID), string(data), LobbyExpiration); err != nil {
+	if err := cache.SetString(lobbyRequestKey(req.ID), string(data), req.ttl()); err != nil {
 		return fmt.Errorf("failed to store lobby request: %w", err)
 	}
 
@@ -73,8 +134,16 @@ func StoreLobbyRequest(req *LobbyRequest) error {
 		return fmt.Errorf("failed to add request to meeting set: %w", err)
 	}
 
-	// Set expiration on the set key
-	cache.Client.Expire(ctx, lobbyMeetingRequestsKey(req.MeetingCode), LobbyExpiration)
+	// Set expiration on the set key (long enough for the longest configurable wait)
+	cache.Client.Expire(ctx, lobbyMeetingRequestsKey(req.MeetingCode), LobbyMaxTimeout+lobbyExpiryGrace)
+
+	// Schedule the request for the timeout sweeper
+	if err := cache.Client.ZAdd(ctx, LobbyExpirationsKey, redis.Z{
+		Score:  float64(req.ExpiresAt),
+		Member: req.ID,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to schedule lobby request expiry: %w", err)
+	}
 
 	return nil
 }
@@ -108,7 +177,7 @@ func UpdateLobbyRequestStatus(requestID string, status LobbyRequestStatus) error
 		return fmt.Errorf("failed to marshal lobby request: %w", err)
 	}
 
-	if err := cache.SetString(lobbyRequestKey(requestID), string(data), LobbyExpiration); err != nil {
+	if err := cache.SetString(lobbyRequestKey(requestID), string(data), req.ttl()); err != nil {
 		return fmt.Errorf("failed to update lobby request: %w", err)
 	}
 
@@ -120,7 +189,23 @@ func UpdateLobbyRequestStatus(requestID string, status LobbyRequestStatus) error
 	return nil
 }
 
-// GetPendingRequests returns all pending requests for a meeting
+// StoreLobbyApproval keeps the token and ticket given to an approved visitor
+func StoreLobbyApproval(req *LobbyRequest, token, ticket string) error {
+	req.Token = token
+	req.Ticket = ticket
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lobby request: %w", err)
+	}
+
+	if err := cache.SetString(lobbyRequestKey(req.ID), string(data), req.ttl()); err != nil {
+		return fmt.Errorf("failed to store lobby approval: %w", err)
+	}
+	return nil
+}
+
+// GetPendingRequests returns all pending requests for a meeting, oldest first
 func GetPendingRequests(meetingCode string) ([]*LobbyRequest, error) {
 	// Get all request IDs from the set
 	ctx := context.Background()
@@ -143,17 +228,224 @@ func GetPendingRequests(meetingCode string) ([]*LobbyRequest, error) {
 		}
 	}
 
+	// Order by arrival so that the list doubles as the waiting queue
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].CreatedAt != pending[j].CreatedAt {
+			return pending[i].CreatedAt < pending[j].CreatedAt
+		}
+		return pending[i].ID < pending[j].ID
+	})
+
 	return pending, nil
 }
 
+// Failure reasons reported by ResolveLobbyRequests
+const (
+	LobbyFailureNotFound        = "not_found"
+	LobbyFailureWrongMeeting    = "wrong_meeting"
+	LobbyFailureAlreadyResolved = "already_resolved"
+)
+
+// resolveLobbyRequestsScript atomically moves pending requests of a meeting to a
+// final status and removes them from the meeting's request set, so that two admins
+// acting at the same time can never resolve the same request twice.
+//
+// KEYS[1] = meeting request set, KEYS[2] = expirations sorted set
+// ARGV[1] = request key prefix, ARGV[2] = meeting code, ARGV[3] = new status
+// ARGV[4..] = request IDs to resolve (every member of the set when omitted)
+//
+// Returns {resolved request JSON..., {failed id, reason, ...}}
+var resolveLobbyRequestsScript = redis.NewScript(`
+local ids = {}
+if #ARGV > 3 then
+	for i = 4, #ARGV do ids[#ids + 1] = ARGV[i] end
+else
+	ids = redis.call('SMEMBERS', KEYS[1])
+end
+
+local resolved, failed = {}, {}
+for _, id in ipairs(ids) do
+	local key = ARGV[1] .. id
+	local raw = redis.call('GET', key)
+	if not raw then
+		redis.call('SREM', KEYS[1], id)
+		failed[#failed + 1] = id
+		failed[#failed + 1] = 'not_found'
+	else
+		local req = cjson.decode(raw)
+		if req.meeting_code ~= ARGV[2] then
+			failed[#failed + 1] = id
+			failed[#failed + 1] = 'wrong_meeting'
+		elseif req.status ~= 'pending' then
+			failed[#failed + 1] = id
+			failed[#failed + 1] = 'already_resolved'
+		else
+			req.status = ARGV[3]
+			local encoded = cjson.encode(req)
+			redis.call('SET', key, encoded, 'KEEPTTL')
+			redis.call('SREM', KEYS[1], id)
+			redis.call('ZREM', KEYS[2], id)
+			resolved[#resolved + 1] = encoded
+		end
+	end
+end
+
+return {resolved, failed}
+`)
+
+// ResolveLobbyRequests atomically sets the given status on pending requests of a meeting.
+// When requestIDs is empty, every pending request of the meeting is resolved.
+// It returns the requests that were resolved and, keyed by request ID, the reason
+// each remaining request could not be resolved.
+func ResolveLobbyRequests(meetingCode string, requestIDs []string, status LobbyRequestStatus) ([]*LobbyRequest, map[string]string, error) {
+	args := make([]interface{}, 0, len(requestIDs)+3)
+	args = append(args, LobbyRequestKeyPrefix, meetingCode, string(status))
+	for _, id := range requestIDs {
+		args = append(args, id)
+	}
+
+	result, err := resolveLobbyRequestsScript.Run(
+		context.Background(), cache.Client, []string{lobbyMeetingRequestsKey(meetingCode), LobbyExpirationsKey}, args...,
+	).Slice()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve lobby requests: %w", err)
+	}
+
+	resolvedRaw, _ := result[0].([]interface{})
+	failedRaw, _ := result[1].([]interface{})
+
+	resolved := make([]*LobbyRequest, 0, len(resolvedRaw))
+	for _, item := range resolvedRaw {
+		data, _ := item.(string)
+		var req LobbyRequest
+		if err := json.Unmarshal([]byte(data), &req); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal lobby request: %w", err)
+		}
+		resolved = append(resolved, &req)
+	}
+
+	failures := make(map[string]string, len(failedRaw)/2)
+	for i := 0; i+1 < len(failedRaw); i += 2 {
+		id, _ := failedRaw[i].(string)
+		reason, _ := failedRaw[i+1].(string)
+		failures[id] = reason
+	}
+
+	return resolved, failures, nil
+}
+
+// ReopenLobbyRequest puts a resolved request back into the meeting's pending set
+func ReopenLobbyRequest(req *LobbyRequest) error {
+	req.Status = LobbyStatusPending
+	if err := StoreLobbyRequest(req); err != nil {
+		return fmt.Errorf("failed to reopen lobby request: %w", err)
+	}
+	return nil
+}
+
 // CleanupLobbyRequest removes all data associated with a request
 func CleanupLobbyRequest(requestID string) error {
+	ctx := context.Background()
+
 	req, err := GetLobbyRequest(requestID)
 	if err == nil {
-		cache.Client.SRem(context.Background(), lobbyMeetingRequestsKey(req.MeetingCode), requestID)
+		cache.Client.SRem(ctx, lobbyMeetingRequestsKey(req.MeetingCode), requestID)
 	}
 
+	cache.Client.ZRem(ctx, LobbyExpirationsKey, requestID)
 	cache.Delete(lobbyRequestKey(requestID))
+	cache.Delete(lobbyVisitorConnKey(requestID))
+
+	return nil
+}
+
+// ClaimExpiredLobbyRequests returns the IDs of requests whose wait expired before now.
+// Each ID is handed to exactly one caller, even with several backend instances sweeping.
+func ClaimExpiredLobbyRequests(now time.Time) ([]string, error) {
+	ctx := context.Background()
+
+	ids, err := cache.Client.ZRangeByScore(ctx, LobbyExpirationsKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", now.Unix()),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expired lobby requests: %w", err)
+	}
+
+	claimed := make([]string, 0, len(ids))
+	for _, id := range ids {
+		// Only the instance that removes the entry processes it
+		if removed, err := cache.Client.ZRem(ctx, LobbyExpirationsKey, id).Result(); err == nil && removed == 1 {
+			claimed = append(claimed, id)
+		}
+	}
+
+	return claimed, nil
+}
+
+// RecordLobbyWait stores how long a resolved request waited, to estimate future waits
+func RecordLobbyWait(meetingCode string, wait time.Duration) {
+	ctx := context.Background()
+	key := lobbyMeetingWaitsKey(meetingCode)
+
+	cache.Client.LPush(ctx, key, int64(wait.Seconds()))
+	cache.Client.LTrim(ctx, key, 0, lobbyWaitSamples-1)
+	cache.Client.Expire(ctx, key, LobbyMaxTimeout)
+}
+
+// AverageLobbyWait returns the average wait of recently resolved requests of a meeting.
+// ok is false when no request has been resolved yet.
+func AverageLobbyWait(meetingCode string) (avg time.Duration, ok bool) {
+	samples, err := cache.Client.LRange(context.Background(), lobbyMeetingWaitsKey(meetingCode), 0, -1).Result()
+	if err != nil || len(samples) == 0 {
+		return 0, false
+	}
+
+	var total int64
+	for _, sample := range samples {
+		seconds, err := strconv.ParseInt(sample, 10, 64)
+		if err != nil {
+			continue
+		}
+		total += seconds
+	}
 
+	return time.Duration(total/int64(len(samples))) * time.Second, true
+}
+
+// detachVisitorScript clears the visitor's live connection only if it is still
+// the given one, so that a stale socket never detaches a reconnected visitor.
+//
+// KEYS[1] = connection key
+// ARGV[1] = connection ID
+var detachVisitorScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('SET', KEYS[1], '', 'KEEPTTL')
+	return 1
+end
+return 0
+`)
+
+// VisitorAttached reports whether a visitor socket was ever attached to the request
+func VisitorAttached(requestID string) bool {
+	n, err := cache.Client.Exists(context.Background(), lobbyVisitorConnKey(requestID)).Result()
+	return err == nil && n > 0
+}
+
+// AttachVisitor records connID as the live visitor connection of a request
+func AttachVisitor(req *LobbyRequest, connID string) error {
+	if err := cache.SetString(lobbyVisitorConnKey(req.ID), connID, req.ttl()); err != nil {
+		return fmt.Errorf("failed to attach visitor: %w", err)
+	}
 	return nil
 }
+
+// DetachVisitor clears the live visitor connection if it is still connID.
+// It returns false when the visitor has since reconnected with another connection.
+func DetachVisitor(requestID, connID string) (bool, error) {
+	detached, err := detachVisitorScript.Run(context.Background(), cache.Client, []string{lobbyVisitorConnKey(requestID)}, connID).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("failed to detach visitor: %w", err)
+	}
+	return detached == 1, nil
+}
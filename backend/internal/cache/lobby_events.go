@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mini-meeting/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// lobbyEventsMaxLen caps how many admin events are kept per meeting for replay
+	lobbyEventsMaxLen = 200
+
+	// lobbyEventsExpiration is how long the admin event log of an idle meeting is kept
+	lobbyEventsExpiration = 1 * time.Hour
+)
+
+func lobbyMeetingEventsKey(meetingCode string) string {
+	return fmt.Sprintf("%s%s:events", LobbyMeetingRequestsKeyPrefix, meetingCode)
+}
+
+// appendAdminEvent stores an admin event in the meeting's event log (a Redis stream)
+// and returns the event ID assigned by Redis.
+func appendAdminEvent(meetingCode string, data []byte) (string, error) {
+	if cache.Client == nil {
+		return "", fmt.Errorf("redis client is not initialized")
+	}
+
+	ctx := context.Background()
+	key := lobbyMeetingEventsKey(meetingCode)
+
+	id, err := cache.Client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: lobbyEventsMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"data": data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to append lobby event: %w", err)
+	}
+
+	cache.Client.Expire(ctx, key, lobbyEventsExpiration)
+	return id, nil
+}
+
+// LastAdminEventID returns the ID of the latest admin event of a meeting, or "" if there is none
+func LastAdminEventID(meetingCode string) string {
+	entries, err := cache.Client.XRevRangeN(context.Background(), lobbyMeetingEventsKey(meetingCode), "+", "-", 1).Result()
+	if err != nil || len(entries) == 0 {
+		return ""
+	}
+	return entries[0].ID
+}
+
+// AdminEventsSince returns the admin events of a meeting published after lastEventID,
+// oldest first, each carrying its event_id.
+func AdminEventsSince(meetingCode, lastEventID string) ([][]byte, error) {
+	entries, err := cache.Client.XRange(context.Background(), lobbyMeetingEventsKey(meetingCode), "("+lastEventID, "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lobby events: %w", err)
+	}
+
+	events := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		data, ok := entry.Values["data"].(string)
+		if !ok {
+			continue
+		}
+		events = append(events, withEventID([]byte(data), entry.ID))
+	}
+
+	return events, nil
+}
+
+// withEventID adds an "event_id" field to a JSON object message
+func withEventID(data []byte, eventID string) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+
+	id, _ := json.Marshal(eventID)
+	fields["event_id"] = id
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return out
+}
@@ -1,23 +1,154 @@
 package cache
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 	"sync"
+	"time"
+
+	"mini-meeting/pkg/cache"
 
 	"github.com/gofiber/contrib/websocket"
 )
 
+const (
+	// LobbyChannelPrefix is the common prefix of all lobby pub/sub channels
+	LobbyChannelPrefix = "lobby:events:"
+
+	// lobbyAdminsChannelPrefix carries events for the admins of a meeting (keyed by meeting code)
+	lobbyAdminsChannelPrefix = LobbyChannelPrefix + "admins:"
+
+	// lobbyVisitorChannelPrefix carries events for a single visitor (keyed by request ID)
+	lobbyVisitorChannelPrefix = LobbyChannelPrefix + "visitor:"
+)
+
+const (
+	// lobbySendBuffer is how many messages may wait to be written to one lobby socket;
+	// a client that falls further behind is disconnected
+	lobbySendBuffer = 64
+
+	// lobbyWriteWait is the time allowed to write one message to a lobby socket
+	lobbyWriteWait = 10 * time.Second
+)
+
+func lobbyAdminsChannel(meetingCode string) string {
+	return lobbyAdminsChannelPrefix + meetingCode
+}
+
+func lobbyVisitorChannel(requestID string) string {
+	return lobbyVisitorChannelPrefix + requestID
+}
+
+// LobbyConn is a lobby socket registered with the hub. The socket allows a single
+// writer, so every message goes through a buffered channel drained by one writer
+// goroutine; handlers send through the LobbyConn rather than writing to the socket.
+// Pings use WriteControl, which may run concurrently with the writer.
+type LobbyConn struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	done      chan struct{}
+	finished  chan struct{}
+	closeOnce sync.Once
+}
+
+func newLobbyConn(conn *websocket.Conn) *LobbyConn {
+	lc := &LobbyConn{
+		conn:     conn,
+		send:     make(chan []byte, lobbySendBuffer),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	go lc.writeLoop()
+	return lc
+}
+
+func (lc *LobbyConn) writeLoop() {
+	defer close(lc.finished)
+	for {
+		select {
+		case data := <-lc.send:
+			if !lc.write(data) {
+				return
+			}
+		case <-lc.done:
+			// Flush what was queued before the connection was closed
+			for {
+				select {
+				case data := <-lc.send:
+					if !lc.write(data) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (lc *LobbyConn) write(data []byte) bool {
+	lc.conn.SetWriteDeadline(time.Now().Add(lobbyWriteWait))
+	if err := lc.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		log.Printf("[LobbyHub] Failed to write to socket, closing: %v", err)
+		// Unblocks the handler's read loop, which unregisters the connection
+		lc.conn.Close()
+		return false
+	}
+	return true
+}
+
+// Send queues a message for the socket. It reports false when the connection is
+// closed or too far behind, in which case the socket is closed.
+func (lc *LobbyConn) Send(data []byte) bool {
+	select {
+	case <-lc.done:
+		return false
+	default:
+	}
+
+	select {
+	case lc.send <- data:
+		return true
+	default:
+		log.Printf("[LobbyHub] Socket is too slow to keep up, closing")
+		lc.conn.Close()
+		return false
+	}
+}
+
+// SendJSON queues a JSON message for the socket
+func (lc *LobbyConn) SendJSON(msg interface{}) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("[LobbyHub] Failed to marshal message: %v", err)
+		return false
+	}
+	return lc.Send(data)
+}
+
+// Close writes the messages already queued and stops the writer. It must be
+// called before the handler returns, since the socket is released afterwards.
+func (lc *LobbyConn) Close() {
+	lc.closeOnce.Do(func() { close(lc.done) })
+	<-lc.finished
+}
+
 // LobbyHub manages WebSocket connections for lobby functionality.
 // It tracks admin connections per meeting and visitor connections per request.
+// Notifications are fanned out through Redis pub/sub so that every backend
+// instance delivers them to the sockets it holds locally.
 type LobbyHub struct {
 	mu sync.RWMutex
 
 	// meetingCode → set of admin WS connections
-	admins map[string]map[*websocket.Conn]struct{}
+	admins map[string]map[*LobbyConn]struct{}
 
 	// requestID → visitor WS connection
-	visitors map[string]*websocket.Conn
+	visitors map[string]*LobbyConn
 }
 
 // Global hub instance
@@ -25,60 +156,127 @@ var Hub = NewLobbyHub()
 
 func NewLobbyHub() *LobbyHub {
 	return &LobbyHub{
-		admins:   make(map[string]map[*websocket.Conn]struct{}),
-		visitors: make(map[string]*websocket.Conn),
+		admins:   make(map[string]map[*LobbyConn]struct{}),
+		visitors: make(map[string]*LobbyConn),
 	}
 }
 
 // --- Admin connections ---
 
-func (h *LobbyHub) RegisterAdmin(meetingCode string, conn *websocket.Conn) {
+// RegisterAdmin registers an admin socket and returns the connection to send through
+func (h *LobbyHub) RegisterAdmin(meetingCode string, conn *websocket.Conn) *LobbyConn {
+	lc := newLobbyConn(conn)
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	if h.admins[meetingCode] == nil {
-		h.admins[meetingCode] = make(map[*websocket.Conn]struct{})
+		h.admins[meetingCode] = make(map[*LobbyConn]struct{})
 	}
-	h.admins[meetingCode][conn] = struct{}{}
+	h.admins[meetingCode][lc] = struct{}{}
 	log.Printf("[LobbyHub] Admin registered for meeting %s (total: %d)", meetingCode, len(h.admins[meetingCode]))
+	return lc
 }
 
-func (h *LobbyHub) UnregisterAdmin(meetingCode string, conn *websocket.Conn) {
+// UnregisterAdmin removes an admin connection and stops its writer
+func (h *LobbyHub) UnregisterAdmin(meetingCode string, lc *LobbyConn) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	if conns, ok := h.admins[meetingCode]; ok {
-		delete(conns, conn)
+		delete(conns, lc)
 		if len(conns) == 0 {
 			delete(h.admins, meetingCode)
 		}
 	}
+	h.mu.Unlock()
+
+	lc.Close()
 	log.Printf("[LobbyHub] Admin unregistered for meeting %s", meetingCode)
 }
 
 // --- Visitor connections ---
 
-func (h *LobbyHub) RegisterVisitor(requestID string, conn *websocket.Conn) {
+// RegisterVisitor registers a visitor socket and returns the connection to send through
+func (h *LobbyHub) RegisterVisitor(requestID string, conn *websocket.Conn) *LobbyConn {
+	lc := newLobbyConn(conn)
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.visitors[requestID] = conn
+	h.visitors[requestID] = lc
 	log.Printf("[LobbyHub] Visitor registered: %s", requestID)
+	return lc
 }
 
-func (h *LobbyHub) UnregisterVisitor(requestID string) {
+// UnregisterVisitor stops the writer of a visitor connection and removes it,
+// unless the visitor has already reconnected on this instance with a newer connection.
+func (h *LobbyHub) UnregisterVisitor(requestID string, lc *LobbyConn) {
 	h.mu.Lock()
-	defer h.mu.Unlock()
-	delete(h.visitors, requestID)
-	log.Printf("[LobbyHub] Visitor unregistered: %s", requestID)
+	current := h.visitors[requestID] == lc
+	if current {
+		delete(h.visitors, requestID)
+	}
+	h.mu.Unlock()
+
+	lc.Close()
+	if current {
+		log.Printf("[LobbyHub] Visitor unregistered: %s", requestID)
+	}
 }
 
 // --- Notifications ---
 
 // NotifyAdmins broadcasts a JSON message to all admin connections for a meeting.
-// Dead connections are automatically evicted on write failure.
+// The message is appended to the meeting's event log, so reconnecting admins can
+// replay what they missed, and published on the meeting's Redis channel so that
+// admins connected to any backend instance receive it.
 func (h *LobbyHub) NotifyAdmins(meetingCode string, msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("[LobbyHub] Failed to marshal admin notification: %v", err)
+		return
+	}
+
+	if eventID, err := appendAdminEvent(meetingCode, data); err != nil {
+		log.Printf("[LobbyHub] Failed to log admin notification: %v", err)
+	} else {
+		data = withEventID(data, eventID)
+	}
+
+	if err := h.publish(lobbyAdminsChannel(meetingCode), data); err != nil {
+		log.Printf("[LobbyHub] Failed to publish admin notification, delivering locally: %v", err)
+		h.deliverToAdmins(meetingCode, data)
+	}
+}
+
+// NotifyVisitor sends a JSON message to a specific visitor connection.
+// The message is published on the request's Redis channel so that it reaches
+// the visitor regardless of which backend instance holds the socket.
+func (h *LobbyHub) NotifyVisitor(requestID string, msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("[LobbyHub] Failed to marshal visitor notification: %v", err)
+		return
+	}
+
+	if err := h.publish(lobbyVisitorChannel(requestID), data); err != nil {
+		log.Printf("[LobbyHub] Failed to publish visitor notification, delivering locally: %v", err)
+		h.deliverToVisitor(requestID, data)
+	}
+}
+
+func (h *LobbyHub) publish(channel string, data []byte) error {
+	if cache.Client == nil {
+		return fmt.Errorf("redis client is not initialized")
+	}
+	return cache.Client.Publish(context.Background(), channel, data).Err()
+}
+
+// --- Local delivery ---
+
+// deliverToAdmins queues a message for the admin connections held by this instance.
+// Dead connections are automatically evicted.
+func (h *LobbyHub) deliverToAdmins(meetingCode string, data []byte) {
 	h.mu.RLock()
-	conns := make([]*websocket.Conn, 0)
+	conns := make([]*LobbyConn, 0)
 	if adminSet, ok := h.admins[meetingCode]; ok {
 		for conn := range adminSet {
 			conns = append(conns, conn)
@@ -86,15 +284,9 @@ func (h *LobbyHub) NotifyAdmins(meetingCode string, msg interface{}) {
 	}
 	h.mu.RUnlock()
 
-	data, err := json.Marshal(msg)
-	if err != nil {
-		log.Printf("[LobbyHub] Failed to marshal admin notification: %v", err)
-		return
-	}
-
 	for _, conn := range conns {
-		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-			log.Printf("[LobbyHub] Failed to send to admin, evicting dead connection: %v", err)
+		if !conn.Send(data) {
+			log.Printf("[LobbyHub] Failed to send to admin, evicting dead connection")
 			// Evict dead connection to prevent memory leak
 			h.mu.Lock()
 			if adminSet, ok := h.admins[meetingCode]; ok {
@@ -108,28 +300,61 @@ func (h *LobbyHub) NotifyAdmins(meetingCode string, msg interface{}) {
 	}
 }
 
-// NotifyVisitor sends a JSON message to a specific visitor connection.
-// Removes the dead connection on write failure.
-func (h *LobbyHub) NotifyVisitor(requestID string, msg interface{}) {
+// deliverToVisitor queues a message for the visitor connection if this instance holds it.
+// Removes the dead connection.
+func (h *LobbyHub) deliverToVisitor(requestID string, data []byte) {
 	h.mu.RLock()
 	conn, ok := h.visitors[requestID]
 	h.mu.RUnlock()
 
 	if !ok {
-		log.Printf("[LobbyHub] No visitor connection for request %s", requestID)
+		// The visitor is connected to another instance (or not at all)
 		return
 	}
 
-	data, err := json.Marshal(msg)
-	if err != nil {
-		log.Printf("[LobbyHub] Failed to marshal visitor notification: %v", err)
-		return
-	}
-
-	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-		log.Printf("[LobbyHub] Failed to send to visitor %s, evicting: %v", requestID, err)
+	if !conn.Send(data) {
+		log.Printf("[LobbyHub] Failed to send to visitor %s, evicting", requestID)
 		h.mu.Lock()
-		delete(h.visitors, requestID)
+		if h.visitors[requestID] == conn {
+			delete(h.visitors, requestID)
+		}
 		h.mu.Unlock()
 	}
 }
+
+// --- Redis subscription ---
+
+// Run subscribes to the lobby Redis channels and delivers every published
+// message to the matching sockets on this instance. It blocks until ctx is
+// cancelled, so it should be started in its own goroutine after Redis is connected.
+func (h *LobbyHub) Run(ctx context.Context) {
+	pubsub := cache.Client.PSubscribe(ctx, LobbyChannelPrefix+"*")
+	defer pubsub.Close()
+
+	log.Printf("[LobbyHub] Subscribed to %s* for cross-instance delivery", LobbyChannelPrefix)
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			h.dispatch(msg.Channel, []byte(msg.Payload))
+		}
+	}
+}
+
+// dispatch routes a message received from Redis to local admin or visitor sockets.
+func (h *LobbyHub) dispatch(channel string, data []byte) {
+	switch {
+	case strings.HasPrefix(channel, lobbyAdminsChannelPrefix):
+		h.deliverToAdmins(strings.TrimPrefix(channel, lobbyAdminsChannelPrefix), data)
+	case strings.HasPrefix(channel, lobbyVisitorChannelPrefix):
+		h.deliverToVisitor(strings.TrimPrefix(channel, lobbyVisitorChannelPrefix), data)
+	default:
+		log.Printf("[LobbyHub] Ignoring message on unknown channel %s", channel)
+	}
+}
@@ -0,0 +1,344 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"mini-meeting/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// useTestRedis points the cache at an in-memory Redis for the duration of the test
+func useTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	previous := cache.Client
+	cache.Client = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		cache.Client.Close()
+		cache.Client = previous
+	})
+	return server
+}
+
+func storeTestRequest(t *testing.T, id, meetingCode string, status LobbyRequestStatus) {
+	t.Helper()
+
+	now := time.Now()
+	req := &LobbyRequest{
+		ID:          id,
+		MeetingCode: meetingCode,
+		Name:        "Visitor " + id,
+		Identity:    "Visitor_" + id,
+		Role:        "guest",
+		Status:      LobbyStatusPending,
+		CreatedAt:   now.Unix(),
+		ExpiresAt:   now.Add(LobbyExpiration).Unix(),
+	}
+	if err := StoreLobbyRequest(req); err != nil {
+		t.Fatalf("StoreLobbyRequest(%s): %v", id, err)
+	}
+	if status != LobbyStatusPending {
+		if err := UpdateLobbyRequestStatus(id, status); err != nil {
+			t.Fatalf("UpdateLobbyRequestStatus(%s): %v", id, err)
+		}
+	}
+}
+
+func TestResolveLobbyRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		ids          []string
+		status       LobbyRequestStatus
+		wantResolved []string
+		wantFailures map[string]string
+	}{
+		{
+			name:         "all pending",
+			status:       LobbyStatusApproved,
+			wantResolved: []string{"p1", "p2", "p3"},
+			wantFailures: map[string]string{},
+		},
+		{
+			name:         "selected",
+			ids:          []string{"p2"},
+			status:       LobbyStatusRejected,
+			wantResolved: []string{"p2"},
+			wantFailures: map[string]string{},
+		},
+		{
+			name:         "partial failures",
+			ids:          []string{"p1", "gone", "other", "done", "p3"},
+			status:       LobbyStatusApproved,
+			wantResolved: []string{"p1", "p3"},
+			wantFailures: map[string]string{
+				"gone":  LobbyFailureNotFound,
+				"other": LobbyFailureWrongMeeting,
+				"done":  LobbyFailureAlreadyResolved,
+			},
+		},
+		{
+			name:         "nothing resolvable",
+			ids:          []string{"gone", "other"},
+			status:       LobbyStatusApproved,
+			wantResolved: []string{},
+			wantFailures: map[string]string{
+				"gone":  LobbyFailureNotFound,
+				"other": LobbyFailureWrongMeeting,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestRedis(t)
+			storeTestRequest(t, "p1", "abc-defg-hij", LobbyStatusPending)
+			storeTestRequest(t, "p2", "abc-defg-hij", LobbyStatusPending)
+			storeTestRequest(t, "p3", "abc-defg-hij", LobbyStatusPending)
+			storeTestRequest(t, "done", "abc-defg-hij", LobbyStatusRejected)
+			storeTestRequest(t, "other", "xyz-wxyz-xyz", LobbyStatusPending)
+
+			resolved, failures, err := ResolveLobbyRequests("abc-defg-hij", tt.ids, tt.status)
+			if err != nil {
+				t.Fatalf("ResolveLobbyRequests: %v", err)
+			}
+
+			ids := make([]string, 0, len(resolved))
+			for _, req := range resolved {
+				if req.Status != tt.status {
+					t.Errorf("request %s has status %q, want %q", req.ID, req.Status, tt.status)
+				}
+				ids = append(ids, req.ID)
+			}
+			sort.Strings(ids)
+			if len(ids) != len(tt.wantResolved) {
+				t.Fatalf("resolved %v, want %v", ids, tt.wantResolved)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantResolved[i] {
+					t.Fatalf("resolved %v, want %v", ids, tt.wantResolved)
+				}
+			}
+
+			if len(failures) != len(tt.wantFailures) {
+				t.Fatalf("failures %v, want %v", failures, tt.wantFailures)
+			}
+			for id, reason := range tt.wantFailures {
+				if failures[id] != reason {
+					t.Errorf("failure of %s = %q, want %q", id, failures[id], reason)
+				}
+			}
+
+			// Resolved requests keep their data but leave the queue
+			for _, id := range tt.wantResolved {
+				req, err := GetLobbyRequest(id)
+				if err != nil {
+					t.Fatalf("GetLobbyRequest(%s): %v", id, err)
+				}
+				if req.Status != tt.status {
+					t.Errorf("stored request %s has status %q, want %q", id, req.Status, tt.status)
+				}
+			}
+			pending, err := GetPendingRequests("abc-defg-hij")
+			if err != nil {
+				t.Fatalf("GetPendingRequests: %v", err)
+			}
+			if want := 3 - len(tt.wantResolved); len(pending) != want {
+				t.Errorf("%d requests still pending, want %d", len(pending), want)
+			}
+
+			// A request of another meeting is left alone
+			other, err := GetLobbyRequest("other")
+			if err != nil || other.Status != LobbyStatusPending {
+				t.Errorf("request of another meeting changed: %+v, %v", other, err)
+			}
+		})
+	}
+}
+
+// Two admins resolving the same requests at once never both get them
+func TestResolveLobbyRequestsOnce(t *testing.T) {
+	useTestRedis(t)
+	storeTestRequest(t, "p1", "abc-defg-hij", LobbyStatusPending)
+
+	first, _, err := ResolveLobbyRequests("abc-defg-hij", []string{"p1"}, LobbyStatusApproved)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("first resolve: %d resolved, %v", len(first), err)
+	}
+
+	second, failures, err := ResolveLobbyRequests("abc-defg-hij", []string{"p1"}, LobbyStatusRejected)
+	if err != nil {
+		t.Fatalf("second resolve: %v", err)
+	}
+	if len(second) != 0 || failures["p1"] != LobbyFailureAlreadyResolved {
+		t.Errorf("second resolve got %d requests and failures %v", len(second), failures)
+	}
+
+	if n, _ := cache.Client.ZCard(context.Background(), LobbyExpirationsKey).Result(); n != 0 {
+		t.Errorf("%d resolved requests still scheduled to time out", n)
+	}
+}
+
+// The pending list is the waiting queue: oldest knock first
+func TestGetPendingRequestsQueueOrder(t *testing.T) {
+	useTestRedis(t)
+
+	now := time.Now()
+	for _, r := range []struct {
+		id  string
+		age time.Duration
+	}{{"b", 2 * time.Minute}, {"c", time.Minute}, {"a", 2 * time.Minute}, {"d", 3 * time.Minute}} {
+		req := &LobbyRequest{ID: r.id, MeetingCode: "abc-defg-hij", Status: LobbyStatusPending, CreatedAt: now.Add(-r.age).Unix()}
+		if err := StoreLobbyRequest(req); err != nil {
+			t.Fatalf("StoreLobbyRequest(%s): %v", r.id, err)
+		}
+	}
+
+	pending, err := GetPendingRequests("abc-defg-hij")
+	if err != nil {
+		t.Fatalf("GetPendingRequests: %v", err)
+	}
+	var order string
+	for _, req := range pending {
+		order += req.ID
+	}
+	if order != "dabc" {
+		t.Errorf("queue order %q, want %q", order, "dabc")
+	}
+}
+
+func TestClaimExpiredLobbyRequests(t *testing.T) {
+	useTestRedis(t)
+
+	now := time.Now()
+	for id, expiresIn := range map[string]time.Duration{"late": -time.Minute, "due": 0, "waiting": time.Minute} {
+		req := &LobbyRequest{ID: id, MeetingCode: "abc-defg-hij", Status: LobbyStatusPending, CreatedAt: now.Unix(), ExpiresAt: now.Add(expiresIn).Unix()}
+		if err := StoreLobbyRequest(req); err != nil {
+			t.Fatalf("StoreLobbyRequest(%s): %v", id, err)
+		}
+	}
+
+	claimed, err := ClaimExpiredLobbyRequests(now)
+	if err != nil {
+		t.Fatalf("ClaimExpiredLobbyRequests: %v", err)
+	}
+	sort.Strings(claimed)
+	if len(claimed) != 2 || claimed[0] != "due" || claimed[1] != "late" {
+		t.Errorf("claimed %v, want [due late]", claimed)
+	}
+
+	// Another instance sweeping at the same time gets nothing
+	if again, err := ClaimExpiredLobbyRequests(now); err != nil || len(again) != 0 {
+		t.Errorf("second sweep claimed %v, %v", again, err)
+	}
+
+	// Timed-out requests keep their data for the grace period, to notify the visitor
+	if _, err := GetLobbyRequest("late"); err != nil {
+		t.Errorf("timed-out request data is gone: %v", err)
+	}
+}
+
+func TestAverageLobbyWait(t *testing.T) {
+	useTestRedis(t)
+
+	if _, ok := AverageLobbyWait("abc-defg-hij"); ok {
+		t.Fatal("average wait reported before any wait was recorded")
+	}
+
+	for i := 1; i <= lobbyWaitSamples+5; i++ {
+		RecordLobbyWait("abc-defg-hij", time.Duration(i)*time.Minute)
+	}
+
+	// Only the most recent samples count: waits 6 to 25 minutes
+	avg, ok := AverageLobbyWait("abc-defg-hij")
+	if !ok || avg != 15*time.Minute+30*time.Second {
+		t.Errorf("AverageLobbyWait = %v, %v, want 15m30s", avg, ok)
+	}
+	if _, ok := AverageLobbyWait("xyz-wxyz-xyz"); ok {
+		t.Error("average wait reported for another meeting")
+	}
+}
+
+func TestValidResumeToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+		given  string
+		want   bool
+	}{
+		{"matching", "s3cret-token", "s3cret-token", true},
+		{"wrong", "s3cret-token", "s3cret-tokem", false},
+		{"prefix", "s3cret-token", "s3cret", false},
+		{"empty given", "s3cret-token", "", false},
+		{"no token issued", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &LobbyRequest{ResumeToken: tt.stored}
+			if got := req.ValidResumeToken(tt.given); got != tt.want {
+				t.Errorf("ValidResumeToken(%q) = %v, want %v", tt.given, got, tt.want)
+			}
+		})
+	}
+}
+
+// A stale socket closing after the visitor reconnected does not detach them
+func TestDetachVisitor(t *testing.T) {
+	useTestRedis(t)
+	storeTestRequest(t, "p1", "abc-defg-hij", LobbyStatusPending)
+	req, err := GetLobbyRequest("p1")
+	if err != nil {
+		t.Fatalf("GetLobbyRequest: %v", err)
+	}
+
+	if VisitorAttached("p1") {
+		t.Fatal("visitor attached before connecting")
+	}
+	if err := AttachVisitor(req, "conn-1"); err != nil {
+		t.Fatalf("AttachVisitor: %v", err)
+	}
+	if err := AttachVisitor(req, "conn-2"); err != nil {
+		t.Fatalf("AttachVisitor: %v", err)
+	}
+
+	if detached, err := DetachVisitor("p1", "conn-1"); err != nil || detached {
+		t.Errorf("stale connection detached the visitor: %v, %v", detached, err)
+	}
+	if detached, err := DetachVisitor("p1", "conn-2"); err != nil || !detached {
+		t.Errorf("live connection did not detach the visitor: %v, %v", detached, err)
+	}
+	if !VisitorAttached("p1") {
+		t.Error("a detached visitor no longer counts as having connected")
+	}
+	if detached, err := DetachVisitor("p2", "conn-1"); err != nil || detached {
+		t.Errorf("unknown request detached: %v, %v", detached, err)
+	}
+}
+
+// A visitor who reconnects after approval is given the token and ticket already issued
+func TestStoreLobbyApproval(t *testing.T) {
+	useTestRedis(t)
+	storeTestRequest(t, "p1", "abc-defg-hij", LobbyStatusPending)
+
+	resolved, _, err := ResolveLobbyRequests("abc-defg-hij", []string{"p1"}, LobbyStatusApproved)
+	if err != nil || len(resolved) != 1 {
+		t.Fatalf("ResolveLobbyRequests: %d resolved, %v", len(resolved), err)
+	}
+	if err := StoreLobbyApproval(resolved[0], "join-token", "ticket"); err != nil {
+		t.Fatalf("StoreLobbyApproval: %v", err)
+	}
+
+	req, err := GetLobbyRequest("p1")
+	if err != nil {
+		t.Fatalf("GetLobbyRequest: %v", err)
+	}
+	if req.Status != LobbyStatusApproved || req.Token != "join-token" || req.Ticket != "ticket" {
+		t.Errorf("stored request %+v, want approved with its token and ticket", req)
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"mini-meeting/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// LockKeyPrefix stores locks held by one backend instance at a time
+	LockKeyPrefix = "lock:"
+
+	// lockRetryInterval is how often AcquireLock retries a lock held elsewhere
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// ErrLockBusy is returned by AcquireLock when the lock stayed held for the whole wait
+var ErrLockBusy = errors.New("lock is held by someone else")
+
+// Releasing and extending a lock only touch it while it still holds our token,
+// so that a lock that expired and was taken by someone else is left alone
+var (
+	releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+// Lock is a Redis lock shared by every backend instance. It expires after its
+// TTL in case its holder dies without releasing it.
+type Lock struct {
+	key   string
+	token string
+}
+
+func lockKey(name string) string {
+	return fmt.Sprintf("%s%s", LockKeyPrefix, name)
+}
+
+// TryLock takes the lock name for ttl. It returns nil without an error when the
+// lock is already held.
+func TryLock(name string, ttl time.Duration) (*Lock, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	lock := &Lock{key: lockKey(name), token: hex.EncodeToString(b)}
+
+	acquired, err := cache.Client.SetNX(context.Background(), lock.key, lock.token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take lock %s: %w", name, err)
+	}
+	if !acquired {
+		return nil, nil
+	}
+	return lock, nil
+}
+
+// AcquireLock takes the lock name for ttl, waiting up to wait for it to be released
+func AcquireLock(name string, ttl time.Duration, wait time.Duration) (*Lock, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		lock, err := TryLock(name, ttl)
+		if err != nil || lock != nil {
+			return lock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLockBusy
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Extend pushes the expiry of a held lock to ttl from now. It reports false when
+// the lock expired and was lost.
+func (l *Lock) Extend(ttl time.Duration) (bool, error) {
+	extended, err := extendLockScript.Run(context.Background(), cache.Client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to extend lock: %w", err)
+	}
+	return extended == 1, nil
+}
+
+// Release frees the lock if it is still held
+func (l *Lock) Release() error {
+	if err := releaseLockScript.Run(context.Background(), cache.Client, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mini-meeting/pkg/cache"
+)
+
+const (
+	// ParticipantKeyPrefix stores who is behind a LiveKit identity in a meeting
+	ParticipantKeyPrefix = "participant:"
+
+	// ParticipantExpiration matches the validity of LiveKit join tokens (24 hours)
+	ParticipantExpiration = 24 * time.Hour
+)
+
+// Participant records the account or device a LiveKit identity was issued to,
+// so that moderation actions taken on an identity can be applied to the person.
+// Role is the role the server gave the identity; unlike the role in LiveKit
+// metadata, it cannot be changed by the participant.
+type Participant struct {
+	Identity    string `json:"identity"`
+	Name        string `json:"name"`
+	UserID      uint   `json:"user_id,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Role        string `json:"role,omitempty"`
+}
+
+func participantKey(meetingCode, identity string) string {
+	return fmt.Sprintf("%s%s:%s", ParticipantKeyPrefix, meetingCode, identity)
+}
+
+// StoreParticipant remembers who a LiveKit identity belongs to in a meeting
+func StoreParticipant(meetingCode string, p *Participant) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal participant: %w", err)
+	}
+
+	if err := cache.SetString(participantKey(meetingCode, p.Identity), string(data), ParticipantExpiration); err != nil {
+		return fmt.Errorf("failed to store participant: %w", err)
+	}
+	return nil
+}
+
+// GetParticipant returns who a LiveKit identity belongs to in a meeting
+func GetParticipant(meetingCode, identity string) (*Participant, error) {
+	data, err := cache.GetString(participantKey(meetingCode, identity))
+	if err != nil {
+		return nil, fmt.Errorf("participant not found: %s", identity)
+	}
+
+	var p Participant
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal participant: %w", err)
+	}
+	return &p, nil
+}
+
+// DeleteParticipant forgets a LiveKit identity of a meeting, so that it can no
+// longer act in the meeting through the API
+func DeleteParticipant(meetingCode, identity string) error {
+	if err := cache.Delete(participantKey(meetingCode, identity)); err != nil {
+		return fmt.Errorf("failed to delete participant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"mini-meeting/pkg/cache"
+)
+
+// ConsentChannel carries the consent answers recorded by any backend instance
+// to the instance whose bot captures the session
+const ConsentChannel = "summarizer:consent"
+
+// ConsentChange is a participant's answer to the consent request of a session
+type ConsentChange struct {
+	SessionID uint   `json:"session_id"`
+	Identity  string `json:"identity"`
+	Consented bool   `json:"consented"`
+}
+
+// PublishConsentChange sends a consent answer to every backend instance
+func PublishConsentChange(change *ConsentChange) error {
+	if cache.Client == nil {
+		return fmt.Errorf("redis client is not initialized")
+	}
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal consent change: %w", err)
+	}
+	return cache.Client.Publish(context.Background(), ConsentChannel, data).Err()
+}
+
+// SubscribeConsentChanges calls apply with every consent answer published by any
+// backend instance. It blocks until ctx is cancelled, so it should be started in
+// its own goroutine after Redis is connected.
+func SubscribeConsentChanges(ctx context.Context, apply func(change *ConsentChange)) {
+	pubsub := cache.Client.Subscribe(ctx, ConsentChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var change ConsentChange
+			if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+				log.Printf("[Consent] Ignoring malformed consent change: %v", err)
+				continue
+			}
+			apply(&change)
+		}
+	}
+}
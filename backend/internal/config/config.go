@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -19,12 +20,16 @@ type Config struct {
 	Whisper    WhisperConfig
 	OpenRouter OpenRouterConfig
 	Brevo      BrevoConfig
+	Scheduling SchedulingConfig
+	Recording  RecordingConfig
 }
 
 type ServerConfig struct {
 	Port        string
 	Env         string
 	FrontendURL string
+	// APIURL is the public base URL of this server, used in links sent by email
+	APIURL string
 }
 
 type DatabaseConfig struct {
@@ -69,12 +74,25 @@ type LiveKitConfig struct {
 	APIKey    string
 	APISecret string
 	URL       string
+	// EmptyTimeoutSeconds is how long LiveKit keeps a room open after everyone left
+	EmptyTimeoutSeconds int
 }
 
 type SummarizerConfig struct {
 	ChunkDurationSeconds int
 	TempDir              string
 	CleanupAfterHours    int
+	// IdleTimeoutSeconds is how long the bot waits in a room without participants
+	// before stopping the session on its own
+	IdleTimeoutSeconds int
+	// MaxActiveSessions caps the sessions capturing at the same time across the server (0 = no limit)
+	MaxActiveSessions int
+	// MaxDailySessionsPerUser caps the sessions started on behalf of one user in 24 hours (0 = no limit)
+	MaxDailySessionsPerUser int
+	// FFmpegPath is the ffmpeg binary used to split imported recordings into chunks
+	FFmpegPath string
+	// MaxImportMB caps the size of an uploaded recording; it also raises the request body limit
+	MaxImportMB int
 }
 
 type WhisperConfig struct {
@@ -98,6 +116,39 @@ type BrevoConfig struct {
 	SenderName  string
 }
 
+// SchedulingConfig controls when participants may join scheduled meetings.
+// With EnforceJoinWindow set, joins are refused earlier than JoinEarlyMinutes
+// before the planned start or later than JoinLateMinutes after the planned end.
+type SchedulingConfig struct {
+	EnforceJoinWindow bool
+	JoinEarlyMinutes  int
+	JoinLateMinutes   int
+	// ReminderMinutes is how long before a meeting starts invitees are reminded; 0 disables reminders
+	ReminderMinutes int
+}
+
+// RecordingConfig tells LiveKit Egress where to write meeting recordings.
+// With Storage "local", Egress writes under OutputDir and this server serves the
+// files from LocalDir, the same directory as mounted here. With Storage "s3",
+// files are uploaded to an S3-compatible bucket and downloaded through presigned URLs.
+type RecordingConfig struct {
+	Storage   string
+	OutputDir string
+	LocalDir  string
+	S3        S3Config
+}
+
+type S3Config struct {
+	Endpoint       string
+	Region         string
+	Bucket         string
+	AccessKey      string
+	SecretKey      string
+	ForcePathStyle bool
+	// Prefix is prepended to the key of every recording
+	Prefix string
+}
+
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
@@ -107,6 +158,7 @@ func Load() (*Config, error) {
 			Port:        getEnv("PORT", "3000"),
 			Env:         getEnv("ENV", "development"),
 			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:5173"),
+			APIURL:      getEnv("API_URL", "http://localhost:3000"),
 		},
 		Database: DatabaseConfig{
 			URL:      getEnv("DATABASE_URL"),
@@ -139,14 +191,20 @@ func Load() (*Config, error) {
 			},
 		},
 		LiveKit: LiveKitConfig{
-			APIKey:    getEnv("LIVEKIT_API_KEY"),
-			APISecret: getEnv("LIVEKIT_API_SECRET"),
-			URL:       getEnv("LIVEKIT_URL", "ws://localhost:7880"),
+			APIKey:              getEnv("LIVEKIT_API_KEY"),
+			APISecret:           getEnv("LIVEKIT_API_SECRET"),
+			URL:                 getEnv("LIVEKIT_URL", "ws://localhost:7880"),
+			EmptyTimeoutSeconds: getEnvAsInt("LIVEKIT_EMPTY_TIMEOUT_SECONDS", 300),
 		},
 		Summarizer: SummarizerConfig{
-			ChunkDurationSeconds: getEnvAsInt("SUMMARIZER_CHUNK_DURATION_SECONDS", 20),
-			TempDir:              getEnv("SUMMARIZER_TEMP_DIR", "./tmp/summarizer"),
-			CleanupAfterHours:    getEnvAsInt("SUMMARIZER_CLEANUP_AFTER_HOURS", 24),
+			ChunkDurationSeconds:    getEnvAsInt("SUMMARIZER_CHUNK_DURATION_SECONDS", 20),
+			TempDir:                 getEnv("SUMMARIZER_TEMP_DIR", "./tmp/summarizer"),
+			CleanupAfterHours:       getEnvAsInt("SUMMARIZER_CLEANUP_AFTER_HOURS", 24),
+			IdleTimeoutSeconds:      getEnvAsInt("SUMMARIZER_IDLE_TIMEOUT_SECONDS", 120),
+			MaxActiveSessions:       getEnvAsInt("SUMMARIZER_MAX_ACTIVE_SESSIONS", 0),
+			MaxDailySessionsPerUser: getEnvAsInt("SUMMARIZER_MAX_DAILY_SESSIONS_PER_USER", 0),
+			FFmpegPath:              getEnv("SUMMARIZER_FFMPEG_PATH", "ffmpeg"),
+			MaxImportMB:             getEnvAsInt("SUMMARIZER_MAX_IMPORT_MB", 500),
 		},
 		Whisper: WhisperConfig{
 			URL:            getEnv("WHISPER_URL", "http://localhost:9000"),
@@ -166,6 +224,26 @@ func Load() (*Config, error) {
 			SenderEmail: getEnv("BREVO_SENDER_EMAIL", "noreply@mini-meeting.app"),
 			SenderName:  getEnv("BREVO_SENDER_NAME", "Mini Meeting"),
 		},
+		Scheduling: SchedulingConfig{
+			EnforceJoinWindow: getEnvAsBool("MEETING_ENFORCE_JOIN_WINDOW", false),
+			JoinEarlyMinutes:  getEnvAsInt("MEETING_JOIN_EARLY_MINUTES", 15),
+			JoinLateMinutes:   getEnvAsInt("MEETING_JOIN_LATE_MINUTES", 30),
+			ReminderMinutes:   getEnvAsInt("MEETING_REMINDER_MINUTES", 15),
+		},
+		Recording: RecordingConfig{
+			Storage:   getEnv("RECORDING_STORAGE", "local"),
+			OutputDir: getEnv("RECORDING_OUTPUT_DIR", "/out/recordings"),
+			LocalDir:  getEnv("RECORDING_LOCAL_DIR", "./recordings"),
+			S3: S3Config{
+				Endpoint:       getEnv("RECORDING_S3_ENDPOINT"),
+				Region:         getEnv("RECORDING_S3_REGION", "us-east-1"),
+				Bucket:         getEnv("RECORDING_S3_BUCKET"),
+				AccessKey:      getEnv("RECORDING_S3_ACCESS_KEY"),
+				SecretKey:      getEnv("RECORDING_S3_SECRET_KEY"),
+				ForcePathStyle: getEnvAsBool("RECORDING_S3_FORCE_PATH_STYLE", false),
+				Prefix:         getEnv("RECORDING_S3_PREFIX", "recordings"),
+			},
+		},
 	}
 
 	// Resolve TempDir to an absolute path so it works regardless of the
@@ -173,6 +251,9 @@ func Load() (*Config, error) {
 	if absDir, err := filepath.Abs(config.Summarizer.TempDir); err == nil {
 		config.Summarizer.TempDir = absDir
 	}
+	if absDir, err := filepath.Abs(config.Recording.LocalDir); err == nil {
+		config.Recording.LocalDir = absDir
+	}
 
 	return config, nil
 }
@@ -211,3 +292,11 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return intValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"mini-meeting/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AttendanceHandler struct {
+	service *services.AttendanceService
+}
+
+func NewAttendanceHandler(service *services.AttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{service: service}
+}
+
+// GetAttendanceReport lists who attended a meeting, when and for how long (hosts only)
+// GET /api/v1/meetings/:id/attendance
+func (h *AttendanceHandler) GetAttendanceReport(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	report, err := h.service.GetAttendanceReport(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+	})
+}
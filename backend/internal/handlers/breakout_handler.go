@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BreakoutHandler struct {
+	service *services.BreakoutService
+}
+
+func NewBreakoutHandler(service *services.BreakoutService) *BreakoutHandler {
+	return &BreakoutHandler{service: service}
+}
+
+// breakoutErrorResponse maps breakout service errors to HTTP status codes
+func breakoutErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		statusCode = fiber.StatusNotFound
+	case strings.HasPrefix(err.Error(), "unauthorized:") || err.Error() == "summarizer is not allowed for this meeting":
+		statusCode = fiber.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "):
+		statusCode = fiber.StatusBadRequest
+	case strings.Contains(err.Error(), "already"):
+		statusCode = fiber.StatusConflict
+	case strings.HasPrefix(err.Error(), "session is not active"):
+		statusCode = fiber.StatusConflict
+	case strings.HasPrefix(err.Error(), "quota exceeded"):
+		statusCode = fiber.StatusTooManyRequests
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// CreateBreakouts creates breakout rooms under a meeting (hosts only)
+// POST /api/v1/meetings/:id/breakouts
+func (h *BreakoutHandler) CreateBreakouts(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.CreateBreakoutsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	breakouts, err := h.service.CreateBreakouts(uint(meetingID), userID, &req)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": breakouts,
+	})
+}
+
+// GetBreakouts lists the breakout rooms of a meeting with who is in each (hosts only)
+// GET /api/v1/meetings/:id/breakouts
+func (h *BreakoutHandler) GetBreakouts(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	breakouts, err := h.service.GetBreakouts(uint(meetingID), userID)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": breakouts,
+	})
+}
+
+// AssignParticipants assigns participants to breakout rooms (hosts only)
+// PUT /api/v1/meetings/:id/breakouts/assignments
+func (h *BreakoutHandler) AssignParticipants(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.BreakoutAssignmentsRequest
+	if err := c.BodyParser(&req); err != nil || req.Assignments == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "assignments are required",
+		})
+	}
+
+	breakouts, err := h.service.AssignParticipants(uint(meetingID), userID, req.Assignments)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": breakouts,
+	})
+}
+
+// ShuffleParticipants spreads participants evenly over the breakout rooms at random (hosts only)
+// POST /api/v1/meetings/:id/breakouts/shuffle
+func (h *BreakoutHandler) ShuffleParticipants(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	breakouts, err := h.service.ShuffleParticipants(uint(meetingID), userID)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": breakouts,
+	})
+}
+
+// OpenBreakouts sends assigned participants to their breakout rooms (hosts only)
+// POST /api/v1/meetings/:id/breakouts/open
+func (h *BreakoutHandler) OpenBreakouts(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.OpenBreakoutsRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	breakouts, err := h.service.OpenBreakouts(uint(meetingID), userID, req.DurationSeconds)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": breakouts,
+	})
+}
+
+// MoveParticipant moves one participant into a breakout room or back to the main room (hosts only)
+// POST /api/v1/meetings/:id/breakouts/move
+func (h *BreakoutHandler) MoveParticipant(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.MoveParticipantRequest
+	if err := c.BodyParser(&req); err != nil || req.Identity == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "identity is required",
+		})
+	}
+
+	if err := h.service.MoveParticipant(uint(meetingID), userID, req.Identity, req.Room); err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Broadcast announces a countdown to every breakout room (hosts only)
+// POST /api/v1/meetings/:id/breakouts/broadcast
+func (h *BreakoutHandler) Broadcast(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.BreakoutBroadcastRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.service.Broadcast(uint(meetingID), userID, &req); err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CloseBreakouts recalls everyone to the main room and closes the breakout rooms (hosts only)
+// POST /api/v1/meetings/:id/breakouts/close
+func (h *BreakoutHandler) CloseBreakouts(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	recalled, err := h.service.CloseBreakouts(uint(meetingID), userID)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Breakout rooms closed",
+		"data": fiber.Map{
+			"recalled": recalled,
+		},
+	})
+}
+
+// StartSummarizer starts the summarizer in one breakout room (hosts only)
+// POST /api/v1/meetings/:id/breakouts/:number/summarizer/start
+func (h *BreakoutHandler) StartSummarizer(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	number, err := strconv.Atoi(c.Params("number"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid room number",
+		})
+	}
+
+	session, err := h.service.StartSummarizer(uint(meetingID), userID, number)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Summarizer started successfully",
+		"data": fiber.Map{
+			"session_id": session.ID,
+			"meeting_id": session.MeetingID,
+			"room_name":  session.RoomName,
+			"status":     session.Status,
+			"started_at": session.StartedAt,
+		},
+	})
+}
+
+// StopSummarizer stops the summarizer of one breakout room (hosts only)
+// POST /api/v1/meetings/:id/breakouts/:number/summarizer/stop
+func (h *BreakoutHandler) StopSummarizer(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	number, err := strconv.Atoi(c.Params("number"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid room number",
+		})
+	}
+
+	session, totalChunks, err := h.service.StopSummarizer(uint(meetingID), userID, number)
+	if err != nil {
+		return breakoutErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Summarizer stopped successfully",
+		"data": fiber.Map{
+			"session_id":   session.ID,
+			"meeting_id":   session.MeetingID,
+			"room_name":    session.RoomName,
+			"status":       session.Status,
+			"started_at":   session.StartedAt,
+			"ended_at":     session.EndedAt,
+			"total_chunks": totalChunks,
+		},
+	})
+}
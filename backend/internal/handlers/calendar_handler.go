@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"mini-meeting/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CalendarHandler struct {
+	meetingService *services.MeetingService
+	userService    *services.UserService
+}
+
+func NewCalendarHandler(meetingService *services.MeetingService, userService *services.UserService) *CalendarHandler {
+	return &CalendarHandler{
+		meetingService: meetingService,
+		userService:    userService,
+	}
+}
+
+// GetMeetingInvite downloads the iCalendar invite of a scheduled meeting (hosts and invitees only)
+// GET /api/v1/meetings/:id/invite.ics
+func (h *CalendarHandler) GetMeetingInvite(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	calendar, err := h.meetingService.MeetingInvite(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="invite.ics"`)
+	return c.Send(calendar.Bytes())
+}
+
+// GetCalendarFeed serves the calendar subscription feed identified by its secret token.
+// It is public so calendar applications can poll it.
+// GET /api/v1/calendar/:token.ics
+func (h *CalendarHandler) GetCalendarFeed(c *fiber.Ctx) error {
+	user, err := h.userService.GetUserByCalendarToken(c.Params("token"))
+	if err != nil {
+		if err.Error() == "user not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Calendar not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	calendar, err := h.meetingService.CalendarFeed(user)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	return c.Send(calendar.Bytes())
+}
+
+// GetMyCalendar returns the subscription URL of the authenticated user's calendar feed
+// GET /api/v1/users/me/calendar
+func (h *CalendarHandler) GetMyCalendar(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	token, err := h.userService.GetCalendarToken(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": fiber.Map{
+			"feed_url": c.BaseURL() + "/api/v1/calendar/" + token + ".ics",
+		},
+	})
+}
+
+// RotateMyCalendar replaces the secret of the authenticated user's calendar feed
+// POST /api/v1/users/me/calendar/rotate
+func (h *CalendarHandler) RotateMyCalendar(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	token, err := h.userService.RotateCalendarToken(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Calendar feed URL rotated successfully",
+		"data": fiber.Map{
+			"feed_url": c.BaseURL() + "/api/v1/calendar/" + token + ".ics",
+		},
+	})
+}
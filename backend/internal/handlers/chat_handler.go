@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"mini-meeting/internal/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ChatHandler struct {
+	service *services.ChatService
+}
+
+func NewChatHandler(service *services.ChatService) *ChatHandler {
+	return &ChatHandler{service: service}
+}
+
+// GetChat lists the recorded chat of a meeting in the order it was sent (hosts only)
+// GET /api/v1/meetings/:id/chat?page=1&page_size=50
+func (h *ChatHandler) GetChat(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 50)
+
+	response, err := h.service.GetChat(uint(id), userID, page, pageSize)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(response)
+}
@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// CreateBreakoutsRequest represents the request to create breakout rooms under a
+// meeting. Names are optional, one per room in order.
+type CreateBreakoutsRequest struct {
+	Count int      `json:"count" validate:"required,min=1"`
+	Names []string `json:"names,omitempty"`
+}
+
+// BreakoutAssignmentsRequest maps LiveKit identities to breakout room numbers.
+// Room 0 takes a participant back to the main room.
+type BreakoutAssignmentsRequest struct {
+	Assignments map[string]int `json:"assignments" validate:"required"`
+}
+
+// OpenBreakoutsRequest represents the request to send assigned participants to
+// their breakout rooms. With DurationSeconds everyone is recalled once it elapses.
+type OpenBreakoutsRequest struct {
+	DurationSeconds int `json:"duration_seconds,omitempty"`
+}
+
+// MoveParticipantRequest represents the request to move one participant into a
+// breakout room, or back to the main room when Room is 0
+type MoveParticipantRequest struct {
+	Identity string `json:"identity" validate:"required"`
+	Room     int    `json:"room"`
+}
+
+// BreakoutBroadcastRequest represents a countdown announced to every breakout
+// room. With Recall everyone is brought back to the main room when it ends.
+type BreakoutBroadcastRequest struct {
+	Seconds int    `json:"seconds"`
+	Message string `json:"message,omitempty"`
+	Recall  bool   `json:"recall,omitempty"`
+}
+
+// BreakoutRoomResponse is a breakout room with who is assigned to it and who is in it
+type BreakoutRoomResponse struct {
+	Number       int               `json:"number"`
+	Name         string            `json:"name"`
+	RoomName     string            `json:"room_name"`
+	Assigned     []string          `json:"assigned"`
+	Participants []ParticipantInfo `json:"participants"`
+	SessionID    *uint             `json:"summarizer_session_id,omitempty"`
+}
+
+// BreakoutsResponse describes the breakout rooms of a meeting
+type BreakoutsResponse struct {
+	MeetingCode string                 `json:"meeting_code"`
+	Opened      bool                   `json:"opened"`
+	EndsAt      *time.Time             `json:"ends_at,omitempty"`
+	Rooms       []BreakoutRoomResponse `json:"rooms"`
+	MainRoom    []ParticipantInfo      `json:"main_room"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "mini-meeting/internal/models"
+
+type PaginatedChatResponse struct {
+	Data       []models.ChatMessage `json:"data"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	PageSize   int                  `json:"page_size"`
+	TotalPages int                  `json:"total_pages"`
+}
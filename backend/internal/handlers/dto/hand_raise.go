@@ -0,0 +1,48 @@
+package dto
+
+import "mini-meeting/internal/models"
+
+// ParticipantTokenRequest identifies a participant by the LiveKit token they joined with,
+// which works for guests as well as signed-in users
+type ParticipantTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ReactionRequest represents a reaction a participant sends to their room
+type ReactionRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Reaction string `json:"reaction" validate:"required"`
+}
+
+// LowerHandRequest represents a host lowering the hand of a participant, or every
+// hand of the room when ParticipantIdentity is empty. RoomName selects a breakout
+// room of the meeting; empty means the main room.
+type LowerHandRequest struct {
+	MeetingCode         string `json:"meeting_code" validate:"required"`
+	RoomName            string `json:"room_name,omitempty"`
+	ParticipantIdentity string `json:"participant_identity,omitempty"`
+}
+
+// CallOnRequest represents a host calling on the next raised hand, or on a given
+// participant when ParticipantIdentity is set
+type CallOnRequest struct {
+	MeetingCode         string `json:"meeting_code" validate:"required"`
+	RoomName            string `json:"room_name,omitempty"`
+	ParticipantIdentity string `json:"participant_identity,omitempty"`
+}
+
+// SpeakerCallSummary is how often one participant was called on in a meeting
+type SpeakerCallSummary struct {
+	Identity string `json:"identity"`
+	Name     string `json:"name"`
+	UserID   *uint  `json:"user_id,omitempty"`
+	Calls    int    `json:"calls"`
+	// AverageWaitSeconds is the average time between raising their hand and being called on
+	AverageWaitSeconds float64 `json:"average_wait_seconds"`
+}
+
+// SpeakerCallReport lists who was called on in a meeting
+type SpeakerCallReport struct {
+	Calls        []models.SpeakerCall `json:"calls"`
+	Participants []SpeakerCallSummary `json:"participants"`
+}
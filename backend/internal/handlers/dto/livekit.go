@@ -4,6 +4,9 @@ package dto
 type GenerateTokenRequest struct {
 	MeetingCode string `json:"meeting_code" validate:"required"`
 	UserName    string `json:"user_name,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"` // Device ID generated by the client, used to block guests on a best-effort basis
+	Ticket      string `json:"ticket,omitempty"`      // One-time admission ticket handed out by the lobby on approval
+	Passcode    string `json:"passcode,omitempty"`
 }
 
 // GenerateTokenResponse represents the response after generating a LiveKit token
@@ -15,10 +18,13 @@ type GenerateTokenResponse struct {
 	UserName string `json:"user_name"`
 }
 
-// RemoveParticipantRequest represents the request to remove a participant from a meeting
+// RemoveParticipantRequest represents the request to remove a participant from a meeting.
+// When Block is set, the participant is also added to the meeting's block list.
 type RemoveParticipantRequest struct {
 	MeetingCode         string `json:"meeting_code" validate:"required"`
 	ParticipantIdentity string `json:"participant_identity" validate:"required"`
+	Block               bool   `json:"block,omitempty"`
+	Reason              string `json:"reason,omitempty"`
 }
 
 // MuteParticipantRequest represents the request to mute/unmute a participant
@@ -29,6 +35,51 @@ type MuteParticipantRequest struct {
 	Muted               bool   `json:"muted"`
 }
 
+// MuteAllRequest represents the request to mute the microphones of everyone but the hosts.
+// With BlockUnmute the participants cannot unmute themselves until allowed again.
+type MuteAllRequest struct {
+	MeetingCode string `json:"meeting_code" validate:"required"`
+	BlockUnmute bool   `json:"block_unmute"`
+}
+
+// DisableVideoRequest represents the request to stop the cameras and screen shares
+// of everyone but the hosts. Sources defaults to camera, screen_share and
+// screen_share_audio. With Block the participants cannot turn them back on.
+type DisableVideoRequest struct {
+	MeetingCode string   `json:"meeting_code" validate:"required"`
+	Sources     []string `json:"sources,omitempty"`
+	Block       bool     `json:"block"`
+}
+
+// AllowPublishRequest represents the request to lift the publishing blocks of one
+// participant, or of everyone when ParticipantIdentity is empty
+type AllowPublishRequest struct {
+	MeetingCode         string `json:"meeting_code" validate:"required"`
+	ParticipantIdentity string `json:"participant_identity,omitempty"`
+}
+
+// AskToUnmuteRequest represents a host asking a muted participant to unmute
+type AskToUnmuteRequest struct {
+	MeetingCode         string `json:"meeting_code" validate:"required"`
+	ParticipantIdentity string `json:"participant_identity" validate:"required"`
+}
+
+// SetRoleRequest represents the request to change the role of an in-call participant
+// to presenter, user, guest, attendee or viewer
+type SetRoleRequest struct {
+	MeetingCode         string `json:"meeting_code" validate:"required"`
+	ParticipantIdentity string `json:"participant_identity" validate:"required"`
+	Role                string `json:"role" validate:"required"`
+}
+
+// SetCohostRequest represents the request to promote an in-call participant to
+// co-host (Cohost true) or demote them back to a regular participant
+type SetCohostRequest struct {
+	MeetingCode         string `json:"meeting_code" validate:"required"`
+	ParticipantIdentity string `json:"participant_identity" validate:"required"`
+	Cohost              bool   `json:"cohost"`
+}
+
 // EndMeetingRequest represents the request to end a meeting
 type EndMeetingRequest struct {
 	MeetingCode string `json:"meeting_code" validate:"required"`
@@ -4,6 +4,9 @@ package dto
 type LobbyJoinRequest struct {
 	MeetingCode string `json:"meeting_code" validate:"required"`
 	UserName    string `json:"user_name,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"` // Device ID generated by the client, used to block guests on a best-effort basis
+	Message     string `json:"message,omitempty"`     // Short note shown to the host with the request
+	Passcode    string `json:"passcode,omitempty"`
 }
 
 // LobbyJoinResponse is returned when a join request is created
@@ -11,6 +14,11 @@ type LobbyJoinResponse struct {
 	RequestID string `json:"request_id"`
 	Status    string `json:"status"` // "pending", "approved", "auto_approved"
 
+	// Only set when pending. ResumeToken must be presented when
+	// reconnecting the visitor WebSocket after a dropped connection.
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+	ResumeToken string `json:"resume_token,omitempty"`
+
 	// Only set when auto-approved (admin)
 	Token    string `json:"token,omitempty"`
 	URL      string `json:"url,omitempty"`
@@ -7,12 +7,21 @@ import (
 
 // MeetingResponse represents the API response for a meeting
 type MeetingResponse struct {
-	ID          uint      `json:"id"`
-	CreatorID   uint      `json:"creator_id"`
-	MeetingCode string    `json:"meeting_code"`
-	MeetingLink string    `json:"meeting_link"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint                 `json:"id"`
+	CreatorID   uint                 `json:"creator_id"`
+	MeetingCode string               `json:"meeting_code"`
+	MeetingLink string               `json:"meeting_link"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	StartTime   *time.Time           `json:"start_time"`
+	EndTime     *time.Time           `json:"end_time"`
+	TimeZone    string               `json:"time_zone"`
+	Status      models.MeetingStatus `json:"status"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+
+	RecurrenceRule string                    `json:"recurrence_rule,omitempty"`
+	NextOccurrence *models.MeetingOccurrence `json:"next_occurrence,omitempty"`
 }
 
 // ToMeetingResponse converts a Meeting model to MeetingResponse
@@ -22,7 +31,152 @@ func ToMeetingResponse(m *models.Meeting, baseURL string) MeetingResponse {
 		CreatorID:   m.CreatorID,
 		MeetingCode: m.MeetingCode,
 		MeetingLink: baseURL + m.MeetingCode,
+		Title:       m.Title,
+		Description: m.Description,
+		StartTime:   m.StartTime,
+		EndTime:     m.EndTime,
+		TimeZone:    m.TimeZone,
+		Status:      m.Status,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
+
+		RecurrenceRule: m.RecurrenceRule,
+		NextOccurrence: m.NextOccurrence,
+	}
+}
+
+// CreateMeetingRequest represents the optional body of a meeting creation.
+// An empty body creates an instant meeting; a start_time schedules it.
+// Times are RFC 3339; time_zone is an IANA name such as "Africa/Cairo".
+// recurrence_rule is an RFC 5545 RRULE such as "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+// expanded in time_zone from start_time.
+type CreateMeetingRequest struct {
+	Title          string     `json:"title,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	StartTime      *time.Time `json:"start_time,omitempty"`
+	EndTime        *time.Time `json:"end_time,omitempty"`
+	TimeZone       string     `json:"time_zone,omitempty"`
+	RecurrenceRule string     `json:"recurrence_rule,omitempty"`
+}
+
+// UpdateMeetingRequest represents a partial update of a meeting's details and schedule.
+// Omitted fields are left unchanged.
+type UpdateMeetingRequest struct {
+	Title       *string    `json:"title,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	StartTime   *time.Time `json:"start_time,omitempty"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+	TimeZone    *string    `json:"time_zone,omitempty"`
+
+	// An empty string stops the meeting from repeating
+	RecurrenceRule *string `json:"recurrence_rule,omitempty"`
+}
+
+// OccurrenceOverrideRequest changes or cancels one occurrence of a recurring meeting.
+// Omitted times keep the times given by the recurrence rule.
+type OccurrenceOverrideRequest struct {
+	Cancelled bool       `json:"cancelled"`
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Title     string     `json:"title,omitempty"`
+}
+
+// MeetingSettingsResponse represents the API response for meeting settings
+type MeetingSettingsResponse struct {
+	MeetingID           uint                    `json:"meeting_id"`
+	AdmissionPolicy     models.AdmissionPolicy  `json:"admission_policy"`
+	AllowedDomains      []string                `json:"allowed_domains"`
+	LobbyTimeoutSeconds int                     `json:"lobby_timeout_seconds"`
+	AutoSummarize       bool                    `json:"auto_summarize"`
+	ConsentMode         bool                    `json:"consent_mode"`
+	LobbyRequired       models.LobbyRequirement `json:"lobby_required"`
+	HasPasscode         bool                    `json:"has_passcode"`
+	AllowGuests         bool                    `json:"allow_guests"`
+	MaxParticipants     int                     `json:"max_participants"`
+	Locked              bool                    `json:"locked"`
+	SummarizerAllowed   bool                    `json:"summarizer_allowed"`
+	DefaultRole         models.ParticipantRole  `json:"default_role"`
+}
+
+// UpdateMeetingSettingsRequest represents a partial update of meeting settings.
+// Omitted fields are left unchanged.
+type UpdateMeetingSettingsRequest struct {
+	AdmissionPolicy     *string   `json:"admission_policy,omitempty"`
+	AllowedDomains      *[]string `json:"allowed_domains,omitempty"`
+	LobbyTimeoutSeconds *int      `json:"lobby_timeout_seconds,omitempty"`
+	AutoSummarize       *bool     `json:"auto_summarize,omitempty"`
+	ConsentMode         *bool     `json:"consent_mode,omitempty"`
+	LobbyRequired       *string   `json:"lobby_required,omitempty"`
+	// Passcode sets the passcode participants must enter; an empty string removes it
+	Passcode          *string `json:"passcode,omitempty"`
+	AllowGuests       *bool   `json:"allow_guests,omitempty"`
+	MaxParticipants   *int    `json:"max_participants,omitempty"`
+	Locked            *bool   `json:"locked,omitempty"`
+	SummarizerAllowed *bool   `json:"summarizer_allowed,omitempty"`
+	// DefaultRole is presenter, attendee or viewer; an empty string restores regular participants
+	DefaultRole *string `json:"default_role,omitempty"`
+}
+
+// ToMeetingSettingsResponse converts a MeetingSettings model to MeetingSettingsResponse
+func ToMeetingSettingsResponse(s *models.MeetingSettings) MeetingSettingsResponse {
+	return MeetingSettingsResponse{
+		MeetingID:           s.MeetingID,
+		AdmissionPolicy:     s.AdmissionPolicy,
+		AllowedDomains:      s.Domains(),
+		LobbyTimeoutSeconds: s.LobbyTimeoutSeconds,
+		AutoSummarize:       s.AutoSummarize,
+		ConsentMode:         s.ConsentMode,
+		LobbyRequired:       s.LobbyRequired,
+		HasPasscode:         s.HasPasscode(),
+		AllowGuests:         s.AllowGuests,
+		MaxParticipants:     s.MaxParticipants,
+		Locked:              s.Locked,
+		SummarizerAllowed:   s.SummarizerAllowed,
+		DefaultRole:         s.DefaultRole,
 	}
 }
+
+// AddInviteesRequest represents the request to invite emails to a meeting
+type AddInviteesRequest struct {
+	Emails []string `json:"emails" validate:"required"`
+}
+
+// SetInviteeRoleRequest sets the role an invitee joins with; an empty role restores the meeting default
+type SetInviteeRoleRequest struct {
+	Role models.ParticipantRole `json:"role"`
+}
+
+// RSVPRequest represents an invitee's answer to a meeting invitation
+type RSVPRequest struct {
+	Response models.RSVPStatus `json:"response" validate:"required"`
+}
+
+// AddCohostRequest represents the request to make a registered user a co-host
+type AddCohostRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// AttendanceReport lists who attended a meeting and for how long
+type AttendanceReport struct {
+	MeetingID uint             `json:"meeting_id"`
+	Attendees []AttendeeReport `json:"attendees"`
+}
+
+// AttendeeReport sums up the time one person spent in a meeting. Signed-in users
+// are grouped by account across reconnects; guests by LiveKit identity.
+type AttendeeReport struct {
+	UserID        *uint                `json:"user_id,omitempty"`
+	Identity      string               `json:"identity"`
+	Name          string               `json:"name"`
+	FirstJoinedAt time.Time            `json:"first_joined_at"`
+	LastLeftAt    *time.Time           `json:"last_left_at,omitempty"`
+	TotalSeconds  int64                `json:"total_seconds"`
+	Present       bool                 `json:"present"`
+	Intervals     []AttendanceInterval `json:"intervals"`
+}
+
+// AttendanceInterval is one stay in the meeting room; LeftAt is nil while still present
+type AttendanceInterval struct {
+	JoinedAt time.Time  `json:"joined_at"`
+	LeftAt   *time.Time `json:"left_at,omitempty"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+// CreatePollRequest represents a host creating a poll for a meeting
+type CreatePollRequest struct {
+	Question       string   `json:"question" validate:"required"`
+	Options        []string `json:"options" validate:"required,min=2,max=10"`
+	MultipleChoice bool     `json:"multiple_choice"`
+	Anonymous      bool     `json:"anonymous"`
+}
+
+// PollVoteRequest represents a participant voting in a poll with the LiveKit
+// token they joined with. A vote replaces the participant's earlier one.
+type PollVoteRequest struct {
+	Token     string `json:"token" validate:"required"`
+	OptionIDs []uint `json:"option_ids" validate:"required,min=1"`
+}
+
+// PollVoter is a participant who chose an option of a named poll
+type PollVoter struct {
+	Identity string `json:"identity"`
+	Name     string `json:"name"`
+	UserID   *uint  `json:"user_id,omitempty"`
+}
+
+// PollOptionResult is an option of a poll with the votes it received
+type PollOptionResult struct {
+	ID    uint   `json:"id"`
+	Text  string `json:"text"`
+	Votes int    `json:"votes"`
+	// Voters is only set for hosts, and never for anonymous polls
+	Voters []PollVoter `json:"voters,omitempty"`
+}
+
+// PollResponse is a poll with its results
+type PollResponse struct {
+	ID             uint               `json:"id"`
+	MeetingID      uint               `json:"meeting_id"`
+	Question       string             `json:"question"`
+	MultipleChoice bool               `json:"multiple_choice"`
+	Anonymous      bool               `json:"anonymous"`
+	Status         string             `json:"status"`
+	OpenedAt       *time.Time         `json:"opened_at,omitempty"`
+	ClosedAt       *time.Time         `json:"closed_at,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	TotalVoters    int                `json:"total_voters"`
+	Options        []PollOptionResult `json:"options"`
+	// MyVotes are the options the requesting participant chose
+	MyVotes []uint `json:"my_votes,omitempty"`
+}
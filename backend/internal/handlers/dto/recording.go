@@ -0,0 +1,32 @@
+package dto
+
+import "mini-meeting/internal/models"
+
+// StartRecordingRequest represents a host starting a recording. Type is
+// "room_composite" (the default), which records the room as one file in
+// Layout, or "track", which records the tracks of ParticipantIdentity, or the
+// single track TrackSID, as they were published. RoomName selects a breakout
+// room of the meeting; empty means the main room.
+type StartRecordingRequest struct {
+	Type                string `json:"type,omitempty"`
+	RoomName            string `json:"room_name,omitempty"`
+	AudioOnly           bool   `json:"audio_only"`
+	Layout              string `json:"layout,omitempty"`
+	ParticipantIdentity string `json:"participant_identity,omitempty"`
+	TrackSID            string `json:"track_sid,omitempty"`
+}
+
+// StopRecordingRequest represents a host stopping a recording, or every active
+// recording of the meeting when RecordingID is empty
+type StopRecordingRequest struct {
+	RecordingID uint `json:"recording_id,omitempty"`
+}
+
+// RecordingDownload is where the file of a recording can be fetched: a file on
+// this server, or a presigned URL of the bucket it was uploaded to
+type RecordingDownload struct {
+	Recording *models.Recording
+	FileName  string
+	LocalPath string
+	URL       string
+}
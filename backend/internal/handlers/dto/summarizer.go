@@ -14,10 +14,12 @@ type PaginatedSessionsResponse struct {
 }
 
 type SessionsList struct {
-	ID        uint                           `json:"id"`
-	Status    models.SummarizerSessionStatus `json:"status"`
-	Error     *string                        `json:"error"`
-	StartedAt time.Time                      `json:"started_at"`
+	ID              uint                           `json:"id"`
+	Status          models.SummarizerSessionStatus `json:"status"`
+	Error           *string                        `json:"error"`
+	StartedAt       time.Time                      `json:"started_at"`
+	OccurrenceStart *time.Time                     `json:"occurrence_start,omitempty"`
+	RoomName        string                         `json:"room_name,omitempty"`
 }
 
 type SessionResponse struct {
@@ -28,4 +30,24 @@ type SessionResponse struct {
 	Summary    *string                        `json:"summary"`
 	StartedAt  time.Time                      `json:"started_at"`
 	EndedAt    *time.Time                     `json:"ended_at,omitempty"`
+
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty"`
+	RoomName        string     `json:"room_name,omitempty"`
+
+	Consents []models.SummarizerConsent `json:"consents"`
+}
+
+// SummarizerConsentRequest is a participant's answer to being transcribed.
+// Identity is the caller's LiveKit identity in the meeting.
+type SummarizerConsentRequest struct {
+	Identity string `json:"identity" validate:"required"`
+	Consent  *bool  `json:"consent" validate:"required"`
+}
+
+// ImportSessionRequest creates a session from a recording of a meeting: either
+// a file uploaded with the request (multipart field "file") or a completed
+// recording of the meeting
+type ImportSessionRequest struct {
+	MeetingID   uint `json:"meeting_id" form:"meeting_id" validate:"required"`
+	RecordingID uint `json:"recording_id,omitempty" form:"recording_id"`
 }
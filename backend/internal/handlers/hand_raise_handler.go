@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HandRaiseHandler struct {
+	service *services.HandRaiseService
+}
+
+func NewHandRaiseHandler(service *services.HandRaiseService) *HandRaiseHandler {
+	return &HandRaiseHandler{service: service}
+}
+
+// handRaiseErrorResponse maps hand raise service errors to HTTP status codes
+func handRaiseErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid token"):
+		statusCode = fiber.StatusUnauthorized
+	case strings.HasSuffix(err.Error(), "not found"):
+		statusCode = fiber.StatusNotFound
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		statusCode = fiber.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "):
+		statusCode = fiber.StatusBadRequest
+	case strings.HasPrefix(err.Error(), "rate limited:"):
+		statusCode = fiber.StatusTooManyRequests
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// RaiseHand puts the caller in their room's speaker queue (public, authenticated by LiveKit token)
+// POST /api/v1/livekit/hands/raise
+func (h *HandRaiseHandler) RaiseHand(c *fiber.Ctx) error {
+	var req dto.ParticipantTokenRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token is required",
+		})
+	}
+
+	hands, err := h.service.RaiseHand(req.Token)
+	if err != nil {
+		return handRaiseErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": hands,
+	})
+}
+
+// LowerOwnHand takes the caller out of their room's speaker queue (public, authenticated by LiveKit token)
+// POST /api/v1/livekit/hands/lower
+func (h *HandRaiseHandler) LowerOwnHand(c *fiber.Ctx) error {
+	var req dto.ParticipantTokenRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token is required",
+		})
+	}
+
+	if err := h.service.LowerOwnHand(req.Token); err != nil {
+		return handRaiseErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// React sends a reaction to the caller's room (public, authenticated by LiveKit token)
+// POST /api/v1/livekit/reactions
+func (h *HandRaiseHandler) React(c *fiber.Ctx) error {
+	var req dto.ReactionRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" || req.Reaction == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token and reaction are required",
+		})
+	}
+
+	if err := h.service.React(req.Token, req.Reaction); err != nil {
+		return handRaiseErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetQueue lists the raised hands of a room, longest waiting first (hosts only)
+// GET /api/v1/livekit/hands?meeting_code=...&room_name=...
+func (h *HandRaiseHandler) GetQueue(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingCode := c.Query("meeting_code")
+	if meetingCode == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "meeting_code is required",
+		})
+	}
+
+	hands, err := h.service.GetQueue(meetingCode, c.Query("room_name"), userID)
+	if err != nil {
+		return handRaiseErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": hands,
+	})
+}
+
+// LowerHand lowers the hand of a participant, or every hand of the room (hosts only)
+// POST /api/v1/livekit/hands/lower-participant
+func (h *HandRaiseHandler) LowerHand(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.LowerHandRequest
+	if err := c.BodyParser(&req); err != nil || req.MeetingCode == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "meeting_code is required",
+		})
+	}
+
+	lowered, err := h.service.LowerHand(req.MeetingCode, req.RoomName, userID, req.ParticipantIdentity)
+	if err != nil {
+		return handRaiseErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": fiber.Map{
+			"lowered": lowered,
+		},
+	})
+}
+
+// CallOn calls on the next raised hand, or on a given participant (hosts only)
+// POST /api/v1/livekit/hands/call-on
+func (h *HandRaiseHandler) CallOn(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.CallOnRequest
+	if err := c.BodyParser(&req); err != nil || req.MeetingCode == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "meeting_code is required",
+		})
+	}
+
+	call, err := h.service.CallOn(req.MeetingCode, req.RoomName, userID, req.ParticipantIdentity)
+	if err != nil {
+		return handRaiseErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": call,
+	})
+}
+
+// GetSpeakerCalls reports who was called on in a meeting and how long they waited (hosts only)
+// GET /api/v1/meetings/:id/speaker-calls
+func (h *HandRaiseHandler) GetSpeakerCalls(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	report, err := h.service.GetSpeakerCalls(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": report,
+	})
+}
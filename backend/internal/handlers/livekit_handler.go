@@ -1,17 +1,26 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 
+	"mini-meeting/internal/cache"
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
 	"mini-meeting/internal/services"
 	"mini-meeting/pkg/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/livekit/protocol/livekit"
 )
 
+// moderationTopic is the data message topic of requests hosts send to participants
+const moderationTopic = "moderation"
+
 type LiveKitHandler struct {
 	livekitService    *services.LiveKitService
 	meetingService    *services.MeetingService
@@ -71,15 +80,29 @@ func (h *LiveKitHandler) GenerateToken(c *fiber.Ctx) error {
 		})
 	}
 
+	// A ticket from the lobby binds the token to the identity the lobby admitted.
+	// It is only redeemed once every check below has passed, so that a refused
+	// join does not use it up.
+	var ticket *cache.AdmissionTicket
+	if req.Ticket != "" {
+		ticket, err = cache.GetAdmissionTicket(req.Ticket)
+		if err != nil || !ticket.Admits(req.MeetingCode, userID) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Invalid or expired admission ticket",
+			})
+		}
+	}
+
 	var userName string
 	var identity string
 	var userRole string
 	var metadata string
+	var user *models.User
 
 	// Handle authenticated users
 	if userID > 0 {
 		// Get user details
-		user, err := h.userService.GetUserByID(userID)
+		user, err = h.userService.GetUserByID(userID)
 		if err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "User not found",
@@ -87,11 +110,8 @@ func (h *LiveKitHandler) GenerateToken(c *fiber.Ctx) error {
 		}
 
 		// Determine user role for the meeting
-		// Creator is admin, others are regular users
-		userRole = "user"
-		if meeting.CreatorID == userID {
-			userRole = "admin"
-		}
+		// Creator is admin, co-hosts are cohost, others are regular users
+		userRole = h.meetingService.ParticipantRole(meeting, user)
 
 		// Use custom user name if provided, otherwise use user's name
 		userName = user.Name
@@ -100,7 +120,7 @@ func (h *LiveKitHandler) GenerateToken(c *fiber.Ctx) error {
 		}
 
 		identity = fmt.Sprintf("%s_%d", userName, userID)
-		metadata = fmt.Sprintf(`{"name":"%s","avatar":"%s","role":"%s"}`, userName, user.AvatarURL, userRole)
+		metadata = services.ParticipantMetadata(userName, user.AvatarURL, userRole)
 	} else {
 		// Handle guest users
 		if req.UserName == "" {
@@ -110,10 +130,60 @@ func (h *LiveKitHandler) GenerateToken(c *fiber.Ctx) error {
 		}
 
 		userName = req.UserName
-		userRole = "guest"
+		userRole = h.meetingService.ParticipantRole(meeting, nil)
 		// Generate a unique guest identity based on timestamp and random component
 		identity = fmt.Sprintf("%s_%d", userName, c.Context().ConnID())
-		metadata = fmt.Sprintf(`{"name":"%s","avatar":"","role":"%s"}`, userName, userRole)
+		metadata = services.ParticipantMetadata(userName, "", userRole)
+	}
+
+	if ticket != nil {
+		identity = ticket.Identity
+	}
+
+	// Refuse joins to cancelled meetings and, when enforced, outside the join window
+	if err := h.meetingService.CheckJoinWindow(meeting, userID); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Refuse participants blocked from this meeting
+	participant := newParticipant(identity, userName, user, req.Fingerprint, userRole)
+	if h.meetingService.IsBanned(meeting.ID, participant.UserID, participant.Email, participant.Fingerprint, identity) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You have been blocked from this meeting",
+		})
+	}
+
+	// Participants with a ticket already passed these checks in the lobby
+	if ticket == nil {
+		if err := h.meetingService.CheckAccess(meeting, user, req.Passcode); err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	// Meetings that require the lobby only give tokens here to participants the
+	// admission policy admits or who bring a ticket from the lobby
+	if ticket == nil && h.meetingService.RequiresAdmissionTicket(meeting, user) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":          "This meeting requires approval in the lobby",
+			"lobby_required": true,
+		})
+	}
+
+	if err := openRoom(h.livekitService, h.meetingService, meeting); err != nil {
+		return openRoomErrorResponse(c, meeting, err)
+	}
+
+	// Each ticket gives a single token, even to concurrent requests
+	if ticket != nil {
+		if _, err := cache.RedeemAdmissionTicket(req.Ticket); err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Invalid or expired admission ticket",
+			})
+		}
 	}
 
 	// Generate token
@@ -131,6 +201,9 @@ func (h *LiveKitHandler) GenerateToken(c *fiber.Ctx) error {
 		})
 	}
 
+	rememberParticipant(req.MeetingCode, participant)
+	h.meetingService.MarkLive(meeting)
+
 	response := dto.GenerateTokenResponse{
 		Token:    token,
 		URL:      h.livekitService.GetURL(),
@@ -142,7 +215,9 @@ func (h *LiveKitHandler) GenerateToken(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
-// RemoveParticipant removes a participant from a meeting (admin only)
+// RemoveParticipant removes a participant from a meeting (hosts only).
+// With block set, the participant is also added to the meeting's block list so
+// they cannot rejoin through /livekit/token or the lobby.
 func (h *LiveKitHandler) RemoveParticipant(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(uint)
 	if !ok {
@@ -158,7 +233,7 @@ func (h *LiveKitHandler) RemoveParticipant(c *fiber.Ctx) error {
 		})
 	}
 
-	// Verify meeting exists and user is the creator
+	// Verify meeting exists and user is a host
 	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -166,16 +241,64 @@ func (h *LiveKitHandler) RemoveParticipant(c *fiber.Ctx) error {
 		})
 	}
 
-	// Only meeting creator can remove participants
-	if meeting.CreatorID != userID {
+	// Only meeting hosts can remove participants
+	if !h.meetingService.IsHost(meeting, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting hosts can remove participants",
+		})
+	}
+
+	// Co-hosts cannot remove the meeting creator
+	if p, err := cache.GetParticipant(req.MeetingCode, req.ParticipantIdentity); err == nil &&
+		p.UserID == meeting.CreatorID && userID != meeting.CreatorID {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Only meeting creator can remove participants",
+			"error": "The meeting creator cannot be removed",
 		})
 	}
 
+	if req.Block {
+		ban := &models.MeetingBan{
+			Identity: req.ParticipantIdentity,
+			Name:     req.ParticipantIdentity,
+			Reason:   req.Reason,
+		}
+		// Block the person behind the identity, not just the identity itself.
+		// Guests have no account, so their ban only holds while they keep the
+		// same client fingerprint.
+		if p, err := cache.GetParticipant(req.MeetingCode, req.ParticipantIdentity); err == nil {
+			ban.Name = p.Name
+			ban.Email = p.Email
+			ban.Fingerprint = p.Fingerprint
+			if p.UserID > 0 {
+				ban.UserID = &p.UserID
+			}
+		}
+
+		if err := h.meetingService.BanParticipant(meeting, userID, ban); err != nil {
+			statusCode := fiber.StatusInternalServerError
+			if strings.HasPrefix(err.Error(), "invalid ban") {
+				statusCode = fiber.StatusBadRequest
+			}
+			return c.Status(statusCode).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
 	// Remove participant
 	err = h.livekitService.RemoveParticipant(req.MeetingCode, req.ParticipantIdentity)
+	if err == nil || req.Block {
+		// Their join token is still valid, so stop it from voting or raising hands
+		if err := cache.DeleteParticipant(req.MeetingCode, req.ParticipantIdentity); err != nil {
+			log.Printf("[LiveKit] %v", err)
+		}
+	}
 	if err != nil {
+		// The ban is already in place, so a participant who already left is not an error
+		if req.Block {
+			log.Printf("[LiveKit] Blocked %s but failed to remove them from %s: %v", req.ParticipantIdentity, req.MeetingCode, err)
+			return c.SendStatus(fiber.StatusNoContent)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to remove participant",
 		})
@@ -184,6 +307,72 @@ func (h *LiveKitHandler) RemoveParticipant(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// newParticipant describes who a LiveKit identity is issued to and with which role.
+// user is nil for guests.
+func newParticipant(identity, name string, user *models.User, fingerprint string, role string) *cache.Participant {
+	p := &cache.Participant{
+		Identity:    identity,
+		Name:        name,
+		Fingerprint: fingerprint,
+		Role:        role,
+	}
+	if user != nil {
+		p.UserID = user.ID
+		p.Email = user.Email
+	}
+	return p
+}
+
+// errMeetingFull is returned by openRoom when the meeting has no seat left
+var errMeetingFull = errors.New("meeting is full")
+
+// openRoom creates the LiveKit room of a meeting with the meeting's participant
+// limit and refuses one more participant when the room is full. When the room
+// or its participants cannot be checked, nobody is let in.
+func openRoom(livekitService *services.LiveKitService, meetingService *services.MeetingService, meeting *models.Meeting) error {
+	settings, err := meetingService.GetSettings(meeting.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := livekitService.CreateRoom(meeting.MeetingCode, settings.MaxParticipants); err != nil {
+		return err
+	}
+
+	if settings.MaxParticipants > 0 {
+		attendees, err := livekitService.CountAttendees(meeting.MeetingCode)
+		if err != nil {
+			return err
+		}
+		if attendees >= settings.MaxParticipants {
+			return errMeetingFull
+		}
+	}
+
+	return nil
+}
+
+// openRoomErrorResponse answers a join refused by openRoom
+func openRoomErrorResponse(c *fiber.Ctx, meeting *models.Meeting, err error) error {
+	if errors.Is(err, errMeetingFull) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	log.Printf("[LiveKit] Failed to open room %s: %v", meeting.MeetingCode, err)
+	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+		"error": "Failed to open the meeting room",
+	})
+}
+
+// rememberParticipant records who a LiveKit identity was issued to, so that
+// moderation actions on the identity (e.g. remove and block) reach the person.
+func rememberParticipant(meetingCode string, p *cache.Participant) {
+	if err := cache.StoreParticipant(meetingCode, p); err != nil {
+		log.Printf("[LiveKit] Failed to remember participant %s: %v", p.Identity, err)
+	}
+}
+
 // ListParticipants lists all participants in a meeting
 func (h *LiveKitHandler) ListParticipants(c *fiber.Ctx) error {
 	meetingCode := c.Query("meeting_code")
@@ -257,7 +446,7 @@ func (h *LiveKitHandler) GetParticipantCount(c *fiber.Ctx) error {
 	})
 }
 
-// MuteParticipant mutes/unmutes a specific track for a participant (admin only)
+// MuteParticipant mutes/unmutes a specific track for a participant (hosts only)
 func (h *LiveKitHandler) MuteParticipant(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(uint)
 	if !ok {
@@ -273,7 +462,7 @@ func (h *LiveKitHandler) MuteParticipant(c *fiber.Ctx) error {
 		})
 	}
 
-	// Verify meeting exists and user is the creator
+	// Verify meeting exists and user is a host
 	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -281,10 +470,10 @@ func (h *LiveKitHandler) MuteParticipant(c *fiber.Ctx) error {
 		})
 	}
 
-	// Only meeting creator can mute participants
-	if meeting.CreatorID != userID {
+	// Only meeting hosts can mute participants
+	if !h.meetingService.IsHost(meeting, userID) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Only meeting creator can mute participants",
+			"error": "Only meeting hosts can mute participants",
 		})
 	}
 
@@ -299,7 +488,212 @@ func (h *LiveKitHandler) MuteParticipant(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
-// EndMeeting ends a meeting for all participants (admin only)
+// MuteAll mutes the microphones of every participant except hosts (hosts only)
+func (h *LiveKitHandler) MuteAll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.MuteAllRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Meeting not found",
+		})
+	}
+
+	if !h.meetingService.IsHost(meeting, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting hosts can mute participants",
+		})
+	}
+
+	sources := []livekit.TrackSource{livekit.TrackSource_MICROPHONE}
+	muted, err := h.livekitService.MuteAll(req.MeetingCode, req.MeetingCode, sources, req.BlockUnmute)
+	if err != nil {
+		log.Printf("[LiveKit] Failed to mute all in %s: %v", req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to mute participants",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"muted_tracks": muted,
+	})
+}
+
+// DisableVideo stops the cameras and screen shares of every participant except hosts (hosts only)
+func (h *LiveKitHandler) DisableVideo(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.DisableVideoRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	sources := []livekit.TrackSource{
+		livekit.TrackSource_CAMERA,
+		livekit.TrackSource_SCREEN_SHARE,
+		livekit.TrackSource_SCREEN_SHARE_AUDIO,
+	}
+	if len(req.Sources) > 0 {
+		sources = sources[:0]
+		for _, name := range req.Sources {
+			source, ok := livekit.TrackSource_value[strings.ToUpper(name)]
+			if !ok || livekit.TrackSource(source) == livekit.TrackSource_UNKNOWN {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Invalid source: " + name,
+				})
+			}
+			sources = append(sources, livekit.TrackSource(source))
+		}
+	}
+
+	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Meeting not found",
+		})
+	}
+
+	if !h.meetingService.IsHost(meeting, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting hosts can disable video",
+		})
+	}
+
+	muted, err := h.livekitService.MuteAll(req.MeetingCode, req.MeetingCode, sources, req.Block)
+	if err != nil {
+		log.Printf("[LiveKit] Failed to disable video in %s: %v", req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to disable video",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"muted_tracks": muted,
+	})
+}
+
+// AllowPublish lifts the blocks set by MuteAll and DisableVideo for one
+// participant or everyone (hosts only)
+func (h *LiveKitHandler) AllowPublish(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.AllowPublishRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Meeting not found",
+		})
+	}
+
+	if !h.meetingService.IsHost(meeting, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting hosts can change participant permissions",
+		})
+	}
+
+	if err := h.livekitService.AllowSources(req.MeetingCode, req.MeetingCode, req.ParticipantIdentity); err != nil {
+		log.Printf("[LiveKit] Failed to allow publishing in %s: %v", req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update participant permissions",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AskToUnmute lets a muted participant unmute again and asks them to, through a
+// data message on the "moderation" topic (hosts only). LiveKit does not let hosts
+// unmute someone else's microphone.
+func (h *LiveKitHandler) AskToUnmute(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.AskToUnmuteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.ParticipantIdentity == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "participant_identity is required",
+		})
+	}
+
+	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Meeting not found",
+		})
+	}
+
+	if !h.meetingService.IsHost(meeting, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting hosts can ask participants to unmute",
+		})
+	}
+
+	if err := h.livekitService.AllowMicrophone(req.MeetingCode, req.MeetingCode, req.ParticipantIdentity); err != nil {
+		log.Printf("[LiveKit] Failed to allow %s to unmute in %s: %v", req.ParticipantIdentity, req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update participant permissions",
+		})
+	}
+
+	host := ""
+	if user, err := h.userService.GetUserByID(userID); err == nil {
+		host = user.Name
+	}
+	payload, _ := json.Marshal(fiber.Map{
+		"type": "ask_to_unmute",
+		"from": host,
+	})
+	if err := h.livekitService.SendData(req.MeetingCode, []string{req.ParticipantIdentity}, moderationTopic, payload); err != nil {
+		log.Printf("[LiveKit] Failed to ask %s to unmute in %s: %v", req.ParticipantIdentity, req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to ask participant to unmute",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// EndMeeting ends a meeting for all participants (hosts only)
 func (h *LiveKitHandler) EndMeeting(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(uint)
 	if !ok {
@@ -315,7 +709,7 @@ func (h *LiveKitHandler) EndMeeting(c *fiber.Ctx) error {
 		})
 	}
 
-	// Verify meeting exists and user is the creator
+	// Verify meeting exists and user is a host
 	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -323,10 +717,10 @@ func (h *LiveKitHandler) EndMeeting(c *fiber.Ctx) error {
 		})
 	}
 
-	// Only meeting creator can end the meeting
-	if meeting.CreatorID != userID {
+	// Only meeting hosts can end the meeting
+	if !h.meetingService.IsHost(meeting, userID) {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "Only meeting creator can end the meeting",
+			"error": "Only meeting hosts can end the meeting",
 		})
 	}
 
@@ -347,5 +741,146 @@ func (h *LiveKitHandler) EndMeeting(c *fiber.Ctx) error {
 		})
 	}
 
+	h.meetingService.MarkEnded(meeting)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetRole changes the role of an in-call participant live (hosts only). Their
+// LiveKit permissions follow the role without them having to rejoin. Signed-in
+// participants keep the role when they rejoin the meeting; guests keep it until
+// they leave. Host and co-host rights go through SetCohost instead.
+func (h *LiveKitHandler) SetRole(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SetRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	role := models.ParticipantRole(req.Role)
+	if !role.IsAssignable() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Role must be presenter, user, guest, attendee or viewer",
+		})
+	}
+
+	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Meeting not found",
+		})
+	}
+
+	if !h.meetingService.IsHost(meeting, userID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting hosts can change participant roles",
+		})
+	}
+
+	// Only identities issued by this server can be given a role, so that a host
+	// is never mistaken for someone else
+	p, err := cache.GetParticipant(req.MeetingCode, req.ParticipantIdentity)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Participant not found",
+		})
+	}
+
+	// Hosts keep their role; co-hosts are demoted through SetCohost
+	if p.UserID > 0 && h.meetingService.IsHost(meeting, p.UserID) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Cannot change the role of a meeting host",
+		})
+	}
+
+	if p.UserID > 0 {
+		if err := h.meetingService.AssignRole(meeting.ID, p.UserID, role); err != nil {
+			log.Printf("[LiveKit] Failed to store role of %s in %s: %v", req.ParticipantIdentity, req.MeetingCode, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update participant role",
+			})
+		}
+	}
+
+	if err := h.livekitService.UpdateParticipantRole(req.MeetingCode, req.ParticipantIdentity, string(role)); err != nil {
+		log.Printf("[LiveKit] Failed to update role of %s in %s: %v", req.ParticipantIdentity, req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update participant permissions",
+		})
+	}
+	p.Role = string(role)
+	rememberParticipant(req.MeetingCode, p)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetCohost promotes an in-call participant to co-host, or demotes them, live (creator only).
+// The co-host assignment is stored for the meeting and the participant's LiveKit
+// permissions and metadata role are updated without them having to rejoin.
+func (h *LiveKitHandler) SetCohost(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.SetCohostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Verify meeting exists and user is the creator
+	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Meeting not found",
+		})
+	}
+
+	if meeting.CreatorID != userID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only meeting creator can manage co-hosts",
+		})
+	}
+
+	// Co-host rights are tied to an account, so guests cannot be promoted
+	p, err := cache.GetParticipant(req.MeetingCode, req.ParticipantIdentity)
+	if err != nil || p.UserID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Only signed-in participants can be made co-hosts",
+		})
+	}
+
+	role := "user"
+	if req.Cohost {
+		role = "cohost"
+		_, err = h.meetingService.PromoteToCohost(meeting, userID, p.UserID)
+	} else {
+		err = h.meetingService.RemoveCohost(meeting.ID, userID, p.UserID)
+	}
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	if err := h.livekitService.UpdateParticipantRole(req.MeetingCode, req.ParticipantIdentity, role); err != nil {
+		log.Printf("[LiveKit] Failed to update role of %s in %s: %v", req.ParticipantIdentity, req.MeetingCode, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update participant permissions",
+		})
+	}
+	p.Role = role
+	rememberParticipant(req.MeetingCode, p)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"mini-meeting/internal/cache"
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
 	"mini-meeting/internal/services"
 	"mini-meeting/pkg/utils"
 
@@ -37,7 +39,8 @@ func NewLobbyHandler(
 }
 
 // RequestToJoin handles a user's request to join a meeting.
-// If the user is the meeting creator (admin), they are auto-approved with a token.
+// If the user is the meeting creator (admin) or is allowed by the meeting's
+// admission policy, they are auto-approved with a token.
 // Otherwise, a pending request is created in the lobby cache.
 // POST /api/v1/lobby/request
 func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
@@ -54,6 +57,13 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 		})
 	}
 
+	req.Message = strings.TrimSpace(req.Message)
+	if utf8.RuneCountInString(req.Message) > cache.LobbyMessageMaxLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("message must be at most %d characters", cache.LobbyMessageMaxLength),
+		})
+	}
+
 	// Verify meeting exists
 	meeting, err := h.meetingService.GetMeetingByCode(req.MeetingCode)
 	if err != nil {
@@ -77,19 +87,17 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 
 	// Build identity, name, role, metadata
 	var userName, identity, userRole, metadata, avatarURL string
+	var user *models.User
 
 	if userID > 0 {
-		user, err := h.userService.GetUserByID(userID)
+		user, err = h.userService.GetUserByID(userID)
 		if err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error": "User not found",
 			})
 		}
 
-		userRole = "user"
-		if meeting.CreatorID == userID {
-			userRole = "admin"
-		}
+		userRole = h.meetingService.ParticipantRole(meeting, user)
 
 		userName = user.Name
 		if req.UserName != "" {
@@ -98,7 +106,7 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 		avatarURL = user.AvatarURL
 
 		identity = fmt.Sprintf("%s_%d", userName, userID)
-		metadata = fmt.Sprintf(`{"name":"%s","avatar":"%s","role":"%s"}`, userName, user.AvatarURL, userRole)
+		metadata = services.ParticipantMetadata(userName, user.AvatarURL, userRole)
 	} else {
 		if req.UserName == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -107,13 +115,41 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 		}
 
 		userName = req.UserName
-		userRole = "guest"
+		userRole = h.meetingService.ParticipantRole(meeting, nil)
 		identity = fmt.Sprintf("%s_%d", userName, c.Context().ConnID())
-		metadata = fmt.Sprintf(`{"name":"%s","avatar":"","role":"%s"}`, userName, userRole)
+		metadata = services.ParticipantMetadata(userName, "", userRole)
+	}
+
+	// Refuse joins to cancelled meetings and, when enforced, outside the join window
+	if err := h.meetingService.CheckJoinWindow(meeting, userID); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Refuse participants blocked from this meeting
+	participant := newParticipant(identity, userName, user, req.Fingerprint, userRole)
+	if h.meetingService.IsBanned(meeting.ID, participant.UserID, participant.Email, participant.Fingerprint, identity) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You have been blocked from this meeting",
+		})
+	}
+
+	// Refuse requests the meeting's access settings rule out before they reach the host
+	if err := h.meetingService.CheckAccess(meeting, user, req.Passcode); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
-	// If admin (meeting creator), auto-approve and return token immediately
-	if userRole == "admin" {
+	// If a host (creator or co-host) or admitted by the meeting's admission policy,
+	// auto-approve and return token immediately. Visitors who wait in the lobby
+	// take a seat only once a host admits them.
+	if h.meetingService.CanAutoAdmit(meeting, user) {
+		if err := openRoom(h.livekitService, h.meetingService, meeting); err != nil {
+			return openRoomErrorResponse(c, meeting, err)
+		}
+
 		token, err := h.livekitService.CreateJoinToken(
 			req.MeetingCode, identity, userName, userRole, metadata,
 		)
@@ -123,6 +159,9 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 			})
 		}
 
+		rememberParticipant(req.MeetingCode, participant)
+		h.meetingService.MarkLive(meeting)
+
 		return c.JSON(dto.LobbyJoinResponse{
 			RequestID: "",
 			Status:    "auto_approved",
@@ -134,8 +173,22 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 		})
 	}
 
-	// Non-admin: create a pending lobby request
+	// Everyone else: create a pending lobby request that expires after the meeting's lobby timeout
 	requestID := uuid.New().String()
+	now := time.Now()
+
+	timeout := cache.LobbyExpiration
+	if settings, err := h.meetingService.GetSettings(meeting.ID); err == nil && settings.LobbyTimeoutSeconds > 0 {
+		timeout = time.Duration(settings.LobbyTimeoutSeconds) * time.Second
+	}
+
+	// The resume token lets the visitor re-attach to this request after a dropped connection
+	resumeToken, err := generateRandomState()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create lobby request",
+		})
+	}
 
 	lobbyReq := &cache.LobbyRequest{
 		ID:          requestID,
@@ -145,8 +198,12 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 		AvatarURL:   avatarURL,
 		Identity:    identity,
 		Role:        userRole,
+		Fingerprint: req.Fingerprint,
+		Message:     req.Message,
+		ResumeToken: resumeToken,
 		Status:      cache.LobbyStatusPending,
-		CreatedAt:   time.Now().Unix(),
+		CreatedAt:   now.Unix(),
+		ExpiresAt:   now.Add(timeout).Unix(),
 	}
 
 	if err := cache.StoreLobbyRequest(lobbyReq); err != nil {
@@ -159,8 +216,10 @@ func (h *LobbyHandler) RequestToJoin(c *fiber.Ctx) error {
 	NotifyAdminsOfNewRequest(lobbyReq)
 
 	return c.JSON(dto.LobbyJoinResponse{
-		RequestID: requestID,
-		Status:    "pending",
+		RequestID:   requestID,
+		Status:      "pending",
+		ExpiresAt:   lobbyReq.ExpiresAt,
+		ResumeToken: lobbyReq.ResumeToken,
 	})
 }
 
@@ -189,6 +248,8 @@ func (h *LobbyHandler) CancelRequest(c *fiber.Ctx) error {
 			"type":       "visitor_cancelled",
 			"request_id": requestID,
 		})
+
+		BroadcastQueuePositions(req.MeetingCode)
 	}
 
 	return c.JSON(fiber.Map{
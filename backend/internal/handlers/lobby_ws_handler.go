@@ -2,29 +2,48 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"time"
 
 	"mini-meeting/internal/cache"
 	"mini-meeting/internal/config"
+	"mini-meeting/internal/models"
 	"mini-meeting/internal/services"
 	"mini-meeting/pkg/utils"
 
 	"github.com/gofiber/contrib/websocket"
+	"github.com/google/uuid"
+)
+
+const (
+	// lobbyWSPongWait is how long a lobby socket may stay silent before it is considered dead
+	lobbyWSPongWait = 60 * time.Second
+
+	// lobbyWSPingInterval is how often the server pings lobby sockets (must be less than lobbyWSPongWait)
+	lobbyWSPingInterval = 25 * time.Second
+
+	// lobbyWSWriteWait is the time allowed to write a ping
+	lobbyWSWriteWait = 10 * time.Second
 )
 
 // WS message types
 const (
 	// Sent TO admin
-	WSTypePendingRequests = "pending_requests"
-	WSTypeNewRequest      = "new_request"
-	WSTypeRequestResolved = "request_resolved"
+	WSTypePendingRequests  = "pending_requests"
+	WSTypeNewRequest       = "new_request"
+	WSTypeRequestResolved  = "request_resolved"
+	WSTypeRequestsResolved = "requests_resolved"
 	WSTypeVisitorCancelled = "visitor_cancelled"
+	WSTypeRespondResult    = "respond_result"
 
 	// Sent TO visitor
-	WSTypeApproved = "approved"
-	WSTypeRejected = "rejected"
+	WSTypeApproved      = "approved"
+	WSTypeRejected      = "rejected"
+	WSTypeQueuePosition = "queue_position"
+
+	// Sent TO both admin and visitor
+	WSTypeTimedOut = "timed_out"
 
 	// Received FROM admin
 	WSTypeRespond = "respond"
@@ -32,9 +51,12 @@ const (
 
 // --- Message structs ---
 
+// WSPendingRequestsMsg is the snapshot sent to an admin on connect. LastEventID is the
+// latest admin event at snapshot time; pass it back as last_event_id when reconnecting.
 type WSPendingRequestsMsg struct {
-	Type     string                     `json:"type"`
-	Requests []WSPendingRequestEntry    `json:"requests"`
+	Type        string                  `json:"type"`
+	Requests    []WSPendingRequestEntry `json:"requests"`
+	LastEventID string                  `json:"last_event_id,omitempty"`
 }
 
 type WSPendingRequestEntry struct {
@@ -42,7 +64,21 @@ type WSPendingRequestEntry struct {
 	Name      string `json:"name"`
 	AvatarURL string `json:"avatar_url,omitempty"`
 	Role      string `json:"role"`
+	Message   string `json:"message,omitempty"`
 	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+func toPendingRequestEntry(req *cache.LobbyRequest) WSPendingRequestEntry {
+	return WSPendingRequestEntry{
+		RequestID: req.ID,
+		Name:      req.Name,
+		AvatarURL: req.AvatarURL,
+		Role:      req.Role,
+		Message:   req.Message,
+		CreatedAt: req.CreatedAt,
+		ExpiresAt: req.ExpiresAt,
+	}
 }
 
 type WSNewRequestMsg struct {
@@ -62,16 +98,66 @@ type WSApprovedMsg struct {
 	RoomCode string `json:"room_code"`
 	Identity string `json:"identity"`
 	UserName string `json:"user_name"`
+	// Ticket can be exchanged once at /livekit/token for a token as the same identity,
+	// for meetings that refuse direct tokens to participants who skipped the lobby
+	Ticket string `json:"ticket,omitempty"`
 }
 
 type WSRejectedMsg struct {
 	Type string `json:"type"`
 }
 
-type WSRespondMsg struct {
+// WSTimedOutMsg tells the visitor (and admins, with request_id) that a request waited too long
+type WSTimedOutMsg struct {
 	Type      string `json:"type"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// WSQueuePositionMsg tells a visitor where they are in the queue (1 = next).
+// EstimatedWaitSeconds is omitted until the host has resolved at least one request.
+type WSQueuePositionMsg struct {
+	Type                 string `json:"type"`
+	Position             int    `json:"position"`
+	Total                int    `json:"total"`
+	EstimatedWaitSeconds *int64 `json:"estimated_wait_seconds,omitempty"`
+}
+
+// Actions accepted in a respond message
+const (
+	WSActionApprove   = "approve"
+	WSActionReject    = "reject"
+	WSActionAdmitAll  = "admit_all"
+	WSActionRejectAll = "reject_all"
+)
+
+// WSRespondMsg resolves one request (request_id), a list of requests (request_ids),
+// or every pending request of the meeting (admit_all / reject_all).
+type WSRespondMsg struct {
+	Type       string   `json:"type"`
+	RequestID  string   `json:"request_id,omitempty"`
+	RequestIDs []string `json:"request_ids,omitempty"`
+	Action     string   `json:"action"` // "approve", "reject", "admit_all" or "reject_all"
+}
+
+// WSRequestsResolvedMsg is broadcast to admins once per bulk action
+type WSRequestsResolvedMsg struct {
+	Type     string   `json:"type"`
+	Approved []string `json:"approved"`
+	Rejected []string `json:"rejected"`
+}
+
+// WSRespondFailure describes a request that could not be resolved
+type WSRespondFailure struct {
 	RequestID string `json:"request_id"`
-	Action    string `json:"action"` // "approve" or "reject"
+	Reason    string `json:"reason"`
+}
+
+// WSRespondResultMsg is sent back to the admin who issued a respond message
+type WSRespondResultMsg struct {
+	Type     string             `json:"type"`
+	Action   string             `json:"action"`
+	Resolved int                `json:"resolved"`
+	Failed   []WSRespondFailure `json:"failed"`
 }
 
 // --- Handler ---
@@ -97,14 +183,44 @@ func NewLobbyWSHandler(
 	}
 }
 
+// keepAlive pings the socket periodically and drops it when pongs stop arriving,
+// so that half-open connections are detected. The returned function stops the pinger.
+func keepAlive(c *websocket.Conn) func() {
+	c.SetReadDeadline(time.Now().Add(lobbyWSPongWait))
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(lobbyWSPongWait))
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lobbyWSPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(lobbyWSWriteWait)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
 // HandleVisitor handles the WebSocket connection for a visitor waiting to join.
-// Query params: request_id, meeting_code
+// Query params: request_id, meeting_code, resume_token (required when reconnecting)
 // The visitor connects AFTER submitting a lobby request via POST /lobby/request.
-// They wait for push messages: "approved" or "rejected".
-// When the connection closes (e.g., tab closed), the request is automatically cleaned up.
+// They wait for push messages: "queue_position", then "approved", "rejected" or "timed_out".
+// When the connection closes, the visitor keeps their place for cache.LobbyReconnectGrace;
+// if they have not reconnected by then, the request is cleaned up.
 func (h *LobbyWSHandler) HandleVisitor(c *websocket.Conn) {
 	requestID := c.Query("request_id")
 	meetingCode := c.Query("meeting_code")
+	resumeToken := c.Query("resume_token")
 
 	if requestID == "" || meetingCode == "" {
 		log.Printf("[WS Visitor] Missing request_id or meeting_code")
@@ -114,52 +230,137 @@ func (h *LobbyWSHandler) HandleVisitor(c *websocket.Conn) {
 	}
 
 	// Verify the request exists
-	_, err := cache.GetLobbyRequest(requestID)
-	if err != nil {
+	lobbyReq, err := cache.GetLobbyRequest(requestID)
+	if err != nil || lobbyReq.MeetingCode != meetingCode {
 		log.Printf("[WS Visitor] Request not found: %s", requestID)
 		c.WriteJSON(map[string]string{"error": "Request not found or expired"})
 		c.Close()
 		return
 	}
 
-	// Register in hub
-	cache.Hub.RegisterVisitor(requestID, c)
+	// A request that already had a socket can only be resumed with its resume token
+	if resumeToken != "" && !lobbyReq.ValidResumeToken(resumeToken) {
+		log.Printf("[WS Visitor] Invalid resume token for request %s", requestID)
+		c.WriteJSON(map[string]string{"error": "Invalid resume token"})
+		c.Close()
+		return
+	}
+	if resumeToken == "" && cache.VisitorAttached(requestID) {
+		log.Printf("[WS Visitor] Missing resume token to reconnect request %s", requestID)
+		c.WriteJSON(map[string]string{"error": "resume_token is required to reconnect"})
+		c.Close()
+		return
+	}
 
-	// When this function returns (connection closes), clean up
+	// Register in hub and take over the request from any previous connection
+	connID := uuid.New().String()
+	lc := cache.Hub.RegisterVisitor(requestID, c)
+	if err := cache.AttachVisitor(lobbyReq, connID); err != nil {
+		log.Printf("[WS Visitor] %v", err)
+	}
+
+	stopKeepAlive := keepAlive(c)
+
+	// When this function returns (connection closes), give the visitor time to reconnect
 	defer func() {
-		cache.Hub.UnregisterVisitor(requestID)
+		stopKeepAlive()
+		cache.Hub.UnregisterVisitor(requestID, lc)
 
-		// Clean up the lobby request from cache
-		req, err := cache.GetLobbyRequest(requestID)
-		if err == nil && req.Status == cache.LobbyStatusPending {
-			cache.CleanupLobbyRequest(requestID)
+		time.AfterFunc(cache.LobbyReconnectGrace, func() {
+			releaseDisconnectedVisitor(meetingCode, requestID, connID)
+		})
+	}()
 
-			// Notify admins that this visitor cancelled (disconnected)
-			cache.Hub.NotifyAdmins(meetingCode, WSRequestResolvedMsg{
-				Type:      WSTypeVisitorCancelled,
-				RequestID: requestID,
-			})
-			log.Printf("[WS Visitor] Visitor disconnected, cleaned up request %s", requestID)
+	// The request may have been resolved while the visitor was away
+	switch lobbyReq.Status {
+	case cache.LobbyStatusApproved:
+		// Give back the token the visitor was admitted with. While it is still being
+		// issued it is delivered through the hub, so keep reading until the visitor leaves.
+		if lobbyReq.Token != "" {
+			lc.SendJSON(h.approvedMsg(lobbyReq))
 		}
-	}()
+	case cache.LobbyStatusRejected:
+		lc.SendJSON(WSRejectedMsg{Type: WSTypeRejected})
+		return
+	case cache.LobbyStatusTimedOut:
+		lc.SendJSON(WSTimedOutMsg{Type: WSTypeTimedOut})
+		return
+	default:
+		// Tell the visitor where they are in the queue
+		if pending, err := cache.GetPendingRequests(meetingCode); err == nil {
+			avgWait, hasAvg := cache.AverageLobbyWait(meetingCode)
+			for i, req := range pending {
+				if req.ID == requestID {
+					lc.SendJSON(queuePositionMsg(pending, i, avgWait, hasAvg))
+					break
+				}
+			}
+		}
+	}
 
 	// Read loop — visitor doesn't send meaningful messages, but we need
 	// to keep the connection alive and detect disconnection
 	for {
 		_, _, err := c.ReadMessage()
 		if err != nil {
-			// Connection closed (tab closed, network issue, etc.)
+			// Connection closed (tab closed, network issue, missed pongs, etc.)
 			log.Printf("[WS Visitor] Connection closed for request %s: %v", requestID, err)
 			break
 		}
 	}
 }
 
+// releaseDisconnectedVisitor runs once the reconnect grace period of a closed visitor
+// socket has passed. If the visitor has not reconnected and is still waiting, the
+// request is cleaned up and admins are told the visitor left.
+func releaseDisconnectedVisitor(meetingCode, requestID, connID string) {
+	detached, err := cache.DetachVisitor(requestID, connID)
+	if err != nil {
+		log.Printf("[WS Visitor] %v", err)
+		return
+	}
+	if !detached {
+		// The visitor reconnected with another socket
+		return
+	}
+
+	req, err := cache.GetLobbyRequest(requestID)
+	if err != nil || req.Status != cache.LobbyStatusPending {
+		return
+	}
+
+	cache.CleanupLobbyRequest(requestID)
+
+	// Notify admins that this visitor cancelled (disconnected)
+	cache.Hub.NotifyAdmins(meetingCode, WSRequestResolvedMsg{
+		Type:      WSTypeVisitorCancelled,
+		RequestID: requestID,
+	})
+	log.Printf("[WS Visitor] Visitor did not reconnect, cleaned up request %s", requestID)
+
+	BroadcastQueuePositions(meetingCode)
+}
+
+// cleanupAfterGrace removes resolved requests once a disconnected visitor can no
+// longer reconnect to pick up the outcome.
+func cleanupAfterGrace(requestIDs ...string) {
+	if len(requestIDs) == 0 {
+		return
+	}
+	time.AfterFunc(cache.LobbyReconnectGrace, func() {
+		for _, id := range requestIDs {
+			cache.CleanupLobbyRequest(id)
+		}
+	})
+}
+
 // HandleAdmin handles the WebSocket connection for a meeting admin.
-// Query params: meeting_code, token (JWT)
-// On connect: sends all current pending requests.
-// Receives: {type: "respond", request_id, action} messages.
-// Pushes: new_request, request_resolved, visitor_cancelled events.
+// Query params: meeting_code, token (JWT), last_event_id (optional, when reconnecting)
+// On connect: sends all current pending requests, then replays the events published
+// after last_event_id. Every pushed event carries an event_id; replayed events may
+// duplicate live ones, so clients should ignore event IDs they have already seen.
+// Receives: {type: "respond", request_id | request_ids, action} messages.
+// Pushes: new_request, request_resolved, requests_resolved, visitor_cancelled events.
 func (h *LobbyWSHandler) HandleAdmin(c *websocket.Conn) {
 	meetingCode := c.Query("meeting_code")
 	token := c.Query("token")
@@ -182,7 +383,7 @@ func (h *LobbyWSHandler) HandleAdmin(c *websocket.Conn) {
 
 	userID := claims.UserID
 
-	// Verify the user is the meeting creator
+	// Verify the user is a meeting host (creator or co-host)
 	meeting, err := h.meetingService.GetMeetingByCode(meetingCode)
 	if err != nil {
 		log.Printf("[WS Admin] Meeting not found: %s", meetingCode)
@@ -191,16 +392,21 @@ func (h *LobbyWSHandler) HandleAdmin(c *websocket.Conn) {
 		return
 	}
 
-	if meeting.CreatorID != userID {
-		log.Printf("[WS Admin] User %d is not the creator of meeting %s", userID, meetingCode)
-		c.WriteJSON(map[string]string{"error": "Only meeting creator can manage lobby"})
+	if !h.meetingService.IsHost(meeting, userID) {
+		log.Printf("[WS Admin] User %d is not a host of meeting %s", userID, meetingCode)
+		c.WriteJSON(map[string]string{"error": "Only meeting hosts can manage lobby"})
 		c.Close()
 		return
 	}
 
-	// Register admin connection
-	cache.Hub.RegisterAdmin(meetingCode, c)
-	defer cache.Hub.UnregisterAdmin(meetingCode, c)
+	// Register admin connection; from here on every write goes through lc
+	lc := cache.Hub.RegisterAdmin(meetingCode, c)
+	defer cache.Hub.UnregisterAdmin(meetingCode, lc)
+
+	stopKeepAlive := keepAlive(c)
+	defer stopKeepAlive()
+
+	lastEventID := cache.LastAdminEventID(meetingCode)
 
 	// Send current pending requests
 	pending, err := cache.GetPendingRequests(meetingCode)
@@ -210,20 +416,26 @@ func (h *LobbyWSHandler) HandleAdmin(c *websocket.Conn) {
 
 	entries := make([]WSPendingRequestEntry, 0, len(pending))
 	for _, req := range pending {
-		entries = append(entries, WSPendingRequestEntry{
-			RequestID: req.ID,
-			Name:      req.Name,
-			AvatarURL: req.AvatarURL,
-			Role:      req.Role,
-			CreatedAt: req.CreatedAt,
-		})
+		entries = append(entries, toPendingRequestEntry(req))
 	}
 
-	c.WriteJSON(WSPendingRequestsMsg{
-		Type:     WSTypePendingRequests,
-		Requests: entries,
+	lc.SendJSON(WSPendingRequestsMsg{
+		Type:        WSTypePendingRequests,
+		Requests:    entries,
+		LastEventID: lastEventID,
 	})
 
+	// Replay the events a reconnecting admin missed
+	if since := c.Query("last_event_id"); since != "" {
+		events, err := cache.AdminEventsSince(meetingCode, since)
+		if err != nil {
+			log.Printf("[WS Admin] Failed to replay events for meeting %s: %v", meetingCode, err)
+		}
+		for _, event := range events {
+			lc.Send(event)
+		}
+	}
+
 	// Read loop — process admin commands
 	for {
 		_, msgBytes, err := c.ReadMessage()
@@ -247,7 +459,7 @@ func (h *LobbyWSHandler) HandleAdmin(c *websocket.Conn) {
 				log.Printf("[WS Admin] Invalid respond message: %v", err)
 				continue
 			}
-			h.handleAdminRespond(meetingCode, &respondMsg)
+			h.handleAdminRespond(lc, meetingCode, &respondMsg)
 
 		default:
 			log.Printf("[WS Admin] Unknown message type: %s", baseMsg.Type)
@@ -256,49 +468,183 @@ func (h *LobbyWSHandler) HandleAdmin(c *websocket.Conn) {
 }
 
 // handleAdminRespond processes an approve/reject action from the admin.
-func (h *LobbyWSHandler) handleAdminRespond(meetingCode string, msg *WSRespondMsg) {
-	if msg.RequestID == "" || (msg.Action != "approve" && msg.Action != "reject") {
-		log.Printf("[WS Admin] Invalid respond params: request_id=%s action=%s", msg.RequestID, msg.Action)
+// Requests are claimed atomically in Redis, so when several admins respond at the
+// same time each request is resolved exactly once. A single-request action emits
+// request_resolved; a bulk action emits one consolidated requests_resolved event.
+// The issuing admin always receives a respond_result with per-request failures.
+func (h *LobbyWSHandler) handleAdminRespond(lc *cache.LobbyConn, meetingCode string, msg *WSRespondMsg) {
+	var status cache.LobbyRequestStatus
+	var requestIDs []string
+
+	switch msg.Action {
+	case WSActionApprove, WSActionReject:
+		requestIDs = msg.RequestIDs
+		if msg.RequestID != "" {
+			requestIDs = append(requestIDs, msg.RequestID)
+		}
+		if len(requestIDs) == 0 {
+			log.Printf("[WS Admin] Invalid respond params: no request_id for action %s", msg.Action)
+			return
+		}
+		status = cache.LobbyStatusApproved
+		if msg.Action == WSActionReject {
+			status = cache.LobbyStatusRejected
+		}
+	case WSActionAdmitAll:
+		status = cache.LobbyStatusApproved
+	case WSActionRejectAll:
+		status = cache.LobbyStatusRejected
+	default:
+		log.Printf("[WS Admin] Invalid respond action: %s", msg.Action)
 		return
 	}
 
-	// Get the lobby request
-	lobbyReq, err := cache.GetLobbyRequest(msg.RequestID)
+	resolved, failures, err := cache.ResolveLobbyRequests(meetingCode, requestIDs, status)
 	if err != nil {
-		log.Printf("[WS Admin] Request not found: %s", msg.RequestID)
+		log.Printf("[WS Admin] Failed to resolve requests for meeting %s: %v", meetingCode, err)
 		return
 	}
 
-	if lobbyReq.MeetingCode != meetingCode {
-		log.Printf("[WS Admin] Request %s does not belong to meeting %s", msg.RequestID, meetingCode)
-		return
+	approved := make([]string, 0, len(resolved))
+	rejected := make([]string, 0, len(resolved))
+
+	for _, lobbyReq := range resolved {
+		cache.RecordLobbyWait(meetingCode, time.Since(time.Unix(lobbyReq.CreatedAt, 0)))
+
+		if status == cache.LobbyStatusRejected {
+			cache.Hub.NotifyVisitor(lobbyReq.ID, WSRejectedMsg{
+				Type: WSTypeRejected,
+			})
+			rejected = append(rejected, lobbyReq.ID)
+			continue
+		}
+
+		if err := h.approveVisitor(lobbyReq); err != nil {
+			log.Printf("[WS Admin] Failed to approve request %s: %v", lobbyReq.ID, err)
+			failures[lobbyReq.ID] = approvalFailureReason(err)
+
+			// A visitor blocked since they knocked is turned away; anything else may
+			// clear up, so the request goes back to the queue for the host to retry
+			if errors.Is(err, errVisitorBanned) {
+				if err := cache.UpdateLobbyRequestStatus(lobbyReq.ID, cache.LobbyStatusRejected); err != nil {
+					log.Printf("[WS Admin] %v", err)
+				}
+				cache.Hub.NotifyVisitor(lobbyReq.ID, WSRejectedMsg{
+					Type: WSTypeRejected,
+				})
+				rejected = append(rejected, lobbyReq.ID)
+				continue
+			}
+			if err := cache.ReopenLobbyRequest(lobbyReq); err != nil {
+				log.Printf("[WS Admin] %v", err)
+			}
+			continue
+		}
+		approved = append(approved, lobbyReq.ID)
 	}
 
-	if msg.Action == "reject" {
-		// Update status
-		if err := cache.UpdateLobbyRequestStatus(msg.RequestID, cache.LobbyStatusRejected); err != nil {
-			log.Printf("[WS Admin] Failed to reject request: %v", err)
-			return
+	// Notify all admins (in case of multiple admin tabs)
+	isBulk := msg.Action == WSActionAdmitAll || msg.Action == WSActionRejectAll || len(requestIDs) > 1
+	if isBulk {
+		if len(approved)+len(rejected) > 0 {
+			cache.Hub.NotifyAdmins(meetingCode, WSRequestsResolvedMsg{
+				Type:     WSTypeRequestsResolved,
+				Approved: approved,
+				Rejected: rejected,
+			})
 		}
+	} else {
+		for _, id := range append(approved, rejected...) {
+			cache.Hub.NotifyAdmins(meetingCode, WSRequestResolvedMsg{
+				Type:      WSTypeRequestResolved,
+				RequestID: id,
+			})
+		}
+	}
 
-		// Notify visitor
-		cache.Hub.NotifyVisitor(msg.RequestID, WSRejectedMsg{
-			Type: WSTypeRejected,
-		})
+	// Report the outcome to the admin who issued the action
+	failed := make([]WSRespondFailure, 0, len(failures))
+	for id, reason := range failures {
+		failed = append(failed, WSRespondFailure{RequestID: id, Reason: reason})
+	}
+	lc.SendJSON(WSRespondResultMsg{
+		Type:     WSTypeRespondResult,
+		Action:   msg.Action,
+		Resolved: len(approved) + len(rejected),
+		Failed:   failed,
+	})
 
-		// Notify all admins (in case of multiple admin tabs)
-		cache.Hub.NotifyAdmins(meetingCode, WSRequestResolvedMsg{
-			Type:      WSTypeRequestResolved,
-			RequestID: msg.RequestID,
-		})
+	if len(resolved) > 0 {
+		BroadcastQueuePositions(meetingCode)
+	}
 
-		// Cleanup
-		go cache.CleanupLobbyRequest(msg.RequestID)
-		return
+	// Keep resolved requests around so that a visitor who was briefly
+	// disconnected still receives the outcome when they reconnect
+	cleanupAfterGrace(append(approved, rejected...)...)
+}
+
+// Reasons reported to the admin when an approved visitor cannot be let in
+const (
+	WSFailureBanned       = "banned"
+	WSFailureMeetingFull  = "meeting_full"
+	WSFailureAccessDenied = "access_denied"
+	WSFailureTokenError   = "token_error"
+)
+
+// errVisitorBanned is returned by approveVisitor for a visitor blocked since they knocked
+var errVisitorBanned = errors.New("visitor is blocked from this meeting")
+
+// accessError is a join refused by the meeting's settings at the time of approval
+type accessError struct{ err error }
+
+func (e *accessError) Error() string { return e.err.Error() }
+func (e *accessError) Unwrap() error { return e.err }
+
+func approvalFailureReason(err error) string {
+	var access *accessError
+	switch {
+	case errors.Is(err, errVisitorBanned):
+		return WSFailureBanned
+	case errors.Is(err, errMeetingFull):
+		return WSFailureMeetingFull
+	case errors.As(err, &access):
+		return WSFailureAccessDenied
+	}
+	return WSFailureTokenError
+}
+
+// approveVisitor lets in the visitor of an approved request: it checks again that
+// they may join, since they may have been blocked or the meeting locked or filled
+// up while they waited, then generates their LiveKit token and ticket and pushes
+// them to the visitor
+func (h *LobbyWSHandler) approveVisitor(lobbyReq *cache.LobbyRequest) error {
+	meeting, err := h.meetingService.GetMeetingByCode(lobbyReq.MeetingCode)
+	if err != nil {
+		return err
+	}
+
+	var user *models.User
+	if lobbyReq.UserID > 0 {
+		if user, err = h.userService.GetUserByID(lobbyReq.UserID); err != nil {
+			return err
+		}
 	}
 
-	// Approve: generate token
-	metadata := fmt.Sprintf(`{"name":"%s","avatar":"%s","role":"%s"}`, lobbyReq.Name, lobbyReq.AvatarURL, lobbyReq.Role)
+	participant := newParticipant(lobbyReq.Identity, lobbyReq.Name, user, lobbyReq.Fingerprint, lobbyReq.Role)
+	if h.meetingService.IsBanned(meeting.ID, participant.UserID, participant.Email, participant.Fingerprint, participant.Identity) {
+		return errVisitorBanned
+	}
+	if err := h.meetingService.CheckJoinWindow(meeting, lobbyReq.UserID); err != nil {
+		return &accessError{err}
+	}
+	if err := h.meetingService.CheckAdmission(meeting, user); err != nil {
+		return &accessError{err}
+	}
+	if err := openRoom(h.livekitService, h.meetingService, meeting); err != nil {
+		return err
+	}
+
+	metadata := services.ParticipantMetadata(lobbyReq.Name, lobbyReq.AvatarURL, lobbyReq.Role)
 
 	token, err := h.livekitService.CreateJoinToken(
 		lobbyReq.MeetingCode,
@@ -308,50 +654,118 @@ func (h *LobbyWSHandler) handleAdminRespond(meetingCode string, msg *WSRespondMs
 		metadata,
 	)
 	if err != nil {
-		log.Printf("[WS Admin] Failed to generate token: %v", err)
-		return
+		return err
 	}
 
-	// Update status
-	if err := cache.UpdateLobbyRequestStatus(msg.RequestID, cache.LobbyStatusApproved); err != nil {
-		log.Printf("[WS Admin] Failed to approve request: %v", err)
-		return
+	rememberParticipant(lobbyReq.MeetingCode, participant)
+
+	ticket, err := cache.IssueAdmissionTicket(&cache.AdmissionTicket{
+		MeetingCode: lobbyReq.MeetingCode,
+		Identity:    lobbyReq.Identity,
+		Name:        lobbyReq.Name,
+		UserID:      lobbyReq.UserID,
+	})
+	if err != nil {
+		// The token above is still valid, only rejoining through /livekit/token is affected
+		log.Printf("[WS Visitor] Failed to issue admission ticket for %s: %v", lobbyReq.Identity, err)
+	}
+
+	// Keep them for a visitor who reconnects, then notify the visitor
+	if err := cache.StoreLobbyApproval(lobbyReq, token, ticket); err != nil {
+		log.Printf("[WS Visitor] %v", err)
 	}
+	cache.Hub.NotifyVisitor(lobbyReq.ID, h.approvedMsg(lobbyReq))
 
-	// Notify visitor with token
-	cache.Hub.NotifyVisitor(msg.RequestID, WSApprovedMsg{
+	return nil
+}
+
+// approvedMsg tells a visitor they were admitted, with the token and ticket they were given
+func (h *LobbyWSHandler) approvedMsg(lobbyReq *cache.LobbyRequest) WSApprovedMsg {
+	return WSApprovedMsg{
 		Type:     WSTypeApproved,
-		Token:    token,
+		Token:    lobbyReq.Token,
 		URL:      h.livekitService.GetURL(),
 		RoomCode: lobbyReq.MeetingCode,
 		Identity: lobbyReq.Identity,
 		UserName: lobbyReq.Name,
-	})
-
-	// Notify all admins
-	cache.Hub.NotifyAdmins(meetingCode, WSRequestResolvedMsg{
-		Type:      WSTypeRequestResolved,
-		RequestID: msg.RequestID,
-	})
-
-	// Cleanup after a delay (give visitor time to receive the token)
-	go func() {
-		time.Sleep(5 * time.Second)
-		cache.CleanupLobbyRequest(msg.RequestID)
-	}()
+		Ticket:   lobbyReq.Ticket,
+	}
 }
 
 // HandleVisitorRequest is called from the HTTP RequestToJoin handler
 // AFTER storing the lobby request. It notifies connected admins about the new request.
 func NotifyAdminsOfNewRequest(lobbyReq *cache.LobbyRequest) {
 	cache.Hub.NotifyAdmins(lobbyReq.MeetingCode, WSNewRequestMsg{
-		Type: WSTypeNewRequest,
-		Request: WSPendingRequestEntry{
-			RequestID: lobbyReq.ID,
-			Name:      lobbyReq.Name,
-			AvatarURL: lobbyReq.AvatarURL,
-			Role:      lobbyReq.Role,
-			CreatedAt: lobbyReq.CreatedAt,
-		},
+		Type:    WSTypeNewRequest,
+		Request: toPendingRequestEntry(lobbyReq),
 	})
 }
+
+// queuePositionMsg builds the queue position of the request at index in the pending queue
+func queuePositionMsg(pending []*cache.LobbyRequest, index int, avgWait time.Duration, hasAvg bool) WSQueuePositionMsg {
+	msg := WSQueuePositionMsg{
+		Type:     WSTypeQueuePosition,
+		Position: index + 1,
+		Total:    len(pending),
+	}
+	if hasAvg {
+		estimate := int64(avgWait.Seconds()) * int64(index+1)
+		msg.EstimatedWaitSeconds = &estimate
+	}
+	return msg
+}
+
+// BroadcastQueuePositions pushes the current queue position to every visitor waiting
+// for a meeting. It is called whenever requests leave the queue.
+func BroadcastQueuePositions(meetingCode string) {
+	pending, err := cache.GetPendingRequests(meetingCode)
+	if err != nil {
+		log.Printf("[Lobby] Failed to get pending requests for meeting %s: %v", meetingCode, err)
+		return
+	}
+
+	avgWait, hasAvg := cache.AverageLobbyWait(meetingCode)
+	for i, req := range pending {
+		cache.Hub.NotifyVisitor(req.ID, queuePositionMsg(pending, i, avgWait, hasAvg))
+	}
+}
+
+// ExpireTimedOutRequests resolves every pending request whose wait exceeded the
+// meeting's lobby timeout, notifying the visitor and the meeting's admins.
+func (h *LobbyWSHandler) ExpireTimedOutRequests() {
+	ids, err := cache.ClaimExpiredLobbyRequests(time.Now())
+	if err != nil {
+		log.Printf("[Lobby] Failed to claim expired requests: %v", err)
+		return
+	}
+
+	meetings := make(map[string]struct{})
+	for _, id := range ids {
+		lobbyReq, err := cache.GetLobbyRequest(id)
+		if err != nil {
+			continue
+		}
+
+		resolved, _, err := cache.ResolveLobbyRequests(lobbyReq.MeetingCode, []string{id}, cache.LobbyStatusTimedOut)
+		if err != nil || len(resolved) == 0 {
+			// Already approved, rejected or cancelled
+			continue
+		}
+
+		cache.Hub.NotifyVisitor(id, WSTimedOutMsg{
+			Type: WSTypeTimedOut,
+		})
+		cache.Hub.NotifyAdmins(lobbyReq.MeetingCode, WSTimedOutMsg{
+			Type:      WSTypeTimedOut,
+			RequestID: id,
+		})
+		cleanupAfterGrace(id)
+
+		meetings[lobbyReq.MeetingCode] = struct{}{}
+		log.Printf("[Lobby] Request %s for meeting %s timed out", id, lobbyReq.MeetingCode)
+	}
+
+	for meetingCode := range meetings {
+		BroadcastQueuePositions(meetingCode)
+	}
+}
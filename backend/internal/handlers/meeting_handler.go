@@ -1,24 +1,29 @@
 package handlers
 
 import (
+	"html"
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
 	"mini-meeting/internal/services"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type MeetingHandler struct {
-	service *services.MeetingService
-	cfg     *config.Config
+	service           *services.MeetingService
+	invitationService *services.InvitationService
+	cfg               *config.Config
 }
 
-func NewMeetingHandler(service *services.MeetingService, cfg *config.Config) *MeetingHandler {
-	return &MeetingHandler{service: service, cfg: cfg}
+func NewMeetingHandler(service *services.MeetingService, invitationService *services.InvitationService, cfg *config.Config) *MeetingHandler {
+	return &MeetingHandler{service: service, invitationService: invitationService, cfg: cfg}
 }
 
-// CreateMeeting creates a new instant meeting
+// CreateMeeting creates a new instant meeting, or a scheduled one when a start_time is given
 // POST /api/v1/meetings
 func (h *MeetingHandler) CreateMeeting(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(uint)
@@ -28,12 +33,20 @@ func (h *MeetingHandler) CreateMeeting(c *fiber.Ctx) error {
 		})
 	}
 
-	// Create instant meeting (no request body needed)
-	meeting, err := h.service.CreateMeeting(userID)
+	// The body is optional: without one an instant meeting is created
+	var req *dto.CreateMeetingRequest
+	if len(c.Body()) > 0 {
+		req = &dto.CreateMeetingRequest{}
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	meeting, err := h.service.CreateMeeting(userID, req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return meetingErrorResponse(c, err)
 	}
 
 	// Generate meeting link using frontend URL
@@ -114,7 +127,7 @@ func (h *MeetingHandler) GetMeetingByCode(c *fiber.Ctx) error {
 }
 
 // GetMyMeetings retrieves meetings created by the current user
-// GET /api/v1/meetings/my
+// GET /api/v1/meetings/my?filter=upcoming|past
 func (h *MeetingHandler) GetMyMeetings(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(uint)
 	if !ok {
@@ -123,11 +136,9 @@ func (h *MeetingHandler) GetMyMeetings(c *fiber.Ctx) error {
 		})
 	}
 
-	meetings, err := h.service.GetMeetingsByCreatorID(userID)
+	meetings, err := h.service.GetMyMeetings(userID, c.Query("filter"))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return meetingErrorResponse(c, err)
 	}
 
 	baseURL := h.cfg.Server.FrontendURL
@@ -207,3 +218,679 @@ func (h *MeetingHandler) DeleteMeeting(c *fiber.Ctx) error {
 		"message": "Meeting deleted successfully",
 	})
 }
+
+// meetingErrorResponse maps errors returned by meeting management methods to HTTP responses
+func meetingErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch {
+	case err.Error() == "meeting not found" || err.Error() == "invitee not found" || err.Error() == "ban not found" ||
+		err.Error() == "user not found" || err.Error() == "co-host not found" || err.Error() == "occurrence not found":
+		statusCode = fiber.StatusNotFound
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		statusCode = fiber.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid ") || strings.HasSuffix(err.Error(), " is required for the domain admission policy"):
+		statusCode = fiber.StatusBadRequest
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// GetSettings retrieves the settings of a meeting (creator only)
+// GET /api/v1/meetings/:id/settings
+func (h *MeetingHandler) GetSettings(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	settings, err := h.service.GetSettingsForOwner(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": dto.ToMeetingSettingsResponse(settings),
+	})
+}
+
+// UpdateSettings updates the settings of a meeting (creator only)
+// PATCH /api/v1/meetings/:id/settings
+func (h *MeetingHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.UpdateMeetingSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	settings, err := h.service.UpdateSettings(uint(id), userID, &req)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Meeting settings updated successfully",
+		"data":    dto.ToMeetingSettingsResponse(settings),
+	})
+}
+
+// GetInvitees lists the emails invited to a meeting (creator only)
+// GET /api/v1/meetings/:id/invitees
+func (h *MeetingHandler) GetInvitees(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	invitees, err := h.service.GetInvitees(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": invitees,
+	})
+}
+
+// AddInvitees adds emails to the invitee list of a meeting and emails them an invitation (creator only)
+// POST /api/v1/meetings/:id/invitees
+func (h *MeetingHandler) AddInvitees(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.AddInviteesRequest
+	if err := c.BodyParser(&req); err != nil || len(req.Emails) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "emails is required",
+		})
+	}
+
+	invitees, err := h.invitationService.Invite(uint(id), userID, req.Emails)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Invitees added successfully",
+		"data":    invitees,
+	})
+}
+
+// RemoveInvitee removes an email from the invitee list of a meeting (creator only)
+// DELETE /api/v1/meetings/:id/invitees/:inviteeId
+func (h *MeetingHandler) RemoveInvitee(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	inviteeID, err := strconv.ParseUint(c.Params("inviteeId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invitee ID",
+		})
+	}
+
+	if err := h.service.RemoveInvitee(uint(id), userID, uint(inviteeID)); err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Invitee removed successfully",
+	})
+}
+
+// SetInviteeRole sets the role an invitee joins with (creator only)
+// PUT /api/v1/meetings/:id/invitees/:inviteeId/role
+func (h *MeetingHandler) SetInviteeRole(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	inviteeID, err := strconv.ParseUint(c.Params("inviteeId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invitee ID",
+		})
+	}
+
+	var req dto.SetInviteeRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.service.SetInviteeRole(uint(id), userID, uint(inviteeID), req.Role); err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Invitee role updated successfully",
+	})
+}
+
+// GetBans lists the participants blocked from a meeting (hosts only)
+// GET /api/v1/meetings/:id/bans
+func (h *MeetingHandler) GetBans(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	bans, err := h.service.GetBans(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": bans,
+	})
+}
+
+// LiftBan removes a participant from the block list of a meeting (hosts only)
+// DELETE /api/v1/meetings/:id/bans/:banId
+func (h *MeetingHandler) LiftBan(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	banID, err := strconv.ParseUint(c.Params("banId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid ban ID",
+		})
+	}
+
+	if err := h.service.LiftBan(uint(id), userID, uint(banID)); err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Ban lifted successfully",
+	})
+}
+
+// GetCohosts lists the co-hosts of a meeting (creator only)
+// GET /api/v1/meetings/:id/cohosts
+func (h *MeetingHandler) GetCohosts(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	cohosts, err := h.service.GetCohosts(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": cohosts,
+	})
+}
+
+// AddCohost makes a registered user a co-host of a meeting (creator only)
+// POST /api/v1/meetings/:id/cohosts
+func (h *MeetingHandler) AddCohost(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.AddCohostRequest
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "email is required",
+		})
+	}
+
+	cohost, err := h.service.AddCohost(uint(id), userID, req.Email)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Co-host added successfully",
+		"data":    cohost,
+	})
+}
+
+// RemoveCohost revokes the co-host rights of a user on a meeting (creator only)
+// DELETE /api/v1/meetings/:id/cohosts/:userId
+func (h *MeetingHandler) RemoveCohost(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	cohostUserID, err := strconv.ParseUint(c.Params("userId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if err := h.service.RemoveCohost(uint(id), userID, uint(cohostUserID)); err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Co-host removed successfully",
+	})
+}
+
+// UpdateMeeting updates the details and schedule of a meeting (creator only)
+// PATCH /api/v1/meetings/:id
+func (h *MeetingHandler) UpdateMeeting(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.UpdateMeetingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	meeting, err := h.service.UpdateMeeting(uint(id), userID, &req)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Meeting updated successfully",
+		"data":    dto.ToMeetingResponse(meeting, h.cfg.Server.FrontendURL+"/"),
+	})
+}
+
+// CancelMeeting cancels a meeting that has not started yet (creator only)
+// POST /api/v1/meetings/:id/cancel
+func (h *MeetingHandler) CancelMeeting(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	meeting, err := h.service.CancelMeeting(uint(id), userID)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Meeting cancelled successfully",
+		"data":    dto.ToMeetingResponse(meeting, h.cfg.Server.FrontendURL+"/"),
+	})
+}
+
+// GetOccurrences lists the occurrences of a scheduled meeting between from and to (hosts only).
+// Both are RFC 3339 times; they default to now and 30 days later.
+// GET /api/v1/meetings/:id/occurrences?from=&to=
+func (h *MeetingHandler) GetOccurrences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid from time",
+			})
+		}
+	}
+
+	to := from.AddDate(0, 0, 30)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid to time",
+			})
+		}
+	}
+
+	occurrences, err := h.service.GetOccurrences(uint(id), userID, from, to)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": occurrences,
+	})
+}
+
+// SetOccurrenceOverride moves, renames or cancels one occurrence of a recurring meeting (creator only)
+// PUT /api/v1/meetings/:id/occurrences/:occurrenceId
+func (h *MeetingHandler) SetOccurrenceOverride(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	occurrenceID, err := strconv.ParseInt(c.Params("occurrenceId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid occurrence ID",
+		})
+	}
+
+	var req dto.OccurrenceOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	occurrence, err := h.service.SetOccurrenceOverride(uint(id), userID, occurrenceID, &req)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Occurrence updated successfully",
+		"data":    occurrence,
+	})
+}
+
+// RemoveOccurrenceOverride restores one occurrence of a recurring meeting to its rule (creator only)
+// DELETE /api/v1/meetings/:id/occurrences/:occurrenceId
+func (h *MeetingHandler) RemoveOccurrenceOverride(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	occurrenceID, err := strconv.ParseInt(c.Params("occurrenceId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid occurrence ID",
+		})
+	}
+
+	if err := h.service.RemoveOccurrenceOverride(uint(id), userID, occurrenceID); err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Occurrence restored successfully",
+	})
+}
+
+// RespondToInvitation records the RSVP of the authenticated user to a meeting they are invited to
+// POST /api/v1/meetings/:id/rsvp
+func (h *MeetingHandler) RespondToInvitation(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.RSVPRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	invitee, err := h.invitationService.RespondAsUser(uint(id), userID, req.Response)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "RSVP recorded successfully",
+		"data":    invitee,
+	})
+}
+
+// ConfirmRSVPLink shows the answer an invitee chose through the signed link of an
+// invitation email and asks them to confirm it. Nothing is recorded on GET, since
+// mail scanners and link previews open the links in emails.
+// GET /api/v1/invitations/:id/rsvp?response=accepted&exp=...&sig=...
+func (h *MeetingHandler) ConfirmRSVPLink(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invitation ID",
+		})
+	}
+
+	// A missing or malformed expiry fails the signature check
+	expires, _ := strconv.ParseInt(c.Query("exp"), 10, 64)
+	response := models.RSVPStatus(c.Query("response"))
+	meeting, err := h.invitationService.CheckRSVPLink(uint(id), expires, c.Query("sig"), response)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	title := meeting.Title
+	if title == "" {
+		title = "Meeting " + meeting.MeetingCode
+	}
+
+	button := "Decline the invitation"
+	switch response {
+	case models.RSVPAccepted:
+		button = "Accept the invitation"
+	case models.RSVPTentative:
+		button = "Answer maybe"
+	}
+
+	return sendRSVPPage(c, "Answer the invitation", `<p style="color: #444; line-height: 1.6;">`+html.EscapeString(title)+`</p>
+    <form method="POST" action="`+html.EscapeString(c.Path())+`">
+      <input type="hidden" name="response" value="`+html.EscapeString(string(response))+`">
+      <input type="hidden" name="exp" value="`+strconv.FormatInt(expires, 10)+`">
+      <input type="hidden" name="sig" value="`+html.EscapeString(c.Query("sig"))+`">
+      <button type="submit" style="background: #6c63ff; color: #ffffff; border: 0; border-radius: 4px; padding: 10px 20px; cursor: pointer;">`+button+`</button>
+    </form>`)
+}
+
+// RespondWithLink records the RSVP confirmed on the page of ConfirmRSVPLink.
+// It is public and answers with a short HTML page since it is opened from the email.
+// POST /api/v1/invitations/:id/rsvp (form fields response, exp and sig)
+func (h *MeetingHandler) RespondWithLink(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invitation ID",
+		})
+	}
+
+	expires, _ := strconv.ParseInt(c.FormValue("exp"), 10, 64)
+	response := models.RSVPStatus(c.FormValue("response"))
+	meeting, err := h.invitationService.RespondWithLink(uint(id), expires, c.FormValue("sig"), response)
+	if err != nil {
+		return meetingErrorResponse(c, err)
+	}
+
+	message := "You declined the invitation."
+	switch response {
+	case models.RSVPAccepted:
+		message = "You accepted the invitation."
+	case models.RSVPTentative:
+		message = "You answered maybe to the invitation."
+	}
+
+	joinURL := h.service.MeetingJoinURL(meeting)
+	return sendRSVPPage(c, "Thanks for your answer", `<p style="color: #444; line-height: 1.6;">`+message+` You can change it with the other links in the email.</p>
+    <a href="`+html.EscapeString(joinURL)+`" style="color: #6c63ff;">Open the meeting</a>`)
+}
+
+// sendRSVPPage answers an RSVP link with a short HTML page
+func sendRSVPPage(c *fiber.Ctx, heading string, body string) error {
+	c.Type("html", "utf-8")
+	return c.SendString(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; background: #f4f4f4; padding: 32px;">
+  <div style="max-width: 560px; margin: 0 auto; background: #ffffff; border-radius: 8px; padding: 32px;">
+    <h2 style="color: #1a1a2e; margin-top: 0;">` + heading + `</h2>
+    ` + body + `
+  </div>
+</body>
+</html>`)
+}
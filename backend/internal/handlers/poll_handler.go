@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PollHandler struct {
+	service *services.PollService
+}
+
+func NewPollHandler(service *services.PollService) *PollHandler {
+	return &PollHandler{service: service}
+}
+
+// pollErrorResponse maps poll service errors to HTTP status codes
+func pollErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid token"):
+		statusCode = fiber.StatusUnauthorized
+	case strings.HasSuffix(err.Error(), "not found"):
+		statusCode = fiber.StatusNotFound
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		statusCode = fiber.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "):
+		statusCode = fiber.StatusBadRequest
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// CreatePoll creates a draft poll for a meeting (hosts only)
+// POST /api/v1/meetings/:id/polls
+func (h *PollHandler) CreatePoll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.CreatePollRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	poll, err := h.service.CreatePoll(uint(id), userID, &req)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": poll,
+	})
+}
+
+// ListPolls lists the polls of a meeting with their results (hosts only)
+// GET /api/v1/meetings/:id/polls
+func (h *PollHandler) ListPolls(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	polls, err := h.service.ListPolls(uint(id), userID)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": polls,
+	})
+}
+
+// GetPoll returns a poll of a meeting with its results (hosts only)
+// GET /api/v1/meetings/:id/polls/:pollId
+func (h *PollHandler) GetPoll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	pollID, err := strconv.ParseUint(c.Params("pollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid poll ID",
+		})
+	}
+
+	poll, err := h.service.GetResults(uint(id), uint(pollID), userID)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": poll,
+	})
+}
+
+// OpenPoll starts accepting votes for a draft poll (hosts only)
+// POST /api/v1/meetings/:id/polls/:pollId/open
+func (h *PollHandler) OpenPoll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	pollID, err := strconv.ParseUint(c.Params("pollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid poll ID",
+		})
+	}
+
+	poll, err := h.service.OpenPoll(uint(id), uint(pollID), userID)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": poll,
+	})
+}
+
+// ClosePoll stops accepting votes for an open poll (hosts only)
+// POST /api/v1/meetings/:id/polls/:pollId/close
+func (h *PollHandler) ClosePoll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	pollID, err := strconv.ParseUint(c.Params("pollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid poll ID",
+		})
+	}
+
+	poll, err := h.service.ClosePoll(uint(id), uint(pollID), userID)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": poll,
+	})
+}
+
+// Vote records the caller's choice in an open poll (public, authenticated by LiveKit token)
+// POST /api/v1/livekit/polls/:pollId/vote
+func (h *PollHandler) Vote(c *fiber.Ctx) error {
+	pollID, err := strconv.ParseUint(c.Params("pollId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid poll ID",
+		})
+	}
+
+	var req dto.PollVoteRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" || len(req.OptionIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token and option_ids are required",
+		})
+	}
+
+	poll, err := h.service.Vote(uint(pollID), req.Token, req.OptionIDs)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": poll,
+	})
+}
+
+// ListParticipantPolls lists the open and closed polls of the caller's meeting
+// (public, authenticated by LiveKit token)
+// POST /api/v1/livekit/polls
+func (h *PollHandler) ListParticipantPolls(c *fiber.Ctx) error {
+	var req dto.ParticipantTokenRequest
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "token is required",
+		})
+	}
+
+	polls, err := h.service.ListParticipantPolls(req.Token)
+	if err != nil {
+		return pollErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": polls,
+	})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RecordingHandler struct {
+	service *services.RecordingService
+}
+
+func NewRecordingHandler(service *services.RecordingService) *RecordingHandler {
+	return &RecordingHandler{service: service}
+}
+
+// recordingErrorResponse maps recording service errors to HTTP status codes
+func recordingErrorResponse(c *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch {
+	case strings.HasSuffix(err.Error(), "not found"):
+		statusCode = fiber.StatusNotFound
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		statusCode = fiber.StatusForbidden
+	case strings.HasPrefix(err.Error(), "invalid "):
+		statusCode = fiber.StatusBadRequest
+	}
+
+	return c.Status(statusCode).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
+
+// StartRecording starts recording a room of a meeting (hosts only)
+// POST /api/v1/meetings/:id/recordings/start
+func (h *RecordingHandler) StartRecording(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.StartRecordingRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	recordings, err := h.service.StartRecording(uint(id), userID, &req)
+	if err != nil {
+		return recordingErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data": recordings,
+	})
+}
+
+// StopRecording stops a recording, or every active recording of a meeting (hosts only)
+// POST /api/v1/meetings/:id/recordings/stop
+func (h *RecordingHandler) StopRecording(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.StopRecordingRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	recordings, err := h.service.StopRecording(uint(id), userID, req.RecordingID)
+	if err != nil {
+		return recordingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": recordings,
+	})
+}
+
+// ListRecordings lists the recordings of a meeting, newest first (hosts only)
+// GET /api/v1/meetings/:id/recordings
+func (h *RecordingHandler) ListRecordings(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	recordings, err := h.service.ListRecordings(uint(id), userID)
+	if err != nil {
+		return recordingErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data": recordings,
+	})
+}
+
+// DownloadRecording sends the file of a completed recording, or redirects to it
+// when it is kept in a bucket (hosts only)
+// GET /api/v1/meetings/:id/recordings/:recordingId/download
+func (h *RecordingHandler) DownloadRecording(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	recordingID, err := strconv.ParseUint(c.Params("recordingId"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid recording ID",
+		})
+	}
+
+	download, err := h.service.GetDownload(uint(id), uint(recordingID), userID)
+	if err != nil {
+		return recordingErrorResponse(c, err)
+	}
+
+	if download.URL != "" {
+		return c.Redirect(download.URL, fiber.StatusFound)
+	}
+	return c.Download(download.LocalPath, download.FileName)
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"mini-meeting/internal/handlers/dto"
 	"mini-meeting/internal/services"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -41,10 +43,13 @@ func (h *SummarizerHandler) StartSummarizer(c *fiber.Ctx) error {
 		statusCode := fiber.StatusInternalServerError
 		if err.Error() == "meeting not found" {
 			statusCode = fiber.StatusNotFound
-		} else if err.Error() == "unauthorized: only meeting creator can start summarizer" {
+		} else if err.Error() == "unauthorized: only meeting hosts can start summarizer" ||
+			err.Error() == "summarizer is not allowed for this meeting" {
 			statusCode = fiber.StatusForbidden
 		} else if err.Error() == "summarizer already running for this meeting" {
 			statusCode = fiber.StatusConflict
+		} else if strings.HasPrefix(err.Error(), "quota exceeded") {
+			statusCode = fiber.StatusTooManyRequests
 		}
 
 		return c.Status(statusCode).JSON(fiber.Map{
@@ -101,9 +106,9 @@ func (h *SummarizerHandler) StopSummarizer(c *fiber.Ctx) error {
 		statusCode := fiber.StatusInternalServerError
 		if err.Error() == "session not found" || err.Error() == "meeting not found" {
 			statusCode = fiber.StatusNotFound
-		} else if err.Error() == "unauthorized: only meeting creator can stop summarizer" {
+		} else if err.Error() == "unauthorized: only meeting hosts can stop summarizer" {
 			statusCode = fiber.StatusForbidden
-		} else if err.Error()[:28] == "session is not active" {
+		} else if strings.HasPrefix(err.Error(), "session is not active") {
 			statusCode = fiber.StatusConflict
 		}
 
@@ -128,6 +133,51 @@ func (h *SummarizerHandler) StopSummarizer(c *fiber.Ctx) error {
 	})
 }
 
+// RecordConsent records whether the caller agrees to be transcribed by the active summarizer
+// POST /api/v1/meetings/:id/summarizer/consent
+func (h *SummarizerHandler) RecordConsent(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meetingID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	var req dto.SummarizerConsentRequest
+	if err := c.BodyParser(&req); err != nil || req.Identity == "" || req.Consent == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "identity and consent are required",
+		})
+	}
+
+	if err := h.service.RecordConsent(uint(meetingID), userID, req.Identity, *req.Consent); err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "meeting not found" || err.Error() == "no active summarizer session found" {
+			statusCode = fiber.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "unauthorized:") {
+			statusCode = fiber.StatusForbidden
+		}
+		return c.Status(statusCode).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Consent recorded",
+		"data": fiber.Map{
+			"identity": req.Identity,
+			"consent":  *req.Consent,
+		},
+	})
+}
+
 // GetSessions retrieves a paginated list of sessions for the authenticated user
 // GET /api/v1/sessions?page=1&page_size=10
 func (h *SummarizerHandler) GetSessions(c *fiber.Ctx) error {
@@ -186,6 +236,61 @@ func (h *SummarizerHandler) GetSession(c *fiber.Ctx) error {
 	})
 }
 
+// ImportSession creates a session from an uploaded audio/video file (multipart
+// field "file") or from a completed recording of the meeting, and summarizes it
+// in the background
+// POST /api/v1/sessions/import
+func (h *SummarizerHandler) ImportSession(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req dto.ImportSessionRequest
+	if err := c.BodyParser(&req); err != nil || req.MeetingID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "meeting_id is required",
+		})
+	}
+
+	// Absent unless the request is a multipart upload
+	upload, _ := c.FormFile("file")
+
+	session, err := h.service.ImportSession(userID, &req, upload)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			statusCode = fiber.StatusNotFound
+		case strings.HasPrefix(err.Error(), "unauthorized:") ||
+			err.Error() == "summarizer is not allowed for this meeting":
+			statusCode = fiber.StatusForbidden
+		case strings.HasPrefix(err.Error(), "invalid "):
+			statusCode = fiber.StatusBadRequest
+		case strings.HasPrefix(err.Error(), "quota exceeded"):
+			statusCode = fiber.StatusTooManyRequests
+		case strings.HasPrefix(err.Error(), "recording import is unavailable"):
+			statusCode = fiber.StatusServiceUnavailable
+		}
+
+		return c.Status(statusCode).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Recording imported, summary in progress",
+		"data": fiber.Map{
+			"session_id": session.ID,
+			"meeting_id": session.MeetingID,
+			"status":     session.Status,
+			"started_at": session.StartedAt,
+		},
+	})
+}
+
 // DeleteSession deletes a session by ID
 // DELETE /api/v1/sessions/:id
 func (h *SummarizerHandler) DeleteSession(c *fiber.Ctx) error {
@@ -221,3 +326,38 @@ func (h *SummarizerHandler) DeleteSession(c *fiber.Ctx) error {
 		"message": "Session deleted successfully",
 	})
 }
+
+// GetMeetingSessions lists the summarizer sessions of a meeting in occurrence order (hosts only)
+// GET /api/v1/meetings/:id/sessions
+func (h *SummarizerHandler) GetMeetingSessions(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid meeting ID",
+		})
+	}
+
+	sessions, err := h.service.GetMeetingSessions(uint(id), userID)
+	if err != nil {
+		statusCode := fiber.StatusInternalServerError
+		if err.Error() == "meeting not found" {
+			statusCode = fiber.StatusNotFound
+		} else if strings.HasPrefix(err.Error(), "unauthorized:") {
+			statusCode = fiber.StatusForbidden
+		}
+		return c.Status(statusCode).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": sessions,
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"log"
+	"mini-meeting/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+type WebhookHandler struct {
+	livekitService    *services.LiveKitService
+	attendanceService *services.AttendanceService
+	summarizerService *services.SummarizerService
+	chatService       *services.ChatService
+	handRaiseService  *services.HandRaiseService
+	recordingService  *services.RecordingService
+}
+
+func NewWebhookHandler(
+	livekitService *services.LiveKitService,
+	attendanceService *services.AttendanceService,
+	summarizerService *services.SummarizerService,
+	chatService *services.ChatService,
+	handRaiseService *services.HandRaiseService,
+	recordingService *services.RecordingService,
+) *WebhookHandler {
+	return &WebhookHandler{
+		livekitService:    livekitService,
+		attendanceService: attendanceService,
+		summarizerService: summarizerService,
+		chatService:       chatService,
+		handRaiseService:  handRaiseService,
+		recordingService:  recordingService,
+	}
+}
+
+// ReceiveLiveKitWebhook receives room, participant and egress events from the LiveKit server.
+// Requests must be signed with the configured API key and secret.
+// POST /api/v1/livekit/webhook
+func (h *WebhookHandler) ReceiveLiveKitWebhook(c *fiber.Ctx) error {
+	r, err := adaptor.ConvertRequest(c, false)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	event, err := h.livekitService.ReceiveWebhook(r)
+	if err != nil {
+		log.Printf("[LiveKit] Rejected webhook: %v", err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid webhook signature",
+		})
+	}
+
+	h.attendanceService.HandleWebhookEvent(event)
+	h.summarizerService.HandleWebhookEvent(event)
+	h.chatService.HandleWebhookEvent(event)
+	h.handRaiseService.HandleWebhookEvent(event)
+	h.recordingService.HandleWebhookEvent(event)
+
+	return c.SendStatus(fiber.StatusOK)
+}
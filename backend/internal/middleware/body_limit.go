@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit refuses request bodies larger than limit bytes. The app streams
+// request bodies so that recordings can be uploaded without being held in
+// memory, which means Fiber's own BodyLimit no longer refuses anything; this
+// middleware enforces the size instead. Requests to the paths in except are
+// left to a StreamBodyLimit of their own on the route.
+func BodyLimit(limit int, except ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := strings.TrimSuffix(c.Path(), "/")
+		for _, p := range except {
+			if strings.EqualFold(path, p) {
+				return c.Next()
+			}
+		}
+
+		length := c.Request().Header.ContentLength()
+		if length > limit {
+			return bodyTooLarge(c)
+		}
+
+		// Chunked bodies have no length up front: read at most one byte past the
+		// limit, which keeps at most limit bytes in memory
+		if length == -1 && c.Request().IsBodyStream() {
+			body, err := io.ReadAll(io.LimitReader(c.Context().RequestBodyStream(), int64(limit)+1))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Failed to read request body",
+				})
+			}
+			if len(body) > limit {
+				return bodyTooLarge(c)
+			}
+			c.Request().SetBody(body)
+		}
+
+		return c.Next()
+	}
+}
+
+// StreamBodyLimit refuses request bodies larger than limit bytes without holding
+// them in memory, for routes that accept large uploads. A streamed body is
+// copied to a temporary file as it arrives, up to one byte past the limit, and
+// handed to the handler from there.
+func StreamBodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		length := c.Request().Header.ContentLength()
+		if length > limit {
+			return bodyTooLarge(c)
+		}
+
+		if !c.Request().IsBodyStream() {
+			if len(c.Body()) > limit {
+				return bodyTooLarge(c)
+			}
+			return c.Next()
+		}
+
+		spool, err := os.CreateTemp("", "upload-*")
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to store request body",
+			})
+		}
+		defer func() {
+			spool.Close()
+			os.Remove(spool.Name())
+		}()
+
+		n, err := io.Copy(spool, io.LimitReader(c.Context().RequestBodyStream(), int64(limit)+1))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read request body",
+			})
+		}
+		if n > int64(limit) {
+			return bodyTooLarge(c)
+		}
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to store request body",
+			})
+		}
+
+		c.Request().SetBodyStream(spool, int(n))
+		return c.Next()
+	}
+}
+
+// bodyTooLarge answers a request whose body is over the limit. The rest of the
+// body is left unread, so the connection cannot be reused.
+func bodyTooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+		"error": "Request body is too large",
+	})
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const testLimit = 64 * 1024
+
+// newTestApp streams request bodies as the server does
+func newTestApp(limits ...fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
+	for _, limit := range limits {
+		app.Use(limit)
+	}
+	return app
+}
+
+// echoLength answers with how many body bytes the handler could read
+func echoLength(c *fiber.Ctx) error {
+	var n int64
+	if c.Request().IsBodyStream() {
+		read, err := io.Copy(io.Discard, c.Context().RequestBodyStream())
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+		n = read
+	} else {
+		n = int64(len(c.Body()))
+	}
+	return c.JSON(fiber.Map{"length": n})
+}
+
+// request POSTs size bytes to path, chunked when chunked is set, and returns the answer
+func request(t *testing.T, app *fiber.App, path string, size int, chunked bool) (int, string) {
+	t.Helper()
+
+	// A reader without a known size makes net/http send the body chunked
+	var body io.Reader = bytes.NewReader(bytes.Repeat([]byte("a"), size))
+	if chunked {
+		body = io.MultiReader(body)
+	}
+	req := httptest.NewRequest(fiber.MethodPost, path, body)
+	if chunked {
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	}
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		size    int
+		chunked bool
+		want    int
+	}{
+		{"within limit", "/", testLimit, false, fiber.StatusOK},
+		{"over limit", "/", testLimit + 1, false, fiber.StatusRequestEntityTooLarge},
+		{"chunked within limit", "/", testLimit, true, fiber.StatusOK},
+		{"chunked over limit", "/", 4 * testLimit, true, fiber.StatusRequestEntityTooLarge},
+		{"excepted path", "/import", 4 * testLimit, false, fiber.StatusOK},
+		{"excepted path with trailing slash", "/import/", 4 * testLimit, true, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(BodyLimit(testLimit, "/import"))
+			app.Post("/*", echoLength)
+
+			status, body := request(t, app, tt.path, tt.size, tt.chunked)
+			if status != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", status, tt.want, body)
+			}
+			if status == fiber.StatusOK && !strings.Contains(body, `"length":`+strconv.Itoa(tt.size)) {
+				t.Errorf("handler read %s, want %d bytes", body, tt.size)
+			}
+		})
+	}
+}
+
+func TestStreamBodyLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		chunked bool
+		want    int
+	}{
+		{"small body", 100, false, fiber.StatusOK},
+		{"within limit", testLimit, false, fiber.StatusOK},
+		{"over limit", testLimit + 1, false, fiber.StatusRequestEntityTooLarge},
+		{"chunked within limit", testLimit, true, fiber.StatusOK},
+		{"chunked exactly one byte over", testLimit + 1, true, fiber.StatusRequestEntityTooLarge},
+		{"chunked over limit", 4 * testLimit, true, fiber.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(StreamBodyLimit(testLimit))
+			app.Post("/", echoLength)
+
+			status, body := request(t, app, "/", tt.size, tt.chunked)
+			if status != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", status, tt.want, body)
+			}
+			if status == fiber.StatusOK && !strings.Contains(body, `"length":`+strconv.Itoa(tt.size)) {
+				t.Errorf("handler read %s, want %d bytes", body, tt.size)
+			}
+		})
+	}
+}
+
+// A chunked multipart upload is parsed from the stream, and one over the limit is refused
+func TestStreamBodyLimitMultipart(t *testing.T) {
+	upload := func(size int) (int, string) {
+		var form bytes.Buffer
+		w := multipart.NewWriter(&form)
+		w.WriteField("meeting_id", "1")
+		part, _ := w.CreateFormFile("file", "recording.mp3")
+		part.Write(bytes.Repeat([]byte("a"), size))
+		w.Close()
+
+		req := httptest.NewRequest(fiber.MethodPost, "/", io.MultiReader(&form))
+		req.Header.Set(fiber.HeaderContentType, w.FormDataContentType())
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+
+		app := newTestApp(StreamBodyLimit(testLimit))
+		app.Post("/", func(c *fiber.Ctx) error {
+			file, err := c.FormFile("file")
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
+			return c.JSON(fiber.Map{"meeting_id": c.FormValue("meeting_id"), "length": file.Size})
+		})
+
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	if status, body := upload(testLimit / 2); status != fiber.StatusOK || !strings.Contains(body, `"length":`+strconv.Itoa(testLimit/2)) {
+		t.Errorf("upload within limit: status %d, body %s", status, body)
+	}
+	if status, body := upload(2 * testLimit); status != fiber.StatusRequestEntityTooLarge {
+		t.Errorf("upload over limit: status %d, want 413 (%s)", status, body)
+	}
+}
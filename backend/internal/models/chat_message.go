@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ChatMessage is a chat message typed in a meeting's room, recorded from LiveKit
+// data messages. RoomName is the breakout room it was sent in; empty for the
+// main room.
+type ChatMessage struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	MeetingID uint       `gorm:"not null;index" json:"meeting_id"`
+	RoomName  string     `gorm:"size:64;not null;default:''" json:"room_name,omitempty"`
+	MessageID string     `gorm:"size:64;not null;default:''" json:"message_id"` // ID given by the sender's client
+	Identity  string     `gorm:"size:255;not null" json:"identity"`
+	Name      string     `gorm:"size:255;not null" json:"name"`
+	UserID    *uint      `gorm:"index" json:"user_id,omitempty"`
+	Message   string     `gorm:"type:text;not null" json:"message"`
+	SentAt    time.Time  `gorm:"not null" json:"sent_at"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"-"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
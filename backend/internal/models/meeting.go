@@ -2,13 +2,49 @@ package models
 
 import "time"
 
+// MeetingStatus is the lifecycle state of a meeting
+type MeetingStatus string
+
+const (
+	MeetingStatusScheduled MeetingStatus = "scheduled"
+	MeetingStatusLive      MeetingStatus = "live"
+	MeetingStatusEnded     MeetingStatus = "ended"
+	MeetingStatusCancelled MeetingStatus = "cancelled"
+)
+
+// Meeting is either an instant meeting (no start time) or a scheduled one.
+// StartTime and EndTime are stored in UTC; TimeZone is the IANA zone the
+// meeting was planned in, used to present the times back to people.
+// A scheduled meeting with a RecurrenceRule (RFC 5545 RRULE) repeats: StartTime
+// and EndTime describe its first occurrence and every occurrence reuses the code.
 type Meeting struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	CreatorID   uint      `gorm:"not null" json:"creator_id"`
-	MeetingCode string    `gorm:"unique;not null;size:14" json:"meeting_code"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	CreatorID   uint          `gorm:"not null" json:"creator_id"`
+	MeetingCode string        `gorm:"unique;not null;size:14" json:"meeting_code"`
+	Title       string        `gorm:"size:255" json:"title"`
+	Description string        `gorm:"type:text" json:"description"`
+	StartTime   *time.Time    `gorm:"index" json:"start_time"`
+	EndTime     *time.Time    `json:"end_time"`
+	TimeZone    string        `gorm:"size:64;not null;default:'UTC'" json:"time_zone"`
+	Status      MeetingStatus `gorm:"size:20;not null;default:'scheduled';index" json:"status"`
+
+	RecurrenceRule string    `gorm:"size:500;not null;default:''" json:"recurrence_rule"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 
 	// Relations
 	Creator User `gorm:"foreignKey:CreatorID" json:"creator,omitempty"`
+
+	// NextOccurrence is filled in when listing recurring meetings; it is not stored
+	NextOccurrence *MeetingOccurrence `gorm:"-" json:"next_occurrence,omitempty"`
+}
+
+// IsScheduled reports whether the meeting has a planned start time
+func (m *Meeting) IsScheduled() bool {
+	return m.StartTime != nil
+}
+
+// IsRecurring reports whether the meeting repeats
+func (m *Meeting) IsRecurring() bool {
+	return m.StartTime != nil && m.RecurrenceRule != ""
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// MeetingAttendance is one interval a participant spent in a meeting's LiveKit room,
+// recorded from LiveKit webhooks. A participant who reconnects gets a new interval.
+// LeftAt is nil while the participant is still in the room.
+type MeetingAttendance struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	MeetingID      uint       `gorm:"not null;index" json:"meeting_id"`
+	RoomSID        string     `gorm:"column:room_sid;size:64;not null;index" json:"room_sid"`
+	ParticipantSID string     `gorm:"column:participant_sid;size:64;not null;uniqueIndex" json:"participant_sid"`
+	Identity       string     `gorm:"size:255;not null" json:"identity"`
+	Name           string     `gorm:"size:255;not null" json:"name"`
+	UserID         *uint      `gorm:"index" json:"user_id,omitempty"`
+	JoinedAt       time.Time  `gorm:"not null" json:"joined_at"`
+	LeftAt         *time.Time `json:"left_at,omitempty"`
+	CreatedAt      time.Time  `json:"-"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// MeetingBan blocks a participant from rejoining a meeting.
+// A ban matches on any of its non-empty fields: the user account, the email
+// address, the guest device fingerprint or the exact LiveKit identity.
+//
+// Only bans on an account or an email address are enforced reliably. Guests are
+// matched on a fingerprint generated by their browser and on an identity made of
+// their name and connection ID, so a guest who clears the fingerprint and joins
+// under a new name gets past the ban. Guest bans are best-effort.
+type MeetingBan struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	MeetingID   uint      `gorm:"not null;index" json:"meeting_id"`
+	UserID      *uint     `json:"user_id,omitempty"`
+	Email       string    `gorm:"size:320" json:"email,omitempty"`
+	Fingerprint string    `gorm:"size:255" json:"fingerprint,omitempty"`
+	Identity    string    `gorm:"size:255" json:"identity,omitempty"`
+	Name        string    `gorm:"size:255" json:"name"`
+	Reason      string    `gorm:"type:text" json:"reason,omitempty"`
+	BannedBy    uint      `gorm:"not null" json:"banned_by"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
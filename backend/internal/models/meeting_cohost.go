@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// MeetingCohost is a registered user the meeting creator delegated hosting rights to.
+// Co-hosts may manage the lobby, moderate participants, end the meeting and run the summarizer.
+type MeetingCohost struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	MeetingID uint      `gorm:"not null;index" json:"meeting_id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+	User    User    `gorm:"foreignKey:UserID" json:"user"`
+}
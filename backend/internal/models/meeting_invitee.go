@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// MeetingInvitee is an email address invited to a meeting by its creator.
+// RemindedFor is the start of the last occurrence the invitee was reminded of.
+// Role, when set, is the role the invitee joins with instead of the meeting default.
+type MeetingInvitee struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	MeetingID   uint            `gorm:"not null;index" json:"meeting_id"`
+	Email       string          `gorm:"not null;size:320" json:"email"`
+	RSVPStatus  RSVPStatus      `gorm:"column:rsvp_status;not null;default:pending;size:20" json:"rsvp_status"`
+	RespondedAt *time.Time      `json:"responded_at,omitempty"`
+	InvitedAt   *time.Time      `json:"invited_at,omitempty"`
+	RemindedFor *time.Time      `json:"-"`
+	Role        ParticipantRole `gorm:"size:20;not null;default:''" json:"role,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
+
+// RSVPStatus is an invitee's answer to a meeting invitation
+type RSVPStatus string
+
+const (
+	RSVPPending   RSVPStatus = "pending"
+	RSVPAccepted  RSVPStatus = "accepted"
+	RSVPDeclined  RSVPStatus = "declined"
+	RSVPTentative RSVPStatus = "tentative"
+)
+
+// IsValidResponse reports whether the status is an answer an invitee can give
+func (r RSVPStatus) IsValidResponse() bool {
+	return r == RSVPAccepted || r == RSVPDeclined || r == RSVPTentative
+}
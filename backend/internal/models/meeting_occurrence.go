@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// MeetingOccurrenceOverride changes or cancels a single occurrence of a recurring meeting.
+// The occurrence is identified by the start time the rule gives it (OccurrenceStart, UTC).
+type MeetingOccurrenceOverride struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	MeetingID       uint       `gorm:"not null;index" json:"meeting_id"`
+	OccurrenceStart time.Time  `gorm:"not null" json:"occurrence_start"`
+	Cancelled       bool       `gorm:"not null;default:false" json:"cancelled"`
+	StartTime       *time.Time `json:"start_time"`
+	EndTime         *time.Time `json:"end_time"`
+	Title           string     `gorm:"size:255" json:"title"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
+
+// MeetingOccurrence is one expanded occurrence of a scheduled meeting. It is computed
+// from the meeting's recurrence rule and overrides, never stored.
+// OccurrenceID is the unix time of the start the rule gives the occurrence, and stays
+// stable when the occurrence is moved by an override.
+type MeetingOccurrence struct {
+	OccurrenceID int64      `json:"occurrence_id"`
+	StartTime    time.Time  `json:"start_time"`
+	EndTime      *time.Time `json:"end_time"`
+	Title        string     `json:"title"`
+	Cancelled    bool       `json:"cancelled"`
+	Overridden   bool       `json:"overridden"`
+}
+
+// OriginalStart returns the start time the rule gives the occurrence
+func (o *MeetingOccurrence) OriginalStart() time.Time {
+	return time.Unix(o.OccurrenceID, 0).UTC()
+}
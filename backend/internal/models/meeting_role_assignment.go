@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// MeetingRoleAssignment is the role a host gave a registered user during a meeting.
+// It outlasts the call so that the user gets it back when they rejoin.
+type MeetingRoleAssignment struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	MeetingID uint            `gorm:"not null;index" json:"meeting_id"`
+	UserID    uint            `gorm:"not null;index" json:"user_id"`
+	Role      ParticipantRole `gorm:"size:20;not null" json:"role"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
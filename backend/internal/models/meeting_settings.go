@@ -0,0 +1,119 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// MeetingSettings holds the per-meeting configuration managed by the meeting creator.
+// A meeting without a settings row behaves as if it had the default values.
+// AllowedDomains is a comma-separated list such as "acme.com,acme.io", and a
+// LobbyTimeoutSeconds of 0 means the server default wait applies. With
+// AutoSummarize the summarizer starts when the first participant joins, and with
+// ConsentMode participants are told about it and asked for their consent.
+// Passcode holds a bcrypt hash; MaxParticipants of 0 means no limit. DefaultRole
+// is the role of participants who are not hosts, empty for "user" or "guest".
+type MeetingSettings struct {
+	ID                  uint             `gorm:"primaryKey" json:"-"`
+	MeetingID           uint             `gorm:"uniqueIndex;not null" json:"meeting_id"`
+	AdmissionPolicy     AdmissionPolicy  `gorm:"not null;default:manual;size:20" json:"admission_policy"`
+	AllowedDomains      string           `gorm:"type:text;not null;default:''" json:"-"`
+	LobbyTimeoutSeconds int              `gorm:"not null;default:0" json:"lobby_timeout_seconds"`
+	AutoSummarize       bool             `gorm:"not null;default:false" json:"auto_summarize"`
+	ConsentMode         bool             `gorm:"not null;default:false" json:"consent_mode"`
+	LobbyRequired       LobbyRequirement `gorm:"not null;default:none;size:20" json:"lobby_required"`
+	Passcode            string           `gorm:"size:255;not null;default:''" json:"-"`
+	AllowGuests         bool             `gorm:"not null;default:true" json:"allow_guests"`
+	MaxParticipants     int              `gorm:"not null;default:0" json:"max_participants"`
+	Locked              bool             `gorm:"not null;default:false" json:"locked"`
+	SummarizerAllowed   bool             `gorm:"not null;default:true" json:"summarizer_allowed"`
+	DefaultRole         ParticipantRole  `gorm:"size:20;not null;default:''" json:"default_role"`
+	CreatedAt           time.Time        `json:"-"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
+
+// AdmissionPolicy decides which participants skip the lobby and get a LiveKit token immediately.
+// The meeting creator is always admitted regardless of the policy.
+type AdmissionPolicy string
+
+const (
+	AdmissionManual        AdmissionPolicy = "manual"        // Everyone waits for the host (default)
+	AdmissionAuthenticated AdmissionPolicy = "authenticated" // Signed-in users are admitted, only guests wait in the lobby
+	AdmissionDomain        AdmissionPolicy = "domain"        // Signed-in users whose email domain is in AllowedDomains or who are invited are admitted
+	AdmissionInvited       AdmissionPolicy = "invited"       // Signed-in users whose email is on the invitee list are admitted
+	AdmissionEveryone      AdmissionPolicy = "everyone"      // Nobody waits, the lobby is disabled
+)
+
+// IsValid reports whether the policy is one of the known values
+func (p AdmissionPolicy) IsValid() bool {
+	switch p {
+	case AdmissionManual, AdmissionAuthenticated, AdmissionDomain, AdmissionInvited, AdmissionEveryone:
+		return true
+	}
+	return false
+}
+
+// LobbyRequirement decides who must go through the lobby instead of asking
+// /livekit/token directly. Participants the admission policy admits never need to.
+type LobbyRequirement string
+
+const (
+	LobbyRequiredNone      LobbyRequirement = "none"      // Anyone may get a token directly (default)
+	LobbyRequiredGuests    LobbyRequirement = "guests"    // Guests need an admission ticket from the lobby
+	LobbyRequiredUninvited LobbyRequirement = "uninvited" // Guests and signed-in users who are not invited need a ticket
+)
+
+// IsValid reports whether the requirement is one of the known values
+func (r LobbyRequirement) IsValid() bool {
+	switch r {
+	case LobbyRequiredNone, LobbyRequiredGuests, LobbyRequiredUninvited:
+		return true
+	}
+	return false
+}
+
+// HasPasscode reports whether joining the meeting requires a passcode
+func (s *MeetingSettings) HasPasscode() bool {
+	return s.Passcode != ""
+}
+
+// Domains returns the allowed email domains as a slice
+func (s *MeetingSettings) Domains() []string {
+	domains := []string{}
+	for _, d := range strings.Split(s.AllowedDomains, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// SetDomains normalizes and stores the allowed email domains
+func (s *MeetingSettings) SetDomains(domains []string) {
+	normalized := make([]string, 0, len(domains))
+	for _, d := range domains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "@")
+		if d != "" {
+			normalized = append(normalized, d)
+		}
+	}
+	s.AllowedDomains = strings.Join(normalized, ",")
+}
+
+// AllowsDomain reports whether the email belongs to one of the allowed domains
+func (s *MeetingSettings) AllowsDomain(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range s.Domains() {
+		if domain == d {
+			return true
+		}
+	}
+	return false
+}
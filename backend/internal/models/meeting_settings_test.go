@@ -0,0 +1,47 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetDomains(t *testing.T) {
+	settings := &MeetingSettings{}
+	settings.SetDomains([]string{" Acme.com ", "@acme.io", "", "  "})
+
+	if want := []string{"acme.com", "acme.io"}; !reflect.DeepEqual(settings.Domains(), want) {
+		t.Errorf("Domains() = %v, want %v", settings.Domains(), want)
+	}
+}
+
+func TestAllowsDomain(t *testing.T) {
+	settings := &MeetingSettings{}
+	settings.SetDomains([]string{"acme.com", "@Acme.io"})
+
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"alice@acme.com", true},
+		{"alice@ACME.COM", true},
+		{"bob@acme.io", true},
+		{"bob@sub.acme.com", false},
+		{"bob@acme.com.evil.com", false},
+		{"bob@notacme.com", false},
+		{"acme.com@evil.com", false},
+		{"acme.com", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := settings.AllowsDomain(tt.email); got != tt.want {
+				t.Errorf("AllowsDomain(%q) = %v, want %v", tt.email, got, tt.want)
+			}
+		})
+	}
+
+	if (&MeetingSettings{}).AllowsDomain("alice@acme.com") {
+		t.Error("settings without domains allow a domain")
+	}
+}
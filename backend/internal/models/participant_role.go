@@ -0,0 +1,30 @@
+package models
+
+// ParticipantRole is the role carried in a participant's LiveKit metadata. It
+// decides the permissions of their LiveKit token.
+type ParticipantRole string
+
+const (
+	RoleHost        ParticipantRole = "admin"     // The meeting creator
+	RoleCohost      ParticipantRole = "cohost"    // A co-host of the meeting
+	RolePresenter   ParticipantRole = "presenter" // Publishes camera, microphone and screen share
+	RoleParticipant ParticipantRole = "user"      // Signed-in participant, publishes camera, microphone and screen share (default)
+	RoleGuest       ParticipantRole = "guest"     // Guest participant, same permissions as RoleParticipant (default)
+	RoleAttendee    ParticipantRole = "attendee"  // Listen-only: subscribes and chats, publishes no media
+	RoleViewer      ParticipantRole = "viewer"    // Subscribes only and is hidden from the participant list
+)
+
+// IsAssignable reports whether hosts may give the role to a participant directly.
+// Host and co-host rights are granted through the meeting's co-host list instead.
+func (r ParticipantRole) IsAssignable() bool {
+	switch r {
+	case RolePresenter, RoleParticipant, RoleGuest, RoleAttendee, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// IsHost reports whether the role carries hosting rights
+func (r ParticipantRole) IsHost() bool {
+	return r == RoleHost || r == RoleCohost
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Poll is a question hosts ask the participants of a live meeting.
+// Status flow: DRAFT → OPEN → CLOSED; votes are accepted while OPEN.
+type Poll struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	MeetingID      uint       `gorm:"not null;index" json:"meeting_id"`
+	CreatedBy      uint       `gorm:"not null" json:"created_by"`
+	Question       string     `gorm:"type:text;not null" json:"question"`
+	MultipleChoice bool       `gorm:"not null;default:false" json:"multiple_choice"`
+	Anonymous      bool       `gorm:"not null;default:false" json:"anonymous"` // Voters are not shown with the results
+	Status         PollStatus `gorm:"size:20;not null;default:DRAFT" json:"status"`
+	OpenedAt       *time.Time `json:"opened_at,omitempty"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"-"`
+
+	// Relations
+	Options []PollOption `gorm:"foreignKey:PollID" json:"options"`
+	Meeting Meeting      `gorm:"foreignKey:MeetingID" json:"-"`
+}
+
+type PollStatus string
+
+const (
+	PollStatusDraft  PollStatus = "DRAFT"
+	PollStatusOpen   PollStatus = "OPEN"
+	PollStatusClosed PollStatus = "CLOSED"
+)
+
+// PollOption is one of the answers of a poll, in display order
+type PollOption struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	PollID   uint   `gorm:"not null;index" json:"-"`
+	Position int    `gorm:"not null" json:"position"`
+	Text     string `gorm:"size:255;not null" json:"text"`
+}
+
+// PollVote is a participant choosing an option. Voters are identified by their
+// LiveKit identity, so guests can vote; UserID is set for signed-in users.
+type PollVote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PollID    uint      `gorm:"not null;index" json:"poll_id"`
+	OptionID  uint      `gorm:"not null" json:"option_id"`
+	Identity  string    `gorm:"size:255;not null" json:"identity"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	UserID    *uint     `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Recording is a LiveKit Egress job recording a meeting room, or one track of it.
+// Status flow: STARTING → ACTIVE → ENDING → COMPLETE; FAILED, ABORTED or
+// LIMIT_REACHED when it did not finish normally. Egress webhooks drive the status.
+type Recording struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	MeetingID uint            `gorm:"not null;index" json:"meeting_id"`
+	StartedBy uint            `gorm:"not null" json:"started_by"`
+	EgressID  string          `gorm:"size:100;not null;uniqueIndex" json:"egress_id"`
+	Type      RecordingType   `gorm:"size:20;not null" json:"type"`
+	Status    RecordingStatus `gorm:"size:20;not null;default:STARTING" json:"status"`
+	// RoomName is the breakout room recorded; empty for the main room
+	RoomName string `gorm:"not null;default:''" json:"room_name,omitempty"`
+	// TrackID and ParticipantIdentity are set for track recordings
+	TrackID             string `gorm:"size:100;not null;default:''" json:"track_id,omitempty"`
+	ParticipantIdentity string `gorm:"size:255;not null;default:''" json:"participant_identity,omitempty"`
+	AudioOnly           bool   `gorm:"not null;default:false" json:"audio_only"`
+	// Storage is where the file is kept ("local" or "s3") and FilePath its path
+	// under the recording directory, or its key in the bucket
+	Storage         string     `gorm:"size:20;not null" json:"storage"`
+	FilePath        string     `gorm:"type:text;not null;default:''" json:"file_path,omitempty"`
+	SizeBytes       int64      `gorm:"not null;default:0" json:"size_bytes"`
+	DurationSeconds float64    `gorm:"not null;default:0" json:"duration_seconds"`
+	Error           *string    `json:"error,omitempty"`
+	StartedAt       time.Time  `gorm:"not null" json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"-"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
+
+type RecordingType string
+
+const (
+	RecordingTypeRoomComposite RecordingType = "room_composite"
+	RecordingTypeTrack         RecordingType = "track"
+)
+
+type RecordingStatus string
+
+const (
+	RecordingStatusStarting     RecordingStatus = "STARTING"
+	RecordingStatusActive       RecordingStatus = "ACTIVE"
+	RecordingStatusEnding       RecordingStatus = "ENDING"
+	RecordingStatusComplete     RecordingStatus = "COMPLETE"
+	RecordingStatusFailed       RecordingStatus = "FAILED"
+	RecordingStatusAborted      RecordingStatus = "ABORTED"
+	RecordingStatusLimitReached RecordingStatus = "LIMIT_REACHED"
+)
+
+// Progress orders statuses the way an egress goes through them. The statuses an
+// egress ends with share the last step; unknown statuses come before all others.
+func (s RecordingStatus) Progress() int {
+	switch s {
+	case RecordingStatusStarting:
+		return 0
+	case RecordingStatusActive:
+		return 1
+	case RecordingStatusEnding:
+		return 2
+	case RecordingStatusComplete, RecordingStatusFailed, RecordingStatusAborted, RecordingStatusLimitReached:
+		return 3
+	}
+	return -1
+}
+
+// IsActive reports whether the recording may still be capturing
+func (r *Recording) IsActive() bool {
+	return r.Status == RecordingStatusStarting || r.Status == RecordingStatusActive
+}
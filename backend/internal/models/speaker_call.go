@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// SpeakerCall records a host calling on a participant to speak, usually the next
+// raised hand in the queue. RaisedAt is nil when the participant was called on
+// without raising their hand. RoomName is the breakout room; empty for the main room.
+type SpeakerCall struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	MeetingID uint       `gorm:"not null;index" json:"meeting_id"`
+	RoomName  string     `gorm:"size:64;not null;default:''" json:"room_name,omitempty"`
+	Identity  string     `gorm:"size:255;not null" json:"identity"`
+	Name      string     `gorm:"size:255;not null" json:"name"`
+	UserID    *uint      `gorm:"index" json:"user_id,omitempty"`
+	CalledBy  uint       `gorm:"not null" json:"called_by"`
+	RaisedAt  *time.Time `json:"raised_at,omitempty"`
+	CalledAt  time.Time  `gorm:"not null" json:"called_at"`
+	CreatedAt time.Time  `json:"-"`
+
+	// Relations
+	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SummarizerConsent is a participant's answer to being transcribed during a
+// summarizer session. Audio from participants who declined is not captured.
+type SummarizerConsent struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	SessionID   uint      `gorm:"not null;uniqueIndex:idx_summarizer_consents_session_identity" json:"-"`
+	Identity    string    `gorm:"size:255;not null;uniqueIndex:idx_summarizer_consents_session_identity" json:"identity"`
+	UserID      *uint     `json:"user_id,omitempty"`
+	Consented   bool      `gorm:"not null" json:"consented"`
+	RespondedAt time.Time `gorm:"not null" json:"responded_at"`
+
+	// Relations
+	Session SummarizerSession `gorm:"foreignKey:SessionID" json:"-"`
+}
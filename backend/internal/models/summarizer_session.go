@@ -4,7 +4,9 @@ import "time"
 
 // SummarizerSession represents a meeting summarization session
 // Status flow: STARTED → CAPTURED → TRANSCRIBED → NORMALIZED → SUMMARIZED
-// On failure, status remains at the failed stage and the Error field is populated
+// Imported recordings start at IMPORTING instead of STARTED.
+// On failure, status remains at the failed stage and the Error field is populated,
+// except for imports that could not be prepared, which end up FAILED
 type SummarizerSession struct {
 	ID         uint                    `gorm:"primaryKey" json:"id"`
 	MeetingID  uint                    `gorm:"not null" json:"-"`
@@ -18,6 +20,13 @@ type SummarizerSession struct {
 	CreatedAt  time.Time               `json:"-"`
 	UpdatedAt  time.Time               `json:"-"`
 
+	// OccurrenceStart links the session to the occurrence of a scheduled meeting it
+	// recorded (the occurrence's original start, UTC). Nil for instant meetings.
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty"`
+
+	// RoomName is the breakout room the session captured; empty for the main room
+	RoomName string `gorm:"not null;default:''" json:"room_name,omitempty"`
+
 	// Relations
 	Meeting Meeting `gorm:"foreignKey:MeetingID" json:"-"`
 	User    User    `gorm:"foreignKey:UserID" json:"-"`
@@ -28,9 +37,11 @@ type SummarizerSession struct {
 type SummarizerSessionStatus string
 
 const (
+	StatusImporting   SummarizerSessionStatus = "IMPORTING"   // Imported recording being fetched and split into chunks
 	StatusStarted     SummarizerSessionStatus = "STARTED"     // Bot joined, capturing audio
 	StatusCaptured    SummarizerSessionStatus = "CAPTURED"    // Audio captured, ready for transcription
 	StatusTranscribed SummarizerSessionStatus = "TRANSCRIBED" // Text extracted, ready for normalization
 	StatusNormalized  SummarizerSessionStatus = "NORMALIZED"  // Text normalized, ready for summarization
 	StatusSummarized  SummarizerSessionStatus = "SUMMARIZED"  // Final summary generated
+	StatusFailed      SummarizerSessionStatus = "FAILED"      // Imported recording could not be prepared
 )
@@ -3,15 +3,17 @@ package models
 import "time"
 
 type User struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	Email      string    `gorm:"unique;not null" json:"email"`
-	Name       string    `gorm:"not null" json:"name"`
-	Role       string    `gorm:"default:'user';not null" json:"role"`
-	Provider   Provider  `gorm:"not null" json:"provider"`
-	ProviderID string    `gorm:"index" json:"-"`
-	AvatarURL  string    `gorm:"" json:"avatar_url"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"-"`
+	ID         uint     `gorm:"primaryKey" json:"id"`
+	Email      string   `gorm:"unique;not null" json:"email"`
+	Name       string   `gorm:"not null" json:"name"`
+	Role       string   `gorm:"default:'user';not null" json:"role"`
+	Provider   Provider `gorm:"not null" json:"provider"`
+	ProviderID string   `gorm:"index" json:"-"`
+	AvatarURL  string   `gorm:"" json:"avatar_url"`
+	// CalendarToken is the secret in the user's calendar feed URL
+	CalendarToken *string   `gorm:"size:64;uniqueIndex" json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"-"`
 }
 
 type Provider string
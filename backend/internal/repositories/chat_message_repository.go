@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ChatMessageRepository struct {
+	db *gorm.DB
+}
+
+func NewChatMessageRepository(db *gorm.DB) *ChatMessageRepository {
+	return &ChatMessageRepository{db: db}
+}
+
+// Create records a message, ignoring a message already recorded
+func (r *ChatMessageRepository) Create(message *models.ChatMessage) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(message).Error
+}
+
+// UpdateText records an edit of a message identified by its sender and client message ID
+func (r *ChatMessageRepository) UpdateText(meetingID uint, identity string, messageID string, text string, editedAt time.Time) (int64, error) {
+	result := r.db.Model(&models.ChatMessage{}).
+		Where("meeting_id = ? AND identity = ? AND message_id = ?", meetingID, identity, messageID).
+		Updates(map[string]interface{}{
+			"message":   text,
+			"edited_at": editedAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// FindByMeetingIDPaginated returns the messages of a meeting in the order they were sent
+func (r *ChatMessageRepository) FindByMeetingIDPaginated(meetingID uint, page, pageSize int) ([]models.ChatMessage, int64, error) {
+	var messages []models.ChatMessage
+	var total int64
+
+	query := r.db.Model(&models.ChatMessage{}).Where("meeting_id = ?", meetingID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Order("sent_at ASC, id ASC").Offset(offset).Limit(pageSize).Find(&messages).Error
+	return messages, total, err
+}
+
+// FindByRoomBetween returns the messages sent in one room of a meeting between
+// two times, where an empty room name is the main room
+func (r *ChatMessageRepository) FindByRoomBetween(meetingID uint, roomName string, from, to time.Time) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	err := r.db.Where("meeting_id = ? AND room_name = ? AND sent_at >= ? AND sent_at <= ?", meetingID, roomName, from, to).
+		Order("sent_at ASC, id ASC").Find(&messages).Error
+	return messages, err
+}
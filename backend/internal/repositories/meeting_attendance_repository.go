@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MeetingAttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingAttendanceRepository(db *gorm.DB) *MeetingAttendanceRepository {
+	return &MeetingAttendanceRepository{db: db}
+}
+
+// Create records a join, ignoring webhooks delivered twice for the same participant
+func (r *MeetingAttendanceRepository) Create(attendance *models.MeetingAttendance) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(attendance).Error
+}
+
+// CloseByParticipantSID records when a participant left, if not recorded already
+func (r *MeetingAttendanceRepository) CloseByParticipantSID(participantSID string, leftAt time.Time) (int64, error) {
+	result := r.db.Model(&models.MeetingAttendance{}).
+		Where("participant_sid = ? AND left_at IS NULL", participantSID).
+		Update("left_at", leftAt)
+	return result.RowsAffected, result.Error
+}
+
+// CloseOpenByRoomSID ends every interval still open in a room session
+func (r *MeetingAttendanceRepository) CloseOpenByRoomSID(roomSID string, leftAt time.Time) error {
+	return r.db.Model(&models.MeetingAttendance{}).
+		Where("room_sid = ? AND left_at IS NULL", roomSID).
+		Update("left_at", leftAt).Error
+}
+
+func (r *MeetingAttendanceRepository) FindByMeetingID(meetingID uint) ([]models.MeetingAttendance, error) {
+	var attendances []models.MeetingAttendance
+	err := r.db.Where("meeting_id = ?", meetingID).Order("joined_at ASC").Find(&attendances).Error
+	return attendances, err
+}
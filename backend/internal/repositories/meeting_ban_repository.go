@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MeetingBanRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingBanRepository(db *gorm.DB) *MeetingBanRepository {
+	return &MeetingBanRepository{db: db}
+}
+
+func (r *MeetingBanRepository) Create(ban *models.MeetingBan) error {
+	return r.db.Create(ban).Error
+}
+
+func (r *MeetingBanRepository) FindByMeetingID(meetingID uint) ([]models.MeetingBan, error) {
+	var bans []models.MeetingBan
+	err := r.db.Where("meeting_id = ?", meetingID).Order("created_at DESC").Find(&bans).Error
+	return bans, err
+}
+
+// ExistsMatching reports whether any ban of the meeting matches one of the given attributes.
+// Empty attributes are ignored.
+func (r *MeetingBanRepository) ExistsMatching(meetingID uint, userID uint, email, fingerprint, identity string) (bool, error) {
+	conditions := r.db.Where("1 = 0")
+	if userID > 0 {
+		conditions = conditions.Or("user_id = ?", userID)
+	}
+	if email != "" {
+		conditions = conditions.Or("email <> '' AND LOWER(email) = LOWER(?)", email)
+	}
+	if fingerprint != "" {
+		conditions = conditions.Or("fingerprint <> '' AND fingerprint = ?", fingerprint)
+	}
+	if identity != "" {
+		conditions = conditions.Or("identity <> '' AND identity = ?", identity)
+	}
+
+	var count int64
+	err := r.db.Model(&models.MeetingBan{}).
+		Where("meeting_id = ?", meetingID).
+		Where(conditions).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MeetingBanRepository) Delete(meetingID uint, id uint) (int64, error) {
+	result := r.db.Where("meeting_id = ?", meetingID).Delete(&models.MeetingBan{}, id)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MeetingCohostRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingCohostRepository(db *gorm.DB) *MeetingCohostRepository {
+	return &MeetingCohostRepository{db: db}
+}
+
+// Create adds a co-host, doing nothing if the user already co-hosts the meeting
+func (r *MeetingCohostRepository) Create(cohost *models.MeetingCohost) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(cohost).Error
+}
+
+func (r *MeetingCohostRepository) FindByMeetingID(meetingID uint) ([]models.MeetingCohost, error) {
+	var cohosts []models.MeetingCohost
+	err := r.db.Preload("User").Where("meeting_id = ?", meetingID).Order("created_at ASC").Find(&cohosts).Error
+	return cohosts, err
+}
+
+func (r *MeetingCohostRepository) ExistsByMeetingAndUser(meetingID uint, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.MeetingCohost{}).
+		Where("meeting_id = ? AND user_id = ?", meetingID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *MeetingCohostRepository) Delete(meetingID uint, userID uint) (int64, error) {
+	result := r.db.Where("meeting_id = ? AND user_id = ?", meetingID, userID).Delete(&models.MeetingCohost{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MeetingInviteeRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingInviteeRepository(db *gorm.DB) *MeetingInviteeRepository {
+	return &MeetingInviteeRepository{db: db}
+}
+
+// CreateMany inserts the invitees, skipping emails already invited to the meeting
+func (r *MeetingInviteeRepository) CreateMany(invitees []models.MeetingInvitee) error {
+	if len(invitees) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&invitees).Error
+}
+
+func (r *MeetingInviteeRepository) FindByMeetingID(meetingID uint) ([]models.MeetingInvitee, error) {
+	var invitees []models.MeetingInvitee
+	err := r.db.Where("meeting_id = ?", meetingID).Order("created_at ASC").Find(&invitees).Error
+	return invitees, err
+}
+
+func (r *MeetingInviteeRepository) FindByID(id uint) (*models.MeetingInvitee, error) {
+	var invitee models.MeetingInvitee
+	err := r.db.First(&invitee, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitee, nil
+}
+
+func (r *MeetingInviteeRepository) FindByMeetingAndEmail(meetingID uint, email string) (*models.MeetingInvitee, error) {
+	var invitee models.MeetingInvitee
+	err := r.db.Where("meeting_id = ? AND LOWER(email) = LOWER(?)", meetingID, email).First(&invitee).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitee, nil
+}
+
+// FindUninvitedByMeetingID returns the invitees of a meeting that were not sent an invitation yet
+func (r *MeetingInviteeRepository) FindUninvitedByMeetingID(meetingID uint) ([]models.MeetingInvitee, error) {
+	var invitees []models.MeetingInvitee
+	err := r.db.Where("meeting_id = ? AND invited_at IS NULL", meetingID).Order("created_at ASC").Find(&invitees).Error
+	return invitees, err
+}
+
+// FindToRemind returns the invitees of a meeting that did not decline and were not
+// reminded of the occurrence starting at occurrenceStart yet
+func (r *MeetingInviteeRepository) FindToRemind(meetingID uint, occurrenceStart time.Time) ([]models.MeetingInvitee, error) {
+	var invitees []models.MeetingInvitee
+	err := r.db.Where("meeting_id = ? AND rsvp_status <> ?", meetingID, models.RSVPDeclined).
+		Where("reminded_for IS NULL OR reminded_for <> ?", occurrenceStart).
+		Find(&invitees).Error
+	return invitees, err
+}
+
+func (r *MeetingInviteeRepository) UpdateRSVP(id uint, status models.RSVPStatus, respondedAt time.Time) error {
+	return r.db.Model(&models.MeetingInvitee{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"rsvp_status": status, "responded_at": respondedAt}).Error
+}
+
+// ClaimInvitation marks an invitee invited unless it already was, and reports
+// whether this call did, so that only one instance emails the invitation
+func (r *MeetingInviteeRepository) ClaimInvitation(id uint, invitedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.MeetingInvitee{}).
+		Where("id = ? AND invited_at IS NULL", id).
+		Update("invited_at", invitedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReleaseInvitation undoes ClaimInvitation after the invitation could not be sent
+func (r *MeetingInviteeRepository) ReleaseInvitation(id uint) error {
+	return r.db.Model(&models.MeetingInvitee{}).Where("id = ?", id).Update("invited_at", nil).Error
+}
+
+// ClaimReminder marks an invitee reminded of the occurrence starting at
+// occurrenceStart unless it already was, and reports whether this call did, so
+// that only one instance emails the reminder
+func (r *MeetingInviteeRepository) ClaimReminder(id uint, occurrenceStart time.Time) (bool, error) {
+	result := r.db.Model(&models.MeetingInvitee{}).
+		Where("id = ? AND (reminded_for IS NULL OR reminded_for <> ?)", id, occurrenceStart).
+		Update("reminded_for", occurrenceStart)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReleaseReminder undoes ClaimReminder after the reminder could not be sent,
+// restoring the occurrence the invitee was reminded of before
+func (r *MeetingInviteeRepository) ReleaseReminder(id uint, occurrenceStart time.Time, previous *time.Time) error {
+	return r.db.Model(&models.MeetingInvitee{}).
+		Where("id = ? AND reminded_for = ?", id, occurrenceStart).
+		Update("reminded_for", previous).Error
+}
+
+// ExistsAttendingByMeetingAndEmail reports whether email is invited to a meeting
+// and did not decline the invitation
+func (r *MeetingInviteeRepository) ExistsAttendingByMeetingAndEmail(meetingID uint, email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.MeetingInvitee{}).
+		Where("meeting_id = ? AND LOWER(email) = LOWER(?)", meetingID, email).
+		Where("rsvp_status <> ?", models.RSVPDeclined).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateRole sets the role of an invitee of a meeting
+func (r *MeetingInviteeRepository) UpdateRole(meetingID uint, id uint, role models.ParticipantRole) (int64, error) {
+	result := r.db.Model(&models.MeetingInvitee{}).
+		Where("meeting_id = ? AND id = ?", meetingID, id).
+		Update("role", role)
+	return result.RowsAffected, result.Error
+}
+
+func (r *MeetingInviteeRepository) Delete(meetingID uint, id uint) (int64, error) {
+	result := r.db.Where("meeting_id = ?", meetingID).Delete(&models.MeetingInvitee{}, id)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MeetingOccurrenceOverrideRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingOccurrenceOverrideRepository(db *gorm.DB) *MeetingOccurrenceOverrideRepository {
+	return &MeetingOccurrenceOverrideRepository{db: db}
+}
+
+func (r *MeetingOccurrenceOverrideRepository) FindByMeetingID(meetingID uint) ([]models.MeetingOccurrenceOverride, error) {
+	var overrides []models.MeetingOccurrenceOverride
+	err := r.db.Where("meeting_id = ?", meetingID).Order("occurrence_start ASC").Find(&overrides).Error
+	return overrides, err
+}
+
+// Save creates the override of an occurrence or replaces the existing one
+func (r *MeetingOccurrenceOverrideRepository) Save(override *models.MeetingOccurrenceOverride) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "meeting_id"}, {Name: "occurrence_start"}},
+		DoUpdates: clause.AssignmentColumns([]string{"cancelled", "start_time", "end_time", "title", "updated_at"}),
+	}).Create(override).Error
+}
+
+func (r *MeetingOccurrenceOverrideRepository) Delete(meetingID uint, occurrenceStart time.Time) (int64, error) {
+	result := r.db.Where("meeting_id = ? AND occurrence_start = ?", meetingID, occurrenceStart).
+		Delete(&models.MeetingOccurrenceOverride{})
+	return result.RowsAffected, result.Error
+}
@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"mini-meeting/internal/models"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MeetingRepository struct {
@@ -42,14 +44,79 @@ func (r *MeetingRepository) FindByCreatorID(creatorID uint) ([]models.Meeting, e
 	return meetings, err
 }
 
+// FindUpcomingByCreatorID returns one-off meetings that are not over yet, soonest first
+func (r *MeetingRepository) FindUpcomingByCreatorID(creatorID uint, now time.Time) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.Where("creator_id = ? AND recurrence_rule = ''", creatorID).
+		Where("status IN ?", []models.MeetingStatus{models.MeetingStatusScheduled, models.MeetingStatusLive}).
+		Where("end_time IS NULL OR end_time > ?", now).
+		Order("start_time ASC NULLS LAST, created_at DESC").
+		Find(&meetings).Error
+	return meetings, err
+}
+
+// FindPastByCreatorID returns cancelled meetings and one-off meetings that ended
+// or whose planned end has passed, latest first
+func (r *MeetingRepository) FindPastByCreatorID(creatorID uint, now time.Time) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.Where("creator_id = ?", creatorID).
+		Where("status = ? OR (recurrence_rule = '' AND (status = ? OR end_time <= ?))",
+			models.MeetingStatusCancelled, models.MeetingStatusEnded, now).
+		Order("COALESCE(start_time, created_at) DESC").
+		Find(&meetings).Error
+	return meetings, err
+}
+
+// FindRecurringByCreatorID returns the recurring meetings of a creator that were not cancelled
+func (r *MeetingRepository) FindRecurringByCreatorID(creatorID uint) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.Where("creator_id = ? AND recurrence_rule <> '' AND status <> ?", creatorID, models.MeetingStatusCancelled).
+		Order("start_time ASC").
+		Find(&meetings).Error
+	return meetings, err
+}
+
+// FindScheduledForUser returns the scheduled meetings a user creates, co-hosts
+// or is invited to by email
+func (r *MeetingRepository) FindScheduledForUser(userID uint, email string) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.Preload("Creator").Where("start_time IS NOT NULL").
+		Where(r.db.Where("creator_id = ?", userID).
+			Or("id IN (?)", r.db.Table("meeting_cohosts").Select("meeting_id").Where("user_id = ?", userID)).
+			Or("id IN (?)", r.db.Table("meeting_invitees").Select("meeting_id").Where("LOWER(email) = LOWER(?)", email))).
+		Order("start_time ASC").
+		Find(&meetings).Error
+	return meetings, err
+}
+
+// FindReminderCandidates returns the scheduled meetings that may have an occurrence
+// starting before until: one-off meetings starting in [now, until] and recurring ones
+func (r *MeetingRepository) FindReminderCandidates(now, until time.Time) ([]models.Meeting, error) {
+	var meetings []models.Meeting
+	err := r.db.Preload("Creator").
+		Where("status = ? AND start_time IS NOT NULL", models.MeetingStatusScheduled).
+		Where("recurrence_rule <> '' OR (start_time >= ? AND start_time <= ?)", now, until).
+		Find(&meetings).Error
+	return meetings, err
+}
+
 func (r *MeetingRepository) FindAll() ([]models.Meeting, error) {
 	var meetings []models.Meeting
 	err := r.db.Preload("Creator").Order("created_at DESC").Find(&meetings).Error
 	return meetings, err
 }
 
+// Update saves the meeting's own columns; the preloaded creator is left untouched
 func (r *MeetingRepository) Update(meeting *models.Meeting) error {
-	return r.db.Save(meeting).Error
+	return r.db.Omit(clause.Associations).Save(meeting).Error
+}
+
+// UpdateStatus moves a meeting to status if it is currently in one of the from states
+func (r *MeetingRepository) UpdateStatus(id uint, from []models.MeetingStatus, status models.MeetingStatus) (int64, error) {
+	result := r.db.Model(&models.Meeting{}).
+		Where("id = ? AND status IN ?", id, from).
+		Update("status", status)
+	return result.RowsAffected, result.Error
 }
 
 func (r *MeetingRepository) Delete(id uint) error {
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MeetingRoleAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingRoleAssignmentRepository(db *gorm.DB) *MeetingRoleAssignmentRepository {
+	return &MeetingRoleAssignmentRepository{db: db}
+}
+
+// Save stores the role of a user in a meeting, replacing the one they had
+func (r *MeetingRoleAssignmentRepository) Save(assignment *models.MeetingRoleAssignment) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "meeting_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(assignment).Error
+}
+
+func (r *MeetingRoleAssignmentRepository) FindByMeetingAndUser(meetingID uint, userID uint) (*models.MeetingRoleAssignment, error) {
+	var assignment models.MeetingRoleAssignment
+	err := r.db.Where("meeting_id = ? AND user_id = ?", meetingID, userID).First(&assignment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MeetingSettingsRepository struct {
+	db *gorm.DB
+}
+
+func NewMeetingSettingsRepository(db *gorm.DB) *MeetingSettingsRepository {
+	return &MeetingSettingsRepository{db: db}
+}
+
+func (r *MeetingSettingsRepository) FindByMeetingID(meetingID uint) (*models.MeetingSettings, error) {
+	var settings models.MeetingSettings
+	err := r.db.Where("meeting_id = ?", meetingID).First(&settings).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// Save creates the settings row or updates it if it already exists
+func (r *MeetingSettingsRepository) Save(settings *models.MeetingSettings) error {
+	return r.db.Save(settings).Error
+}
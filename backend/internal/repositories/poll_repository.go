@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PollRepository struct {
+	db *gorm.DB
+}
+
+func NewPollRepository(db *gorm.DB) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+// Create saves a poll with its options
+func (r *PollRepository) Create(poll *models.Poll) error {
+	return r.db.Create(poll).Error
+}
+
+func (r *PollRepository) FindByID(id uint) (*models.Poll, error) {
+	var poll models.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).First(&poll, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *PollRepository) FindByMeetingID(meetingID uint) ([]models.Poll, error) {
+	var polls []models.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("meeting_id = ?", meetingID).Order("created_at ASC").Find(&polls).Error
+	return polls, err
+}
+
+// FindOpenedBetween returns the polls of a meeting opened between two times
+func (r *PollRepository) FindOpenedBetween(meetingID uint, from, to time.Time) ([]models.Poll, error) {
+	var polls []models.Poll
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("meeting_id = ? AND opened_at >= ? AND opened_at <= ?", meetingID, from, to).
+		Order("opened_at ASC").Find(&polls).Error
+	return polls, err
+}
+
+// UpdateStatusFrom moves a poll from one status to another, setting the time
+// column the new status records, and returns how many polls were updated
+func (r *PollRepository) UpdateStatusFrom(id uint, from, status models.PollStatus, column string, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Poll{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status": status,
+			column:   at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ReplaceVotes replaces the votes of a participant in an open poll, and reports
+// false without voting when the poll is no longer open. Concurrent votes of the
+// same participant are serialized with a transaction-level advisory lock on
+// (poll, identity), so the last one wins instead of both being kept. The poll row
+// is share-locked, so a poll cannot be closed while a vote is being recorded.
+func (r *PollRepository) ReplaceVotes(pollID uint, identity string, votes []models.PollVote) (bool, error) {
+	open := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", int32(pollID), identity).Error; err != nil {
+			return err
+		}
+
+		var poll models.Poll
+		result := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+			Where("id = ? AND status = ?", pollID, models.PollStatusOpen).Limit(1).Find(&poll)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		open = true
+
+		if err := tx.Where("poll_id = ? AND identity = ?", pollID, identity).Delete(&models.PollVote{}).Error; err != nil {
+			return err
+		}
+		if len(votes) == 0 {
+			return nil
+		}
+		return tx.Create(&votes).Error
+	})
+	return open, err
+}
+
+func (r *PollRepository) FindVotes(pollID uint) ([]models.PollVote, error) {
+	var votes []models.PollVote
+	err := r.db.Where("poll_id = ?", pollID).Order("created_at ASC").Find(&votes).Error
+	return votes, err
+}
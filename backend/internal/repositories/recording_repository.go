@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RecordingRepository struct {
+	db *gorm.DB
+}
+
+func NewRecordingRepository(db *gorm.DB) *RecordingRepository {
+	return &RecordingRepository{db: db}
+}
+
+func (r *RecordingRepository) Create(recording *models.Recording) error {
+	return r.db.Create(recording).Error
+}
+
+func (r *RecordingRepository) Update(recording *models.Recording) error {
+	return r.db.Save(recording).Error
+}
+
+func (r *RecordingRepository) FindByID(id uint) (*models.Recording, error) {
+	var recording models.Recording
+	if err := r.db.First(&recording, id).Error; err != nil {
+		return nil, err
+	}
+	return &recording, nil
+}
+
+func (r *RecordingRepository) FindByEgressID(egressID string) (*models.Recording, error) {
+	var recording models.Recording
+	if err := r.db.Where("egress_id = ?", egressID).First(&recording).Error; err != nil {
+		return nil, err
+	}
+	return &recording, nil
+}
+
+// FindByMeetingID returns the recordings of a meeting, newest first
+func (r *RecordingRepository) FindByMeetingID(meetingID uint) ([]models.Recording, error) {
+	var recordings []models.Recording
+	err := r.db.Where("meeting_id = ?", meetingID).Order("started_at DESC").Find(&recordings).Error
+	return recordings, err
+}
+
+// FindActiveByMeetingID returns the recordings of a meeting that may still be capturing
+func (r *RecordingRepository) FindActiveByMeetingID(meetingID uint) ([]models.Recording, error) {
+	var recordings []models.Recording
+	err := r.db.Where("meeting_id = ? AND status IN ?", meetingID, []models.RecordingStatus{
+		models.RecordingStatusStarting,
+		models.RecordingStatusActive,
+	}).Find(&recordings).Error
+	return recordings, err
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SpeakerCallRepository struct {
+	db *gorm.DB
+}
+
+func NewSpeakerCallRepository(db *gorm.DB) *SpeakerCallRepository {
+	return &SpeakerCallRepository{db: db}
+}
+
+func (r *SpeakerCallRepository) Create(call *models.SpeakerCall) error {
+	return r.db.Create(call).Error
+}
+
+func (r *SpeakerCallRepository) FindByMeetingID(meetingID uint) ([]models.SpeakerCall, error) {
+	var calls []models.SpeakerCall
+	err := r.db.Where("meeting_id = ?", meetingID).Order("called_at ASC").Find(&calls).Error
+	return calls, err
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"mini-meeting/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SummarizerConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewSummarizerConsentRepository(db *gorm.DB) *SummarizerConsentRepository {
+	return &SummarizerConsentRepository{db: db}
+}
+
+// Save records a participant's answer, replacing any earlier answer in the same session
+func (r *SummarizerConsentRepository) Save(consent *models.SummarizerConsent) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}, {Name: "identity"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "consented", "responded_at"}),
+	}).Create(consent).Error
+}
+
+func (r *SummarizerConsentRepository) FindBySessionID(sessionID uint) ([]models.SummarizerConsent, error) {
+	var consents []models.SummarizerConsent
+	err := r.db.Where("session_id = ?", sessionID).Order("responded_at ASC").Find(&consents).Error
+	return consents, err
+}
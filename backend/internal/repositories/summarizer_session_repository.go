@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SummarizerSessionRepository struct {
@@ -19,6 +20,18 @@ func (r *SummarizerSessionRepository) Create(session *models.SummarizerSession)
 	return r.db.Create(session).Error
 }
 
+// CreateActive creates a STARTED session unless the room already has one, and
+// reports whether it was created. uq_summarizer_sessions_active_room makes this
+// hold across backend instances.
+func (r *SummarizerSessionRepository) CreateActive(session *models.SummarizerSession) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "meeting_id"}, {Name: "room_name"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'STARTED'"}}},
+		DoNothing:   true,
+	}).Create(session)
+	return result.RowsAffected == 1, result.Error
+}
+
 func (r *SummarizerSessionRepository) FindByID(id uint) (*models.SummarizerSession, error) {
 	var session models.SummarizerSession
 	err := r.db.Preload("Meeting").First(&session, id).Error
@@ -28,9 +41,16 @@ func (r *SummarizerSessionRepository) FindByID(id uint) (*models.SummarizerSessi
 	return &session, nil
 }
 
+// FindActiveByMeetingID returns the session capturing the main room of a meeting
 func (r *SummarizerSessionRepository) FindActiveByMeetingID(meetingID uint) (*models.SummarizerSession, error) {
+	return r.FindActiveByRoomName(meetingID, "")
+}
+
+// FindActiveByRoomName returns the session capturing one room of a meeting, where
+// an empty room name is the main room
+func (r *SummarizerSessionRepository) FindActiveByRoomName(meetingID uint, roomName string) (*models.SummarizerSession, error) {
 	var session models.SummarizerSession
-	err := r.db.Where("meeting_id = ? AND status = ?", meetingID, models.StatusStarted).
+	err := r.db.Where("meeting_id = ? AND room_name = ? AND status = ?", meetingID, roomName, models.StatusStarted).
 		First(&session).Error
 	if err != nil {
 		return nil, err
@@ -52,12 +72,49 @@ func (r *SummarizerSessionRepository) UpdateStatus(id uint, status models.Summar
 	return r.db.Model(&models.SummarizerSession{}).Where("id = ?", id).Updates(updates).Error
 }
 
+// UpdateStatusFrom moves a session to status only if it is currently in from,
+// so that concurrent transitions happen once
+func (r *SummarizerSessionRepository) UpdateStatusFrom(id uint, from, status models.SummarizerSessionStatus, endedAt *time.Time) (int64, error) {
+	updates := map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	}
+	if endedAt != nil {
+		updates["ended_at"] = endedAt
+	}
+	result := r.db.Model(&models.SummarizerSession{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// CountByStatus counts the sessions currently in status
+func (r *SummarizerSessionRepository) CountByStatus(status models.SummarizerSessionStatus) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SummarizerSession{}).Where("status = ?", status).Count(&count).Error
+	return count, err
+}
+
+// CountByUserSince counts the sessions started on behalf of a user since the given time
+func (r *SummarizerSessionRepository) CountByUserSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SummarizerSession{}).Where("user_id = ? AND started_at >= ?", userID, since).Count(&count).Error
+	return count, err
+}
+
 func (r *SummarizerSessionRepository) FindByMeetingID(meetingID uint) ([]models.SummarizerSession, error) {
 	var sessions []models.SummarizerSession
 	err := r.db.Where("meeting_id = ?", meetingID).Order("created_at DESC").Find(&sessions).Error
 	return sessions, err
 }
 
+// FindByMeetingIDByOccurrence returns the sessions of a meeting oldest occurrence first
+func (r *SummarizerSessionRepository) FindByMeetingIDByOccurrence(meetingID uint) ([]models.SummarizerSession, error) {
+	var sessions []models.SummarizerSession
+	err := r.db.Where("meeting_id = ?", meetingID).
+		Order("occurrence_start ASC NULLS LAST, started_at ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
 func (r *SummarizerSessionRepository) FindStuck(status models.SummarizerSessionStatus, cutoffTime time.Time) ([]models.SummarizerSession, error) {
 	var sessions []models.SummarizerSession
 	err := r.db.Where("status = ? AND updated_at < ?", status, cutoffTime).Find(&sessions).Error
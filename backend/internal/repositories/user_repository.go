@@ -70,6 +70,19 @@ func (r *UserRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+func (r *UserRepository) FindByCalendarToken(token string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("calendar_token = ?", token).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) UpdateCalendarToken(userID uint, token string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("calendar_token", token).Error
+}
+
 func (r *UserRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
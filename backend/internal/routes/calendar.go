@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"mini-meeting/internal/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupCalendarRoutes(api fiber.Router, calendarHandler *handlers.CalendarHandler) {
+	// Public — the secret token authenticates calendar applications
+	calendar := api.Group("/calendar")
+	calendar.Get("/:token.ics", calendarHandler.GetCalendarFeed)
+}
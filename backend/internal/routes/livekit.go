@@ -8,16 +8,42 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func setupLiveKitRoutes(api fiber.Router, livekitHandler *handlers.LiveKitHandler, cfg *config.Config) {
+func setupLiveKitRoutes(
+	api fiber.Router,
+	livekitHandler *handlers.LiveKitHandler,
+	webhookHandler *handlers.WebhookHandler,
+	handRaiseHandler *handlers.HandRaiseHandler,
+	pollHandler *handlers.PollHandler,
+	cfg *config.Config,
+) {
 	// Public — needed for guests joining a meeting
 	publicLiveKit := api.Group("/livekit")
 	publicLiveKit.Post("/token", livekitHandler.GenerateToken)
 	publicLiveKit.Get("/participants/count", livekitHandler.GetParticipantCount)
 
+	// Public — LiveKit server webhooks, verified by their signature
+	publicLiveKit.Post("/webhook", webhookHandler.ReceiveLiveKitWebhook)
+
+	// Public — participants, guests included, prove who they are with their LiveKit token
+	publicLiveKit.Post("/hands/raise", handRaiseHandler.RaiseHand)
+	publicLiveKit.Post("/hands/lower", handRaiseHandler.LowerOwnHand)
+	publicLiveKit.Post("/reactions", handRaiseHandler.React)
+	publicLiveKit.Post("/polls", pollHandler.ListParticipantPolls)
+	publicLiveKit.Post("/polls/:pollId/vote", pollHandler.Vote)
+
 	// Protected — host/admin controls
 	livekit := api.Group("/livekit", middleware.AuthMiddleware(cfg))
 	livekit.Get("/participants", livekitHandler.ListParticipants)
 	livekit.Post("/remove-participant", livekitHandler.RemoveParticipant)
 	livekit.Post("/mute-participant", livekitHandler.MuteParticipant)
+	livekit.Post("/mute-all", livekitHandler.MuteAll)
+	livekit.Post("/disable-video", livekitHandler.DisableVideo)
+	livekit.Post("/allow-publish", livekitHandler.AllowPublish)
+	livekit.Post("/ask-to-unmute", livekitHandler.AskToUnmute)
+	livekit.Post("/set-cohost", livekitHandler.SetCohost)
+	livekit.Post("/set-role", livekitHandler.SetRole)
 	livekit.Post("/end-meeting", livekitHandler.EndMeeting)
+	livekit.Get("/hands", handRaiseHandler.GetQueue)
+	livekit.Post("/hands/lower-participant", handRaiseHandler.LowerHand)
+	livekit.Post("/hands/call-on", handRaiseHandler.CallOn)
 }
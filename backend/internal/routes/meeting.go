@@ -12,22 +12,84 @@ func setupMeetingRoutes(
 	api fiber.Router,
 	meetingHandler *handlers.MeetingHandler,
 	summarizerHandler *handlers.SummarizerHandler,
+	calendarHandler *handlers.CalendarHandler,
+	attendanceHandler *handlers.AttendanceHandler,
+	breakoutHandler *handlers.BreakoutHandler,
+	chatHandler *handlers.ChatHandler,
+	handRaiseHandler *handlers.HandRaiseHandler,
+	pollHandler *handlers.PollHandler,
+	recordingHandler *handlers.RecordingHandler,
 	cfg *config.Config,
 ) {
 	// Public — guest accessible
 	publicMeetings := api.Group("/meetings")
 	publicMeetings.Get("/code/:code", meetingHandler.GetMeetingByCode)
 
+	// Public — RSVP links sent in invitation emails carry their own signature
+	invitations := api.Group("/invitations")
+	invitations.Get("/:id/rsvp", meetingHandler.ConfirmRSVPLink)
+	invitations.Post("/:id/rsvp", meetingHandler.RespondWithLink)
+
 	// Protected meeting routes
 	meetings := api.Group("/meetings", middleware.AuthMiddleware(cfg))
 	meetings.Post("/", meetingHandler.CreateMeeting)
 	meetings.Get("/my", meetingHandler.GetMyMeetings)
 	meetings.Get("/:id", meetingHandler.GetMeeting)
+	meetings.Patch("/:id", meetingHandler.UpdateMeeting)
 	meetings.Delete("/:id", meetingHandler.DeleteMeeting)
+	meetings.Post("/:id/cancel", meetingHandler.CancelMeeting)
+	meetings.Get("/:id/invite.ics", calendarHandler.GetMeetingInvite)
+
+	// Meeting settings, invitees and co-hosts (creator only); bans, attendance and history (hosts)
+	meetings.Get("/:id/settings", meetingHandler.GetSettings)
+	meetings.Patch("/:id/settings", meetingHandler.UpdateSettings)
+	meetings.Get("/:id/invitees", meetingHandler.GetInvitees)
+	meetings.Post("/:id/invitees", meetingHandler.AddInvitees)
+	meetings.Delete("/:id/invitees/:inviteeId", meetingHandler.RemoveInvitee)
+	meetings.Put("/:id/invitees/:inviteeId/role", meetingHandler.SetInviteeRole)
+	meetings.Post("/:id/rsvp", meetingHandler.RespondToInvitation)
+	meetings.Get("/:id/attendance", attendanceHandler.GetAttendanceReport)
+	meetings.Get("/:id/chat", chatHandler.GetChat)
+	meetings.Get("/:id/speaker-calls", handRaiseHandler.GetSpeakerCalls)
+	meetings.Get("/:id/bans", meetingHandler.GetBans)
+	meetings.Delete("/:id/bans/:banId", meetingHandler.LiftBan)
+	meetings.Get("/:id/cohosts", meetingHandler.GetCohosts)
+	meetings.Post("/:id/cohosts", meetingHandler.AddCohost)
+	meetings.Delete("/:id/cohosts/:userId", meetingHandler.RemoveCohost)
+	meetings.Get("/:id/occurrences", meetingHandler.GetOccurrences)
+	meetings.Put("/:id/occurrences/:occurrenceId", meetingHandler.SetOccurrenceOverride)
+	meetings.Delete("/:id/occurrences/:occurrenceId", meetingHandler.RemoveOccurrenceOverride)
 
 	// Summarizer sub-routes (under meetings)
 	meetings.Post("/:id/summarizer/start", summarizerHandler.StartSummarizer)
 	meetings.Post("/:id/summarizer/stop", summarizerHandler.StopSummarizer)
+	meetings.Post("/:id/summarizer/consent", summarizerHandler.RecordConsent)
+	meetings.Get("/:id/sessions", summarizerHandler.GetMeetingSessions)
+
+	// Breakout rooms (hosts only)
+	meetings.Post("/:id/breakouts", breakoutHandler.CreateBreakouts)
+	meetings.Get("/:id/breakouts", breakoutHandler.GetBreakouts)
+	meetings.Put("/:id/breakouts/assignments", breakoutHandler.AssignParticipants)
+	meetings.Post("/:id/breakouts/shuffle", breakoutHandler.ShuffleParticipants)
+	meetings.Post("/:id/breakouts/open", breakoutHandler.OpenBreakouts)
+	meetings.Post("/:id/breakouts/move", breakoutHandler.MoveParticipant)
+	meetings.Post("/:id/breakouts/broadcast", breakoutHandler.Broadcast)
+	meetings.Post("/:id/breakouts/close", breakoutHandler.CloseBreakouts)
+	meetings.Post("/:id/breakouts/:number/summarizer/start", breakoutHandler.StartSummarizer)
+	meetings.Post("/:id/breakouts/:number/summarizer/stop", breakoutHandler.StopSummarizer)
+
+	// Polls (hosts only; participants vote through /livekit/polls)
+	meetings.Post("/:id/polls", pollHandler.CreatePoll)
+	meetings.Get("/:id/polls", pollHandler.ListPolls)
+	meetings.Get("/:id/polls/:pollId", pollHandler.GetPoll)
+	meetings.Post("/:id/polls/:pollId/open", pollHandler.OpenPoll)
+	meetings.Post("/:id/polls/:pollId/close", pollHandler.ClosePoll)
+
+	// Recordings through LiveKit Egress (hosts only)
+	meetings.Post("/:id/recordings/start", recordingHandler.StartRecording)
+	meetings.Post("/:id/recordings/stop", recordingHandler.StopRecording)
+	meetings.Get("/:id/recordings", recordingHandler.ListRecordings)
+	meetings.Get("/:id/recordings/:recordingId/download", recordingHandler.DownloadRecording)
 
 	// Admin-only
 	meetings.Get("/", middleware.AdminMiddleware(), meetingHandler.GetAllMeetings)
@@ -35,6 +97,8 @@ func setupMeetingRoutes(
 	// Session management routes (authenticated)
 	sessions := api.Group("/sessions", middleware.AuthMiddleware(cfg))
 	sessions.Get("/", summarizerHandler.GetSessions)
+	// Uploaded recordings are allowed past the default body limit and streamed to disk
+	sessions.Post("/import", middleware.StreamBodyLimit(cfg.Summarizer.MaxImportMB*1024*1024), summarizerHandler.ImportSession)
 	sessions.Get("/:id", summarizerHandler.GetSession)
 	sessions.Delete("/:id", summarizerHandler.DeleteSession)
 }
@@ -3,6 +3,7 @@ package routes
 import (
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/handlers"
+	"mini-meeting/internal/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -16,13 +17,25 @@ func SetupRoutes(
 	lobbyHandler *handlers.LobbyHandler,
 	lobbyWSHandler *handlers.LobbyWSHandler,
 	summarizerHandler *handlers.SummarizerHandler,
+	calendarHandler *handlers.CalendarHandler,
+	attendanceHandler *handlers.AttendanceHandler,
+	webhookHandler *handlers.WebhookHandler,
+	breakoutHandler *handlers.BreakoutHandler,
+	chatHandler *handlers.ChatHandler,
+	handRaiseHandler *handlers.HandRaiseHandler,
+	pollHandler *handlers.PollHandler,
+	recordingHandler *handlers.RecordingHandler,
 	cfg *config.Config,
 ) {
+	// Only recording imports may send more than Fiber's default body limit
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, "/api/v1/sessions/import"))
+
 	api := app.Group("/api/v1")
 
 	setupAuthRoutes(api, authHandler)
-	setupUserRoutes(api, userHandler, cfg)
-	setupMeetingRoutes(api, meetingHandler, summarizerHandler, cfg)
-	setupLiveKitRoutes(api, livekitHandler, cfg)
+	setupUserRoutes(api, userHandler, calendarHandler, cfg)
+	setupMeetingRoutes(api, meetingHandler, summarizerHandler, calendarHandler, attendanceHandler, breakoutHandler, chatHandler, handRaiseHandler, pollHandler, recordingHandler, cfg)
+	setupLiveKitRoutes(api, livekitHandler, webhookHandler, handRaiseHandler, pollHandler, cfg)
 	setupLobbyRoutes(app, api, lobbyHandler, lobbyWSHandler)
+	setupCalendarRoutes(api, calendarHandler)
 }
@@ -8,12 +8,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func setupUserRoutes(api fiber.Router, userHandler *handlers.UserHandler, cfg *config.Config) {
+func setupUserRoutes(api fiber.Router, userHandler *handlers.UserHandler, calendarHandler *handlers.CalendarHandler, cfg *config.Config) {
 	users := api.Group("/users", middleware.AuthMiddleware(cfg))
 
 	// Self-service routes
 	users.Get("/me", userHandler.GetMe)
 	users.Patch("/me", userHandler.UpdateMe)
+	users.Get("/me/calendar", calendarHandler.GetMyCalendar)
+	users.Post("/me/calendar/rotate", calendarHandler.RotateMyCalendar)
 
 	// Admin-only routes
 	users.Get("/", middleware.AdminMiddleware(), userHandler.GetAllUsers)
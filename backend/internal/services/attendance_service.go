@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mini-meeting/internal/cache"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// AttendanceService records who was in a meeting from LiveKit webhooks and reports it to hosts
+type AttendanceService struct {
+	attendanceRepo *repositories.MeetingAttendanceRepository
+	meetingService *MeetingService
+}
+
+func NewAttendanceService(attendanceRepo *repositories.MeetingAttendanceRepository, meetingService *MeetingService) *AttendanceService {
+	return &AttendanceService{
+		attendanceRepo: attendanceRepo,
+		meetingService: meetingService,
+	}
+}
+
+// HandleWebhookEvent updates attendance and the meeting status from a LiveKit webhook
+func (s *AttendanceService) HandleWebhookEvent(event *livekit.WebhookEvent) {
+	if event.Room == nil {
+		return
+	}
+
+	// Time spent in a breakout room counts as attending its meeting
+	meetingCode, breakout := event.Room.Name, false
+	if code, _, ok := ParseBreakoutRoomName(event.Room.Name); ok {
+		meetingCode, breakout = code, true
+	}
+
+	meeting, err := s.meetingService.GetMeetingByCode(meetingCode)
+	if err != nil {
+		// Rooms not backed by a meeting (e.g. created by hand) are not tracked
+		return
+	}
+
+	at := time.Now()
+	if event.CreatedAt > 0 {
+		at = time.Unix(event.CreatedAt, 0)
+	}
+
+	switch event.Event {
+	case "room_started":
+		if !breakout {
+			s.meetingService.MarkLive(meeting)
+		}
+	case "participant_joined":
+		if isHumanParticipantInfo(event.Participant) {
+			s.recordJoin(meeting, event.Room.Sid, event.Participant, at)
+		}
+	case "participant_left":
+		if isHumanParticipantInfo(event.Participant) {
+			s.recordLeave(meeting, event.Room.Sid, event.Participant, at)
+		}
+	case "room_finished":
+		if err := s.attendanceRepo.CloseOpenByRoomSID(event.Room.Sid, at); err != nil {
+			fmt.Printf("AttendanceService: Failed to close attendance of room %s: %v\n", event.Room.Sid, err)
+		}
+		if !breakout {
+			s.meetingService.MarkEnded(meeting)
+		}
+	}
+}
+
+func (s *AttendanceService) recordJoin(meeting *models.Meeting, roomSID string, participant *livekit.ParticipantInfo, at time.Time) {
+	if participant.JoinedAt > 0 {
+		at = time.Unix(participant.JoinedAt, 0)
+	}
+
+	attendance := newAttendance(meeting, roomSID, participant, at)
+	if err := s.attendanceRepo.Create(attendance); err != nil {
+		fmt.Printf("AttendanceService: Failed to record %s joining meeting %d: %v\n", participant.Identity, meeting.ID, err)
+	}
+}
+
+func (s *AttendanceService) recordLeave(meeting *models.Meeting, roomSID string, participant *livekit.ParticipantInfo, at time.Time) {
+	closed, err := s.attendanceRepo.CloseByParticipantSID(participant.Sid, at)
+	if err != nil {
+		fmt.Printf("AttendanceService: Failed to record %s leaving meeting %d: %v\n", participant.Identity, meeting.ID, err)
+		return
+	}
+	if closed > 0 || participant.JoinedAt == 0 {
+		return
+	}
+
+	// The join webhook was missed or has not arrived yet: record the whole stay
+	attendance := newAttendance(meeting, roomSID, participant, time.Unix(participant.JoinedAt, 0))
+	attendance.LeftAt = &at
+	if err := s.attendanceRepo.Create(attendance); err != nil {
+		fmt.Printf("AttendanceService: Failed to record %s leaving meeting %d: %v\n", participant.Identity, meeting.ID, err)
+	}
+}
+
+// newAttendance opens an interval for a participant, linked to their account when
+// the identity was issued to a signed-in user
+func newAttendance(meeting *models.Meeting, roomSID string, participant *livekit.ParticipantInfo, joinedAt time.Time) *models.MeetingAttendance {
+	attendance := &models.MeetingAttendance{
+		MeetingID:      meeting.ID,
+		RoomSID:        roomSID,
+		ParticipantSID: participant.Sid,
+		Identity:       participant.Identity,
+		Name:           participant.Name,
+		JoinedAt:       joinedAt,
+	}
+	if p, err := cache.GetParticipant(meeting.MeetingCode, participant.Identity); err == nil && p.UserID > 0 {
+		userID := p.UserID
+		attendance.UserID = &userID
+	}
+	return attendance
+}
+
+// isHumanParticipantInfo reports whether a participant is a person; bots, egress
+// and other server-side participants are left out of attendance
+func isHumanParticipantInfo(participant *livekit.ParticipantInfo) bool {
+	if participant == nil || participant.Kind != livekit.ParticipantInfo_STANDARD {
+		return false
+	}
+	return !isBotIdentity(participant.Identity)
+}
+
+// GetAttendanceReport returns who attended a meeting and for how long (hosts only)
+func (s *AttendanceService) GetAttendanceReport(meetingID uint, userID uint) (*dto.AttendanceReport, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can view attendance")
+	}
+
+	attendances, err := s.attendanceRepo.FindByMeetingID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &dto.AttendanceReport{MeetingID: meetingID, Attendees: []dto.AttendeeReport{}}
+	index := make(map[string]int)
+	// Intervals come in join order; coveredUntil keeps overlapping stays (two tabs) from counting twice
+	coveredUntil := make(map[string]time.Time)
+	for _, attendance := range attendances {
+		key := "identity:" + attendance.Identity
+		if attendance.UserID != nil {
+			key = fmt.Sprintf("user:%d", *attendance.UserID)
+		}
+
+		i, ok := index[key]
+		if !ok {
+			report.Attendees = append(report.Attendees, dto.AttendeeReport{
+				UserID:        attendance.UserID,
+				Identity:      attendance.Identity,
+				Name:          attendance.Name,
+				FirstJoinedAt: attendance.JoinedAt,
+				Intervals:     []dto.AttendanceInterval{},
+			})
+			i = len(report.Attendees) - 1
+			index[key] = i
+		}
+
+		attendee := &report.Attendees[i]
+		attendee.Intervals = append(attendee.Intervals, dto.AttendanceInterval{
+			JoinedAt: attendance.JoinedAt,
+			LeftAt:   attendance.LeftAt,
+		})
+
+		leftAt := now
+		if attendance.LeftAt != nil {
+			leftAt = *attendance.LeftAt
+			if attendee.LastLeftAt == nil || leftAt.After(*attendee.LastLeftAt) {
+				attendee.LastLeftAt = attendance.LeftAt
+			}
+		} else {
+			attendee.Present = true
+		}
+		start := attendance.JoinedAt
+		if start.Before(coveredUntil[key]) {
+			start = coveredUntil[key]
+		}
+		if leftAt.After(start) {
+			attendee.TotalSeconds += int64(leftAt.Sub(start).Seconds())
+			coveredUntil[key] = leftAt
+		}
+	}
+
+	// A person still in the room has not left yet
+	for i := range report.Attendees {
+		if report.Attendees[i].Present {
+			report.Attendees[i].LastLeftAt = nil
+		}
+	}
+
+	return report, nil
+}
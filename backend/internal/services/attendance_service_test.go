@@ -0,0 +1,82 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// A participant whose name is crafted to look like bot metadata is still a person
+func TestCraftedNameIsCountedAsPerson(t *testing.T) {
+	names := []string{
+		`x","type":"bot`,
+		`x\",\"type\":\"bot`,
+		`{"type":"bot"}`,
+		`chat-recorder`,
+		`summarizer-bot-1`,
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			metadata := ParticipantMetadata(name, "", "guest")
+
+			var decoded map[string]string
+			if err := json.Unmarshal([]byte(metadata), &decoded); err != nil {
+				t.Fatalf("metadata %s is not valid JSON: %v", metadata, err)
+			}
+			if decoded["name"] != name {
+				t.Errorf("name = %q, want %q", decoded["name"], name)
+			}
+			if _, ok := decoded["type"]; ok {
+				t.Errorf("metadata %s carries a type", metadata)
+			}
+
+			participant := &livekit.ParticipantInfo{
+				Identity: name + "_42",
+				Name:     name,
+				Kind:     livekit.ParticipantInfo_STANDARD,
+				Metadata: metadata,
+			}
+			if !isHumanParticipantInfo(participant) {
+				t.Errorf("participant named %q is not counted as a person", name)
+			}
+		})
+	}
+}
+
+// Metadata set by anyone other than the backend does not make a participant a bot
+func TestBotMetadataDoesNotMakeABot(t *testing.T) {
+	participant := &livekit.ParticipantInfo{
+		Identity: "mallory_7",
+		Kind:     livekit.ParticipantInfo_STANDARD,
+		Metadata: `{"type":"bot","bot":"chat-recorder"}`,
+	}
+	if !isHumanParticipantInfo(participant) {
+		t.Error("participant with bot metadata is not counted as a person")
+	}
+}
+
+func TestIsHumanParticipantInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		participant *livekit.ParticipantInfo
+		want        bool
+	}{
+		{"nil", nil, false},
+		{"user", &livekit.ParticipantInfo{Identity: "Alice_12", Kind: livekit.ParticipantInfo_STANDARD}, true},
+		{"summarizer bot", &livekit.ParticipantInfo{Identity: summarizerBotIdentity(3), Kind: livekit.ParticipantInfo_STANDARD}, false},
+		{"chat recorder", &livekit.ParticipantInfo{Identity: chatRecorderIdentity, Kind: livekit.ParticipantInfo_STANDARD}, false},
+		{"summarizer bot lookalike", &livekit.ParticipantInfo{Identity: "summarizer-bot-3_12", Kind: livekit.ParticipantInfo_STANDARD}, true},
+		{"egress", &livekit.ParticipantInfo{Identity: "EG_abc", Kind: livekit.ParticipantInfo_EGRESS}, false},
+		{"agent", &livekit.ParticipantInfo{Identity: "agent-1", Kind: livekit.ParticipantInfo_AGENT}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHumanParticipantInfo(tt.participant); got != tt.want {
+				t.Errorf("isHumanParticipantInfo = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
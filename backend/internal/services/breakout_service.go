@@ -0,0 +1,573 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"mini-meeting/internal/cache"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+// breakoutTopic is the data message topic breakout instructions are sent on.
+// {"type":"move","room":...,"token":...,"url":...} asks a participant to switch
+// to another room with the enclosed token; {"type":"countdown","seconds":...,
+// "message":...} announces that the breakout rooms are about to close.
+const breakoutTopic = "breakout"
+
+const (
+	// breakoutSeparator joins a meeting code and a room number into a breakout room name
+	breakoutSeparator = "-breakout-"
+
+	maxBreakoutRooms = 50
+
+	// breakoutGracePeriod is how long breakout rooms stay open after a recall, so
+	// clients have time to switch rooms before theirs is deleted
+	breakoutGracePeriod = 30 * time.Second
+)
+
+// BreakoutService manages breakout rooms: child LiveKit rooms of a meeting that
+// hosts send participants into and recall them from
+type BreakoutService struct {
+	meetingService    *MeetingService
+	livekitService    *LiveKitService
+	summarizerService *SummarizerService
+}
+
+func NewBreakoutService(meetingService *MeetingService, livekitService *LiveKitService, summarizerService *SummarizerService) *BreakoutService {
+	return &BreakoutService{
+		meetingService:    meetingService,
+		livekitService:    livekitService,
+		summarizerService: summarizerService,
+	}
+}
+
+// BreakoutRoomName returns the LiveKit room name of a meeting's breakout room
+func BreakoutRoomName(meetingCode string, number int) string {
+	return fmt.Sprintf("%s%s%d", meetingCode, breakoutSeparator, number)
+}
+
+// ParseBreakoutRoomName returns the meeting code and number of a breakout room
+// name; ok is false for any other room
+func ParseBreakoutRoomName(roomName string) (meetingCode string, number int, ok bool) {
+	i := strings.LastIndex(roomName, breakoutSeparator)
+	if i <= 0 {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(roomName[i+len(breakoutSeparator):])
+	if err != nil || number < 1 {
+		return "", 0, false
+	}
+	return roomName[:i], number, true
+}
+
+// CreateBreakouts creates count breakout rooms under a meeting (hosts only)
+func (s *BreakoutService) CreateBreakouts(meetingID uint, userID uint, req *dto.CreateBreakoutsRequest) (*dto.BreakoutsResponse, error) {
+	meeting, err := s.hostMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Count < 1 || req.Count > maxBreakoutRooms {
+		return nil, fmt.Errorf("invalid count: between 1 and %d breakout rooms", maxBreakoutRooms)
+	}
+	if len(req.Names) > req.Count {
+		return nil, errors.New("invalid names: more names than breakout rooms")
+	}
+	if _, err := cache.GetBreakouts(meeting.MeetingCode); err == nil {
+		return nil, errors.New("breakout rooms already exist for this meeting")
+	}
+
+	breakouts := &cache.Breakouts{
+		MeetingCode: meeting.MeetingCode,
+		Rooms:       make([]cache.BreakoutRoom, 0, req.Count),
+		Assignments: map[string]int{},
+		CreatedAt:   time.Now(),
+	}
+	for number := 1; number <= req.Count; number++ {
+		name := fmt.Sprintf("Room %d", number)
+		if number <= len(req.Names) && strings.TrimSpace(req.Names[number-1]) != "" {
+			name = strings.TrimSpace(req.Names[number-1])
+		}
+		room := cache.BreakoutRoom{
+			Number:   number,
+			Name:     name,
+			RoomName: BreakoutRoomName(meeting.MeetingCode, number),
+		}
+		// A room of the same name recalled moments ago must not be deleted now
+		if err := cache.CancelRoomDeletion(room.RoomName); err != nil {
+			return nil, err
+		}
+		if err := s.livekitService.CreateRoom(room.RoomName, 0); err != nil {
+			return nil, err
+		}
+		breakouts.Rooms = append(breakouts.Rooms, room)
+	}
+
+	if err := cache.StoreBreakouts(breakouts); err != nil {
+		return nil, err
+	}
+
+	return s.describe(meeting, breakouts), nil
+}
+
+// GetBreakouts returns the breakout rooms of a meeting with who is in each of them (hosts only)
+func (s *BreakoutService) GetBreakouts(meetingID uint, userID uint) (*dto.BreakoutsResponse, error) {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(meeting, breakouts), nil
+}
+
+// AssignParticipants sets the breakout room of participants by LiveKit identity.
+// Room 0 unassigns a participant. Participants move when the rooms are opened.
+func (s *BreakoutService) AssignParticipants(meetingID uint, userID uint, assignments map[string]int) (*dto.BreakoutsResponse, error) {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for identity, number := range assignments {
+		if number != 0 && breakouts.Room(number) == nil {
+			return nil, fmt.Errorf("invalid room number: %d", number)
+		}
+		if number == 0 {
+			delete(breakouts.Assignments, identity)
+		} else {
+			breakouts.Assignments[identity] = number
+		}
+	}
+
+	if err := cache.StoreBreakouts(breakouts); err != nil {
+		return nil, err
+	}
+	return s.describe(meeting, breakouts), nil
+}
+
+// ShuffleParticipants spreads every participant but the hosts evenly over the
+// breakout rooms at random, replacing previous assignments
+func (s *BreakoutService) ShuffleParticipants(meetingID uint, userID uint) (*dto.BreakoutsResponse, error) {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities := make([]string, 0)
+	for identity, located := range s.locate(meeting, breakouts) {
+		if !participantRole(meeting.MeetingCode, located.participant).IsHost() {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Strings(identities)
+	rand.Shuffle(len(identities), func(i, j int) {
+		identities[i], identities[j] = identities[j], identities[i]
+	})
+
+	breakouts.Assignments = make(map[string]int, len(identities))
+	for i, identity := range identities {
+		breakouts.Assignments[identity] = breakouts.Rooms[i%len(breakouts.Rooms)].Number
+	}
+
+	if err := cache.StoreBreakouts(breakouts); err != nil {
+		return nil, err
+	}
+	return s.describe(meeting, breakouts), nil
+}
+
+// OpenBreakouts sends every assigned participant a token for their breakout room.
+// With a duration, everyone is recalled to the main room when it elapses.
+func (s *BreakoutService) OpenBreakouts(meetingID uint, userID uint, durationSeconds int) (*dto.BreakoutsResponse, error) {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if durationSeconds < 0 {
+		return nil, errors.New("invalid duration: must not be negative")
+	}
+
+	located := s.locate(meeting, breakouts)
+	for identity, number := range breakouts.Assignments {
+		current, ok := located[identity]
+		room := breakouts.Room(number)
+		if !ok || room == nil || current.room == room.RoomName {
+			continue
+		}
+		if err := s.move(meeting.MeetingCode, current.participant, current.room, room.RoomName); err != nil {
+			fmt.Printf("BreakoutService: Failed to move %s to %s: %v\n", identity, room.RoomName, err)
+		}
+	}
+
+	breakouts.Opened = true
+	breakouts.EndsAt = nil
+	if durationSeconds > 0 {
+		endsAt := time.Now().Add(time.Duration(durationSeconds) * time.Second)
+		breakouts.EndsAt = &endsAt
+		if err := cache.ScheduleBreakoutRecall(meeting.MeetingCode, endsAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cache.StoreBreakouts(breakouts); err != nil {
+		return nil, err
+	}
+	return s.describe(meeting, breakouts), nil
+}
+
+// MoveParticipant sends one participant into a breakout room, or back to the main
+// room when number is 0. Hosts also use it to visit a breakout room themselves.
+func (s *BreakoutService) MoveParticipant(meetingID uint, userID uint, identity string, number int) error {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return err
+	}
+
+	target := meeting.MeetingCode
+	if number != 0 {
+		room := breakouts.Room(number)
+		if room == nil {
+			return fmt.Errorf("invalid room number: %d", number)
+		}
+		target = room.RoomName
+		breakouts.Assignments[identity] = number
+	} else {
+		delete(breakouts.Assignments, identity)
+	}
+
+	current, ok := s.locate(meeting, breakouts)[identity]
+	if !ok {
+		return errors.New("participant not found")
+	}
+
+	if err := cache.StoreBreakouts(breakouts); err != nil {
+		return err
+	}
+
+	if current.room == target {
+		return nil
+	}
+	return s.move(meeting.MeetingCode, current.participant, current.room, target)
+}
+
+// Broadcast announces a countdown to every breakout room. With recall set,
+// everyone is brought back to the main room when the countdown ends.
+func (s *BreakoutService) Broadcast(meetingID uint, userID uint, req *dto.BreakoutBroadcastRequest) error {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return err
+	}
+	if req.Seconds < 0 {
+		return errors.New("invalid seconds: must not be negative")
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":    "countdown",
+		"seconds": req.Seconds,
+		"message": req.Message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode countdown: %w", err)
+	}
+
+	for _, room := range breakouts.Rooms {
+		if err := s.livekitService.SendData(room.RoomName, nil, breakoutTopic, payload); err != nil {
+			fmt.Printf("BreakoutService: Failed to send countdown to %s: %v\n", room.RoomName, err)
+		}
+	}
+
+	if req.Recall {
+		endsAt := time.Now().Add(time.Duration(req.Seconds) * time.Second)
+		breakouts.EndsAt = &endsAt
+		if err := cache.StoreBreakouts(breakouts); err != nil {
+			return err
+		}
+		if err := cache.ScheduleBreakoutRecall(meeting.MeetingCode, endsAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CloseBreakouts recalls everyone to the main room and closes the breakout rooms
+// (hosts only). It returns how many participants were sent back.
+func (s *BreakoutService) CloseBreakouts(meetingID uint, userID uint) (int, error) {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return s.recall(meeting, breakouts), nil
+}
+
+// StartSummarizer starts a summarizer session capturing one breakout room (hosts only)
+func (s *BreakoutService) StartSummarizer(meetingID uint, userID uint, number int) (*models.SummarizerSession, error) {
+	meeting, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	room := breakouts.Room(number)
+	if room == nil {
+		return nil, errors.New("breakout room not found")
+	}
+	return s.summarizerService.StartBreakoutSummarizer(meeting, userID, room.RoomName)
+}
+
+// StopSummarizer stops the summarizer session of one breakout room (hosts only)
+// and returns the stopped session with its number of audio chunks
+func (s *BreakoutService) StopSummarizer(meetingID uint, userID uint, number int) (*models.SummarizerSession, int64, error) {
+	_, breakouts, err := s.load(meetingID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	room := breakouts.Room(number)
+	if room == nil {
+		return nil, 0, errors.New("breakout room not found")
+	}
+
+	session, err := s.summarizerService.GetActiveRoomSession(meetingID, room.RoomName)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalChunks, err := s.summarizerService.StopSummarizer(session.ID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	session, err = s.summarizerService.GetSessionByID(session.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("session not found: %w", err)
+	}
+	return session, totalChunks, nil
+}
+
+// recall sends everyone in a breakout room a token for the main room, stops the
+// summarizers of the breakout rooms and deletes the rooms after a grace period
+func (s *BreakoutService) recall(meeting *models.Meeting, breakouts *cache.Breakouts) int {
+	if err := cache.CancelBreakoutRecall(meeting.MeetingCode); err != nil {
+		fmt.Printf("BreakoutService: Failed to cancel recall of %s: %v\n", meeting.MeetingCode, err)
+	}
+
+	// The main room may have been closed while it stood empty
+	if settings, err := s.meetingService.GetSettings(meeting.ID); err == nil {
+		if err := s.livekitService.CreateRoom(meeting.MeetingCode, settings.MaxParticipants); err != nil {
+			fmt.Printf("BreakoutService: Failed to reopen room %s: %v\n", meeting.MeetingCode, err)
+		}
+	}
+
+	moved := 0
+	roomNames := make([]string, 0, len(breakouts.Rooms))
+	for _, room := range breakouts.Rooms {
+		roomNames = append(roomNames, room.RoomName)
+
+		if session, err := s.summarizerService.GetActiveRoomSession(meeting.ID, room.RoomName); err == nil {
+			s.summarizerService.autoStop(session.ID, "breakout room closed")
+		}
+
+		participants, err := s.livekitService.ListParticipants(room.RoomName)
+		if err != nil {
+			continue
+		}
+		for _, participant := range participants {
+			if !isHumanParticipantInfo(participant) {
+				continue
+			}
+			if err := s.move(meeting.MeetingCode, participant, room.RoomName, meeting.MeetingCode); err != nil {
+				fmt.Printf("BreakoutService: Failed to recall %s from %s: %v\n", participant.Identity, room.RoomName, err)
+				continue
+			}
+			moved++
+		}
+	}
+
+	if err := cache.DeleteBreakouts(meeting.MeetingCode); err != nil {
+		fmt.Printf("BreakoutService: Failed to delete breakouts of %s: %v\n", meeting.MeetingCode, err)
+	}
+
+	deleteAt := time.Now().Add(breakoutGracePeriod)
+	for _, roomName := range roomNames {
+		if err := cache.ScheduleRoomDeletion(roomName, deleteAt); err != nil {
+			fmt.Printf("BreakoutService: Failed to schedule deletion of room %s: %v\n", roomName, err)
+		}
+	}
+
+	fmt.Printf("BreakoutService: Recalled %d participants to %s\n", moved, meeting.MeetingCode)
+	return moved
+}
+
+// ProcessDueDeadlines recalls everyone from the breakout rooms that ran out of
+// time and deletes the rooms whose grace period is over. The deadlines are kept
+// in Redis so that any backend instance sweeping them runs each one once.
+func (s *BreakoutService) ProcessDueDeadlines() {
+	now := time.Now()
+
+	meetingCodes, err := cache.ClaimDueBreakoutRecalls(now)
+	if err != nil {
+		fmt.Printf("BreakoutService: Failed to claim due recalls: %v\n", err)
+	}
+	for _, meetingCode := range meetingCodes {
+		meeting, err := s.meetingService.GetMeetingByCode(meetingCode)
+		if err != nil {
+			continue
+		}
+		breakouts, err := cache.GetBreakouts(meetingCode)
+		if err != nil {
+			continue
+		}
+		s.recall(meeting, breakouts)
+	}
+
+	roomNames, err := cache.ClaimDueRoomDeletions(now)
+	if err != nil {
+		fmt.Printf("BreakoutService: Failed to claim due room deletions: %v\n", err)
+	}
+	for _, roomName := range roomNames {
+		if err := s.livekitService.DeleteRoom(roomName); err != nil {
+			fmt.Printf("BreakoutService: Failed to delete room %s: %v\n", roomName, err)
+		}
+	}
+}
+
+// move sends a participant a token for another room on the breakout topic. The
+// token keeps their identity, name and metadata, and the role the server gave them.
+func (s *BreakoutService) move(meetingCode string, participant *livekit.ParticipantInfo, from string, to string) error {
+	token, err := s.livekitService.CreateJoinToken(
+		to,
+		participant.Identity,
+		participant.Name,
+		string(participantRole(meetingCode, participant)),
+		participant.Metadata,
+	)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":  "move",
+		"room":  to,
+		"token": token,
+		"url":   s.livekitService.GetURL(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode move: %w", err)
+	}
+
+	return s.livekitService.SendData(from, []string{participant.Identity}, breakoutTopic, payload)
+}
+
+// locatedParticipant is a participant and the room they are in
+type locatedParticipant struct {
+	room        string
+	participant *livekit.ParticipantInfo
+}
+
+// locate finds every person in the main room or a breakout room of a meeting, by identity
+func (s *BreakoutService) locate(meeting *models.Meeting, breakouts *cache.Breakouts) map[string]locatedParticipant {
+	roomNames := []string{meeting.MeetingCode}
+	for _, room := range breakouts.Rooms {
+		roomNames = append(roomNames, room.RoomName)
+	}
+
+	located := make(map[string]locatedParticipant)
+	for _, roomName := range roomNames {
+		participants, err := s.livekitService.ListParticipants(roomName)
+		if err != nil {
+			continue
+		}
+		for _, participant := range participants {
+			if isHumanParticipantInfo(participant) {
+				located[participant.Identity] = locatedParticipant{room: roomName, participant: participant}
+			}
+		}
+	}
+	return located
+}
+
+// describe lists the live participants of the main room and of each breakout room
+func (s *BreakoutService) describe(meeting *models.Meeting, breakouts *cache.Breakouts) *dto.BreakoutsResponse {
+	response := &dto.BreakoutsResponse{
+		MeetingCode: breakouts.MeetingCode,
+		Opened:      breakouts.Opened,
+		EndsAt:      breakouts.EndsAt,
+		Rooms:       make([]dto.BreakoutRoomResponse, 0, len(breakouts.Rooms)),
+		MainRoom:    s.participantInfos(meeting.MeetingCode),
+		CreatedAt:   breakouts.CreatedAt,
+	}
+
+	for _, room := range breakouts.Rooms {
+		assigned := make([]string, 0)
+		for identity, number := range breakouts.Assignments {
+			if number == room.Number {
+				assigned = append(assigned, identity)
+			}
+		}
+		sort.Strings(assigned)
+
+		roomResponse := dto.BreakoutRoomResponse{
+			Number:       room.Number,
+			Name:         room.Name,
+			RoomName:     room.RoomName,
+			Assigned:     assigned,
+			Participants: s.participantInfos(room.RoomName),
+		}
+		if session, err := s.summarizerService.GetActiveRoomSession(meeting.ID, room.RoomName); err == nil {
+			roomResponse.SessionID = &session.ID
+		}
+		response.Rooms = append(response.Rooms, roomResponse)
+	}
+
+	return response
+}
+
+// participantInfos lists the people in a room; a room that does not exist is empty
+func (s *BreakoutService) participantInfos(roomName string) []dto.ParticipantInfo {
+	infos := make([]dto.ParticipantInfo, 0)
+	participants, err := s.livekitService.ListParticipants(roomName)
+	if err != nil {
+		return infos
+	}
+	for _, p := range participants {
+		if !isHumanParticipantInfo(p) {
+			continue
+		}
+		infos = append(infos, dto.ParticipantInfo{
+			Identity: p.Identity,
+			Name:     p.Name,
+			State:    p.State.String(),
+			Metadata: p.Metadata,
+			JoinedAt: p.JoinedAt,
+		})
+	}
+	return infos
+}
+
+// hostMeeting returns a meeting userID hosts
+func (s *BreakoutService) hostMeeting(meetingID uint, userID uint) (*models.Meeting, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can manage breakout rooms")
+	}
+	return meeting, nil
+}
+
+// load returns a meeting userID hosts and its breakout rooms
+func (s *BreakoutService) load(meetingID uint, userID uint) (*models.Meeting, *cache.Breakouts, error) {
+	meeting, err := s.hostMeeting(meetingID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	breakouts, err := cache.GetBreakouts(meeting.MeetingCode)
+	if err != nil {
+		return nil, nil, errors.New("breakout rooms not found")
+	}
+	return meeting, breakouts, nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mini-meeting/internal/cache"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	lksdk "github.com/livekit/server-sdk-go/v2"
+)
+
+// Topics LiveKit's chat components send data messages on. A message has a payload
+// of {"id": ..., "message": ..., "timestamp": <unix ms>}; an edit is sent on
+// chatUpdateTopic with the id of the message it replaces.
+const (
+	chatTopic       = "lk-chat-topic"
+	chatUpdateTopic = "lk-chat-update-topic"
+)
+
+// chatRecorderLeaseTTL bounds how long a room goes unrecorded when the backend
+// instance recording it dies. The lease is renewed every third of it.
+const chatRecorderLeaseTTL = 30 * time.Second
+
+// ChatService records the chat of meeting rooms with a hidden recorder bot, so
+// that it outlives the room, and lets hosts read it back
+type ChatService struct {
+	chatRepo       *repositories.ChatMessageRepository
+	meetingService *MeetingService
+	livekitService *LiveKitService
+
+	// Recorders run by this backend instance, by LiveKit room name
+	recorders     map[string]*chatRecorder
+	recorderMutex sync.Mutex
+}
+
+// chatRecorder is the recorder of one room. Its fields are guarded by the
+// service's recorderMutex.
+type chatRecorder struct {
+	// lease makes this instance the only one recording the room; nil until taken
+	lease *cache.Lock
+	// room is nil while connecting
+	room    *lksdk.Room
+	done    chan struct{}
+	stopped bool
+}
+
+func NewChatService(chatRepo *repositories.ChatMessageRepository, meetingService *MeetingService, livekitService *LiveKitService) *ChatService {
+	return &ChatService{
+		chatRepo:       chatRepo,
+		meetingService: meetingService,
+		livekitService: livekitService,
+		recorders:      make(map[string]*chatRecorder),
+	}
+}
+
+// HandleWebhookEvent sends the recorder into a room when a person joins it and
+// takes it out when the room finishes
+func (s *ChatService) HandleWebhookEvent(event *livekit.WebhookEvent) {
+	if event.Room == nil {
+		return
+	}
+
+	switch event.Event {
+	case "participant_joined":
+		if !isHumanParticipantInfo(event.Participant) {
+			return
+		}
+		meeting, roomName, err := s.resolveRoom(event.Room.Name)
+		if err != nil {
+			return
+		}
+		s.startRecorder(meeting, event.Room.Name, roomName)
+	case "room_finished":
+		s.stopRecorder(event.Room.Name)
+	}
+}
+
+// resolveRoom returns the meeting a LiveKit room belongs to and the breakout room
+// name to record messages under, empty for the main room
+func (s *ChatService) resolveRoom(livekitRoom string) (*models.Meeting, string, error) {
+	meetingCode, roomName := livekitRoom, ""
+	if code, _, ok := ParseBreakoutRoomName(livekitRoom); ok {
+		meetingCode, roomName = code, livekitRoom
+	}
+	meeting, err := s.meetingService.GetMeetingByCode(meetingCode)
+	if err != nil {
+		return nil, "", err
+	}
+	return meeting, roomName, nil
+}
+
+// startRecorder connects the recorder to a room unless it is already there
+func (s *ChatService) startRecorder(meeting *models.Meeting, livekitRoom string, roomName string) {
+	s.recorderMutex.Lock()
+	if _, exists := s.recorders[livekitRoom]; exists {
+		s.recorderMutex.Unlock()
+		return
+	}
+	rec := &chatRecorder{done: make(chan struct{})}
+	s.recorders[livekitRoom] = rec
+	s.recorderMutex.Unlock()
+
+	go func() {
+		if err := s.runRecorder(meeting, livekitRoom, roomName, rec); err != nil {
+			s.dropRecorder(livekitRoom, rec)
+			fmt.Printf("ChatService: Failed to record chat of room %s: %v\n", livekitRoom, err)
+		}
+	}()
+}
+
+// runRecorder takes the room's recorder lease and connects the recorder. Every
+// backend instance receives webhooks, but the recorder joins with a fixed
+// identity, so only the instance holding the lease may connect.
+func (s *ChatService) runRecorder(meeting *models.Meeting, livekitRoom string, roomName string, rec *chatRecorder) error {
+	lease, err := cache.TryLock(chatRecorderLease(livekitRoom), chatRecorderLeaseTTL)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		// Another instance records the room
+		s.dropRecorder(livekitRoom, rec)
+		return nil
+	}
+
+	s.recorderMutex.Lock()
+	rec.lease = lease
+	stopped := rec.stopped
+	s.recorderMutex.Unlock()
+	if stopped {
+		// The room finished while the lease was taken
+		if err := lease.Release(); err != nil {
+			fmt.Printf("ChatService: %v\n", err)
+		}
+		return nil
+	}
+	go s.renewLease(livekitRoom, rec, lease)
+
+	token, err := s.livekitService.CreateChatRecorderToken(livekitRoom)
+	if err != nil {
+		return err
+	}
+
+	room, err := lksdk.ConnectToRoomWithToken(s.livekitService.GetURL(), token, &lksdk.RoomCallback{
+		ParticipantCallback: lksdk.ParticipantCallback{
+			OnDataPacket: func(data lksdk.DataPacket, params lksdk.DataReceiveParams) {
+				packet, ok := data.(*lksdk.UserDataPacket)
+				if !ok || (packet.Topic != chatTopic && packet.Topic != chatUpdateTopic) {
+					return
+				}
+				s.record(meeting, roomName, packet, params)
+			},
+		},
+		OnParticipantDisconnected: func(participant *lksdk.RemoteParticipant) {
+			s.recorderMutex.Lock()
+			room := rec.room
+			s.recorderMutex.Unlock()
+			if room == nil {
+				return
+			}
+			// Nobody is left to chat, and the recorder must not keep the room open
+			for _, remaining := range room.GetRemoteParticipants() {
+				if remaining.Identity() != participant.Identity() && isHumanParticipant(remaining) {
+					return
+				}
+			}
+			go s.dropRecorder(livekitRoom, rec)
+		},
+		OnDisconnectedWithReason: func(reason lksdk.DisconnectionReason) {
+			if reason == lksdk.LeaveRequested {
+				return
+			}
+			go s.dropRecorder(livekitRoom, rec)
+		},
+	}, lksdk.WithAutoSubscribe(false))
+	if err != nil {
+		return fmt.Errorf("failed to connect to room: %w", err)
+	}
+
+	// Keep the connection, unless the room finished while connecting
+	s.recorderMutex.Lock()
+	stopped = rec.stopped
+	if !stopped {
+		rec.room = room
+	}
+	s.recorderMutex.Unlock()
+	if stopped {
+		room.Disconnect()
+		return nil
+	}
+
+	fmt.Printf("ChatService: Recording chat of room %s\n", livekitRoom)
+	return nil
+}
+
+// renewLease keeps the recorder lease of a room until the recorder stops. If the
+// lease is lost, the recorder leaves so that the instance now holding it records.
+func (s *ChatService) renewLease(livekitRoom string, rec *chatRecorder, lease *cache.Lock) {
+	ticker := time.NewTicker(chatRecorderLeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rec.done:
+			return
+		case <-ticker.C:
+			held, err := lease.Extend(chatRecorderLeaseTTL)
+			if err != nil {
+				fmt.Printf("ChatService: Failed to renew recorder lease of room %s: %v\n", livekitRoom, err)
+				continue
+			}
+			if !held {
+				fmt.Printf("ChatService: Lost recorder lease of room %s\n", livekitRoom)
+				s.dropRecorder(livekitRoom, rec)
+				return
+			}
+		}
+	}
+}
+
+// stopRecorder disconnects the recorder from a room
+func (s *ChatService) stopRecorder(livekitRoom string) {
+	s.recorderMutex.Lock()
+	rec := s.recorders[livekitRoom]
+	s.recorderMutex.Unlock()
+
+	if rec != nil {
+		s.dropRecorder(livekitRoom, rec)
+	}
+}
+
+// dropRecorder stops a recorder: it leaves the room and gives up its lease
+func (s *ChatService) dropRecorder(livekitRoom string, rec *chatRecorder) {
+	s.recorderMutex.Lock()
+	if s.recorders[livekitRoom] == rec {
+		delete(s.recorders, livekitRoom)
+	}
+	if rec.stopped {
+		s.recorderMutex.Unlock()
+		return
+	}
+	rec.stopped = true
+	close(rec.done)
+	room, lease := rec.room, rec.lease
+	s.recorderMutex.Unlock()
+
+	if room != nil {
+		room.Disconnect()
+		fmt.Printf("ChatService: Stopped recording chat of room %s\n", livekitRoom)
+	}
+	if lease != nil {
+		if err := lease.Release(); err != nil {
+			fmt.Printf("ChatService: %v\n", err)
+		}
+	}
+}
+
+func chatRecorderLease(livekitRoom string) string {
+	return "chat-recorder:" + livekitRoom
+}
+
+// record saves a chat message or an edit of one. Messages are timed on arrival
+// rather than by the sender's clock, so they line up with the audio transcript.
+func (s *ChatService) record(meeting *models.Meeting, roomName string, packet *lksdk.UserDataPacket, params lksdk.DataReceiveParams) {
+	var payload struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(packet.Payload, &payload); err != nil || strings.TrimSpace(payload.Message) == "" {
+		return
+	}
+
+	now := time.Now()
+	if packet.Topic == chatUpdateTopic && payload.ID != "" {
+		updated, err := s.chatRepo.UpdateText(meeting.ID, params.SenderIdentity, payload.ID, payload.Message, now)
+		if err != nil {
+			fmt.Printf("ChatService: Failed to record edit from %s: %v\n", params.SenderIdentity, err)
+			return
+		}
+		if updated > 0 {
+			return
+		}
+		// The original was not recorded: keep the edited text as a new message
+	}
+
+	message := &models.ChatMessage{
+		MeetingID: meeting.ID,
+		RoomName:  roomName,
+		MessageID: payload.ID,
+		Identity:  params.SenderIdentity,
+		Name:      params.SenderIdentity,
+		Message:   payload.Message,
+		SentAt:    now,
+	}
+	if params.Sender != nil && params.Sender.Name() != "" {
+		message.Name = params.Sender.Name()
+	}
+	if participant, err := cache.GetParticipant(meeting.MeetingCode, params.SenderIdentity); err == nil && participant.UserID > 0 {
+		userID := participant.UserID
+		message.UserID = &userID
+	}
+
+	if err := s.chatRepo.Create(message); err != nil {
+		fmt.Printf("ChatService: Failed to record message from %s: %v\n", params.SenderIdentity, err)
+	}
+}
+
+// GetChat returns the chat of a meeting in the order it was sent, across its
+// main and breakout rooms (hosts only)
+func (s *ChatService) GetChat(meetingID uint, userID uint, page, pageSize int) (*dto.PaginatedChatResponse, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can view the chat")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 50
+	}
+
+	messages, total, err := s.chatRepo.FindByMeetingIDPaginated(meetingID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chat: %w", err)
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &dto.PaginatedChatResponse{
+		Data:       messages,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"mini-meeting/internal/config"
 	"net/http"
@@ -30,11 +32,18 @@ func NewEmailService(cfg *config.Config) *EmailService {
 
 // brevoEmailRequest mirrors the Brevo transactional email API payload.
 type brevoEmailRequest struct {
-	Sender      brevoContact   `json:"sender"`
-	To          []brevoContact `json:"to"`
-	Subject     string         `json:"subject"`
-	HTMLContent string         `json:"htmlContent"`
-	TextContent string         `json:"textContent"`
+	Sender      brevoContact      `json:"sender"`
+	To          []brevoContact    `json:"to"`
+	Subject     string            `json:"subject"`
+	HTMLContent string            `json:"htmlContent"`
+	TextContent string            `json:"textContent"`
+	Attachment  []brevoAttachment `json:"attachment,omitempty"`
+}
+
+// brevoAttachment is a file attached to an email; Content is base64 encoded
+type brevoAttachment struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
 }
 
 type brevoContact struct {
@@ -90,6 +99,127 @@ func (s *EmailService) SendSessionReadyEmail(toEmail, toName string, sessionID u
 		TextContent: textBody,
 	}
 
+	if err := s.send(payload); err != nil {
+		return err
+	}
+
+	fmt.Printf("EmailService: session-ready email sent to %s for session %d\n", toEmail, sessionID)
+	return nil
+}
+
+// MeetingEmail describes a meeting in invitation and reminder emails.
+// When is empty for instant meetings; ICS, when set, is attached as invite.ics.
+type MeetingEmail struct {
+	ToEmail    string
+	Title      string
+	HostName   string
+	When       string
+	JoinURL    string
+	AcceptURL  string
+	MaybeURL   string
+	DeclineURL string
+	ICS        []byte
+}
+
+// SendInvitationEmail invites an email address to a meeting, with RSVP links
+func (s *EmailService) SendInvitationEmail(m MeetingEmail) error {
+	subject := fmt.Sprintf("Invitation: %s", m.Title)
+	intro := fmt.Sprintf("%s invited you to <strong>%s</strong>.", html.EscapeString(m.HostName), html.EscapeString(m.Title))
+	textIntro := fmt.Sprintf("%s invited you to %s.", m.HostName, m.Title)
+
+	if err := s.sendMeetingEmail(m, subject, intro, textIntro, true); err != nil {
+		return err
+	}
+
+	fmt.Printf("EmailService: invitation email sent to %s\n", m.ToEmail)
+	return nil
+}
+
+// SendReminderEmail reminds an invitee that a meeting is about to start
+func (s *EmailService) SendReminderEmail(m MeetingEmail) error {
+	subject := fmt.Sprintf("Reminder: %s starts soon", m.Title)
+	intro := fmt.Sprintf("<strong>%s</strong> is starting soon.", html.EscapeString(m.Title))
+	textIntro := fmt.Sprintf("%s is starting soon.", m.Title)
+
+	if err := s.sendMeetingEmail(m, subject, intro, textIntro, false); err != nil {
+		return err
+	}
+
+	fmt.Printf("EmailService: reminder email sent to %s\n", m.ToEmail)
+	return nil
+}
+
+func (s *EmailService) sendMeetingEmail(m MeetingEmail, subject, intro, textIntro string, withRSVP bool) error {
+	if s.cfg.APIKey == "" {
+		return fmt.Errorf("Brevo API key is not configured")
+	}
+
+	when, textWhen := "", ""
+	if m.When != "" {
+		when = fmt.Sprintf("<br>When: %s", html.EscapeString(m.When))
+		textWhen = fmt.Sprintf("\nWhen: %s", m.When)
+	}
+
+	rsvp, textRSVP := "", ""
+	if withRSVP {
+		rsvp = fmt.Sprintf(`
+    <p style="color: #444; margin-top: 24px;">Will you attend?
+      <a href="%s" style="color: #2e7d32; font-weight: bold;">Yes</a> ·
+      <a href="%s" style="color: #6c63ff; font-weight: bold;">Maybe</a> ·
+      <a href="%s" style="color: #c62828; font-weight: bold;">No</a>
+    </p>`, m.AcceptURL, m.MaybeURL, m.DeclineURL)
+		textRSVP = fmt.Sprintf("\n\nWill you attend?\nYes: %s\nMaybe: %s\nNo: %s", m.AcceptURL, m.MaybeURL, m.DeclineURL)
+	}
+
+	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, sans-serif; background: #f4f4f4; padding: 32px;">
+  <div style="max-width: 560px; margin: 0 auto; background: #ffffff; border-radius: 8px; padding: 32px;">
+    <h2 style="color: #1a1a2e; margin-top: 0;">%s</h2>
+    <p style="color: #444; line-height: 1.6;">
+      %s%s
+    </p>
+    <a href="%s"
+       style="display: inline-block; margin-top: 16px; padding: 12px 24px;
+              background: #6c63ff; color: #ffffff; text-decoration: none;
+              border-radius: 6px; font-weight: bold;">
+      Join Meeting
+    </a>%s
+    <p style="color: #888; font-size: 12px; margin-top: 32px;">
+      Or copy this link: %s
+    </p>
+  </div>
+</body>
+</html>`, html.EscapeString(m.Title), intro, when, m.JoinURL, rsvp, m.JoinURL)
+
+	textBody := fmt.Sprintf(
+		"%s%s\n\nJoin the meeting:\n%s%s\n\nMini Meeting",
+		textIntro, textWhen, m.JoinURL, textRSVP,
+	)
+
+	payload := brevoEmailRequest{
+		Sender: brevoContact{
+			Email: s.cfg.SenderEmail,
+			Name:  s.cfg.SenderName,
+		},
+		To: []brevoContact{
+			{Email: m.ToEmail},
+		},
+		Subject:     subject,
+		HTMLContent: htmlBody,
+		TextContent: textBody,
+	}
+	if len(m.ICS) > 0 {
+		payload.Attachment = []brevoAttachment{
+			{Name: "invite.ics", Content: base64.StdEncoding.EncodeToString(m.ICS)},
+		}
+	}
+
+	return s.send(payload)
+}
+
+// send posts an email to the Brevo API
+func (s *EmailService) send(payload brevoEmailRequest) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal email payload: %w", err)
@@ -114,6 +244,5 @@ func (s *EmailService) SendSessionReadyEmail(toEmail, toName string, sessionID u
 		return fmt.Errorf("Brevo API returned status %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	fmt.Printf("EmailService: session-ready email sent to %s for session %d\n", toEmail, sessionID)
 	return nil
 }
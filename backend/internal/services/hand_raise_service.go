@@ -0,0 +1,384 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mini-meeting/internal/cache"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+	"sort"
+	"time"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+)
+
+// allowedReactions are the reactions participants may send
+var allowedReactions = map[string]bool{
+	"👍": true, "👎": true, "👏": true, "❤️": true, "😂": true,
+	"😮": true, "🎉": true, "🤔": true, "🙌": true,
+}
+
+// reactionTopic is the data message topic reactions are sent to a room on
+const reactionTopic = "reaction"
+
+// reactionMessage is the payload of a reaction sent on reactionTopic
+type reactionMessage struct {
+	Identity string    `json:"identity"`
+	Name     string    `json:"name"`
+	Reaction string    `json:"reaction"`
+	SentAt   time.Time `json:"sent_at"`
+}
+
+// HandRaiseService keeps the raised-hand queue of each room in Redis and mirrors
+// it into the room metadata, where clients read it: "raised_hands" is the queue,
+// longest waiting first, and "speaker" the participant last called on. Reactions
+// are relayed to the room as data messages on reactionTopic.
+type HandRaiseService struct {
+	speakerCallRepo *repositories.SpeakerCallRepository
+	meetingService  *MeetingService
+	livekitService  *LiveKitService
+}
+
+func NewHandRaiseService(speakerCallRepo *repositories.SpeakerCallRepository, meetingService *MeetingService, livekitService *LiveKitService) *HandRaiseService {
+	return &HandRaiseService{
+		speakerCallRepo: speakerCallRepo,
+		meetingService:  meetingService,
+		livekitService:  livekitService,
+	}
+}
+
+// RaiseHand puts the participant a join token was issued to at the end of their
+// room's queue and returns the queue
+func (s *HandRaiseService) RaiseHand(token string) ([]cache.RaisedHand, error) {
+	claims, err := s.livekitService.VerifyJoinToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	room := claims.Video.Room
+	if _, err := s.meetingService.CheckParticipant(room, claims.Identity); err != nil {
+		return nil, err
+	}
+	if _, err := cache.RaiseHand(room, claims.Identity, participantName(claims)); err != nil {
+		return nil, err
+	}
+
+	return s.mirrorHands(room, nil)
+}
+
+// LowerOwnHand takes the participant a join token was issued to out of their room's queue
+func (s *HandRaiseService) LowerOwnHand(token string) error {
+	claims, err := s.livekitService.VerifyJoinToken(token)
+	if err != nil {
+		return err
+	}
+
+	room := claims.Video.Room
+	hand, err := cache.LowerHand(room, claims.Identity)
+	if err != nil {
+		return err
+	}
+	if hand != nil {
+		_, err = s.mirrorHands(room, nil)
+	}
+	return err
+}
+
+// React records a reaction from the participant a join token was issued to
+func (s *HandRaiseService) React(token string, reaction string) error {
+	if !allowedReactions[reaction] {
+		return errors.New("invalid reaction")
+	}
+
+	claims, err := s.livekitService.VerifyJoinToken(token)
+	if err != nil {
+		return err
+	}
+
+	room := claims.Video.Room
+	if _, err := s.meetingService.CheckParticipant(room, claims.Identity); err != nil {
+		return err
+	}
+	allowed, err := cache.AllowReaction(room, claims.Identity)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.New("rate limited: too many reactions")
+	}
+
+	payload, err := json.Marshal(&reactionMessage{
+		Identity: claims.Identity,
+		Name:     participantName(claims),
+		Reaction: reaction,
+		SentAt:   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode reaction: %w", err)
+	}
+	return s.livekitService.SendData(room, nil, reactionTopic, payload)
+}
+
+// GetQueue returns the raised hands of a meeting's main room or of one of its
+// breakout rooms, longest waiting first (hosts only)
+func (s *HandRaiseService) GetQueue(meetingCode string, roomName string, userID uint) ([]cache.RaisedHand, error) {
+	_, room, err := s.hostRoom(meetingCode, roomName, userID)
+	if err != nil {
+		return nil, err
+	}
+	return cache.ListRaisedHands(room)
+}
+
+// LowerHand lowers the hand of one participant, or every hand when identity is
+// empty, and returns how many hands were lowered (hosts only)
+func (s *HandRaiseService) LowerHand(meetingCode string, roomName string, userID uint, identity string) (int, error) {
+	_, room, err := s.hostRoom(meetingCode, roomName, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	lowered := 0
+	if identity == "" {
+		hands, err := cache.ListRaisedHands(room)
+		if err != nil {
+			return 0, err
+		}
+		if err := cache.ClearHands(room); err != nil {
+			return 0, err
+		}
+		lowered = len(hands)
+	} else {
+		hand, err := cache.LowerHand(room, identity)
+		if err != nil {
+			return 0, err
+		}
+		if hand == nil {
+			return 0, errors.New("raised hand not found")
+		}
+		lowered = 1
+	}
+
+	if _, err := s.mirrorHands(room, nil); err != nil {
+		return lowered, err
+	}
+	return lowered, nil
+}
+
+// CallOn calls on the participant who has waited longest, or on a given
+// participant whether or not their hand is raised, and records it (hosts only).
+// The participant may unmute again if they were blocked by a mute-all.
+func (s *HandRaiseService) CallOn(meetingCode string, roomName string, userID uint, identity string) (*models.SpeakerCall, error) {
+	meeting, room, err := s.hostRoom(meetingCode, roomName, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hand *cache.RaisedHand
+	if identity == "" {
+		hand, err = cache.PopNextHand(room)
+		if err != nil {
+			return nil, err
+		}
+		if hand == nil {
+			return nil, errors.New("raised hand not found")
+		}
+	} else {
+		hand, err = cache.LowerHand(room, identity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	call := &models.SpeakerCall{
+		MeetingID: meeting.ID,
+		CalledBy:  userID,
+		CalledAt:  time.Now(),
+	}
+	if room != meeting.MeetingCode {
+		call.RoomName = room
+	}
+
+	if hand != nil {
+		raisedAt := hand.RaisedAt
+		call.Identity = hand.Identity
+		call.Name = hand.Name
+		call.RaisedAt = &raisedAt
+	} else {
+		participant, err := s.findParticipant(room, identity)
+		if err != nil {
+			return nil, err
+		}
+		call.Identity = participant.Identity
+		call.Name = participant.Name
+	}
+
+	if p, err := cache.GetParticipant(meeting.MeetingCode, call.Identity); err == nil && p.UserID > 0 {
+		participantUserID := p.UserID
+		call.UserID = &participantUserID
+	}
+
+	if err := s.speakerCallRepo.Create(call); err != nil {
+		return nil, fmt.Errorf("failed to record speaker call: %w", err)
+	}
+
+	if err := s.livekitService.AllowMicrophone(meeting.MeetingCode, room, call.Identity); err != nil {
+		fmt.Printf("HandRaiseService: Failed to let %s unmute in %s: %v\n", call.Identity, room, err)
+	}
+
+	if _, err := s.mirrorHands(room, call); err != nil {
+		fmt.Printf("HandRaiseService: Failed to mirror hands of room %s: %v\n", room, err)
+	}
+
+	return call, nil
+}
+
+// GetSpeakerCalls reports who was called on in a meeting, across its rooms (hosts only)
+func (s *HandRaiseService) GetSpeakerCalls(meetingID uint, userID uint) (*dto.SpeakerCallReport, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can view speaker calls")
+	}
+
+	calls, err := s.speakerCallRepo.FindByMeetingID(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch speaker calls: %w", err)
+	}
+
+	summaries := make(map[string]*dto.SpeakerCallSummary)
+	waits := make(map[string][]float64)
+	order := make([]string, 0)
+	for _, call := range calls {
+		summary, exists := summaries[call.Identity]
+		if !exists {
+			summary = &dto.SpeakerCallSummary{Identity: call.Identity, Name: call.Name}
+			summaries[call.Identity] = summary
+			order = append(order, call.Identity)
+		}
+		summary.Calls++
+		if call.UserID != nil {
+			summary.UserID = call.UserID
+		}
+		if call.RaisedAt != nil {
+			waits[call.Identity] = append(waits[call.Identity], call.CalledAt.Sub(*call.RaisedAt).Seconds())
+		}
+	}
+
+	participants := make([]dto.SpeakerCallSummary, 0, len(order))
+	for _, identity := range order {
+		summary := summaries[identity]
+		if w := waits[identity]; len(w) > 0 {
+			total := 0.0
+			for _, seconds := range w {
+				total += seconds
+			}
+			summary.AverageWaitSeconds = total / float64(len(w))
+		}
+		participants = append(participants, *summary)
+	}
+
+	// Most called on first
+	sort.SliceStable(participants, func(i, j int) bool {
+		return participants[i].Calls > participants[j].Calls
+	})
+
+	return &dto.SpeakerCallReport{
+		Calls:        calls,
+		Participants: participants,
+	}, nil
+}
+
+// HandleWebhookEvent lowers the hand of a participant who left and forgets the
+// hands and reactions of a room that finished
+func (s *HandRaiseService) HandleWebhookEvent(event *livekit.WebhookEvent) {
+	if event.Room == nil {
+		return
+	}
+
+	switch event.Event {
+	case "participant_left":
+		if event.Participant == nil {
+			return
+		}
+		hand, err := cache.LowerHand(event.Room.Name, event.Participant.Identity)
+		if err != nil || hand == nil {
+			return
+		}
+		if _, err := s.mirrorHands(event.Room.Name, nil); err != nil {
+			fmt.Printf("HandRaiseService: Failed to mirror hands of room %s: %v\n", event.Room.Name, err)
+		}
+	case "room_finished":
+		cache.ClearHands(event.Room.Name)
+	}
+}
+
+// mirrorHands copies the queue of a room into its metadata, along with the
+// participant just called on when call is set, and returns the queue
+func (s *HandRaiseService) mirrorHands(room string, call *models.SpeakerCall) ([]cache.RaisedHand, error) {
+	hands, err := cache.ListRaisedHands(room)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"raised_hands": hands}
+	if call != nil {
+		updates["speaker"] = map[string]interface{}{
+			"identity":  call.Identity,
+			"name":      call.Name,
+			"called_at": call.CalledAt,
+		}
+	}
+	if err := s.livekitService.MergeRoomMetadata(room, updates); err != nil {
+		fmt.Printf("HandRaiseService: Failed to mirror hands of room %s: %v\n", room, err)
+	}
+	return hands, nil
+}
+
+// hostRoom returns a meeting userID hosts and the LiveKit room of its main room,
+// or of the breakout room roomName
+func (s *HandRaiseService) hostRoom(meetingCode string, roomName string, userID uint) (*models.Meeting, string, error) {
+	meeting, err := s.meetingService.GetMeetingByCode(meetingCode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, "", errors.New("unauthorized: only meeting hosts can manage raised hands")
+	}
+
+	if roomName == "" {
+		return meeting, meeting.MeetingCode, nil
+	}
+	if code, _, ok := ParseBreakoutRoomName(roomName); !ok || code != meeting.MeetingCode {
+		return nil, "", errors.New("invalid room_name: not a breakout room of this meeting")
+	}
+	return meeting, roomName, nil
+}
+
+// findParticipant returns a participant in a room by identity
+func (s *HandRaiseService) findParticipant(room string, identity string) (*livekit.ParticipantInfo, error) {
+	participants, err := s.livekitService.ListParticipants(room)
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if participant.Identity == identity {
+			return participant, nil
+		}
+	}
+	return nil, errors.New("participant not found")
+}
+
+// participantName returns the display name in a join token, or its identity
+func participantName(claims *auth.ClaimGrants) string {
+	if claims.Name != "" {
+		return claims.Name
+	}
+	return claims.Identity
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mini-meeting/internal/config"
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// rsvpLinkLifetime is how long the RSVP links of an invitation email work.
+// Invitees can still answer from the app once their links have expired.
+const rsvpLinkLifetime = 60 * 24 * time.Hour
+
+// InvitationService emails meeting invitations and reminders to invitees and records their RSVPs
+type InvitationService struct {
+	meetingService *MeetingService
+	meetingRepo    *repositories.MeetingRepository
+	inviteeRepo    *repositories.MeetingInviteeRepository
+	userRepo       *repositories.UserRepository
+	emailService   *EmailService
+	cfg            *config.Config
+}
+
+func NewInvitationService(
+	meetingService *MeetingService,
+	meetingRepo *repositories.MeetingRepository,
+	inviteeRepo *repositories.MeetingInviteeRepository,
+	userRepo *repositories.UserRepository,
+	emailService *EmailService,
+	cfg *config.Config,
+) *InvitationService {
+	return &InvitationService{
+		meetingService: meetingService,
+		meetingRepo:    meetingRepo,
+		inviteeRepo:    inviteeRepo,
+		userRepo:       userRepo,
+		emailService:   emailService,
+		cfg:            cfg,
+	}
+}
+
+// Invite adds emails to the invitee list of a meeting owned by userID and
+// emails an invitation to the ones that were not invited before
+func (s *InvitationService) Invite(meetingID uint, userID uint, emails []string) ([]models.MeetingInvitee, error) {
+	invitees, err := s.meetingService.AddInvitees(meetingID, userID, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.SendPendingInvitations(meetingID)
+
+	return invitees, nil
+}
+
+// SendPendingInvitations emails an invitation to every invitee of a meeting that was not sent one yet
+func (s *InvitationService) SendPendingInvitations(meetingID uint) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		fmt.Printf("InvitationService: Failed to load meeting %d: %v\n", meetingID, err)
+		return
+	}
+
+	invitees, err := s.inviteeRepo.FindUninvitedByMeetingID(meetingID)
+	if err != nil {
+		fmt.Printf("InvitationService: Failed to load invitees of meeting %d: %v\n", meetingID, err)
+		return
+	}
+	if len(invitees) == 0 {
+		return
+	}
+
+	email := s.meetingEmail(meeting, s.meetingService.NextOccurrence(meeting, time.Now()))
+	if meeting.IsScheduled() {
+		if calendar, err := s.meetingService.InviteCalendar(meeting); err == nil {
+			email.ICS = calendar.Bytes()
+		} else {
+			fmt.Printf("InvitationService: Failed to build invite for meeting %d: %v\n", meetingID, err)
+		}
+	}
+
+	expires := time.Now().Add(rsvpLinkLifetime)
+	for _, invitee := range invitees {
+		// Claim the invitee before sending, in case another instance is sending too
+		claimed, err := s.inviteeRepo.ClaimInvitation(invitee.ID, time.Now())
+		if err != nil {
+			fmt.Printf("InvitationService: Failed to mark invitee %d invited: %v\n", invitee.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		email.ToEmail = invitee.Email
+		email.AcceptURL = s.rsvpURL(&invitee, models.RSVPAccepted, expires)
+		email.MaybeURL = s.rsvpURL(&invitee, models.RSVPTentative, expires)
+		email.DeclineURL = s.rsvpURL(&invitee, models.RSVPDeclined, expires)
+
+		if err := s.emailService.SendInvitationEmail(email); err != nil {
+			fmt.Printf("InvitationService: Failed to invite %s to meeting %d: %v\n", invitee.Email, meetingID, err)
+			if err := s.inviteeRepo.ReleaseInvitation(invitee.ID); err != nil {
+				fmt.Printf("InvitationService: Failed to unmark invitee %d invited: %v\n", invitee.ID, err)
+			}
+		}
+	}
+}
+
+// SendDueReminders reminds invitees who did not decline of meeting occurrences
+// starting within the configured reminder lead time. Each invitee is reminded
+// once per occurrence.
+func (s *InvitationService) SendDueReminders() {
+	lead := time.Duration(s.cfg.Scheduling.ReminderMinutes) * time.Minute
+	if lead <= 0 {
+		return
+	}
+
+	now := time.Now()
+	meetings, err := s.meetingRepo.FindReminderCandidates(now, now.Add(lead))
+	if err != nil {
+		fmt.Printf("InvitationService: Failed to load meetings to remind: %v\n", err)
+		return
+	}
+
+	for i := range meetings {
+		meeting := &meetings[i]
+		occurrence := s.meetingService.NextOccurrence(meeting, now)
+		if occurrence == nil || occurrence.StartTime.Before(now) || occurrence.StartTime.After(now.Add(lead)) {
+			continue
+		}
+
+		invitees, err := s.inviteeRepo.FindToRemind(meeting.ID, occurrence.OriginalStart())
+		if err != nil {
+			fmt.Printf("InvitationService: Failed to load invitees of meeting %d: %v\n", meeting.ID, err)
+			continue
+		}
+
+		email := s.meetingEmail(meeting, occurrence)
+		for _, invitee := range invitees {
+			// Claim the invitee before sending, since every instance runs the reminder worker
+			claimed, err := s.inviteeRepo.ClaimReminder(invitee.ID, occurrence.OriginalStart())
+			if err != nil {
+				fmt.Printf("InvitationService: Failed to mark invitee %d reminded: %v\n", invitee.ID, err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			email.ToEmail = invitee.Email
+			if err := s.emailService.SendReminderEmail(email); err != nil {
+				fmt.Printf("InvitationService: Failed to remind %s of meeting %d: %v\n", invitee.Email, meeting.ID, err)
+				if err := s.inviteeRepo.ReleaseReminder(invitee.ID, occurrence.OriginalStart(), invitee.RemindedFor); err != nil {
+					fmt.Printf("InvitationService: Failed to unmark invitee %d reminded: %v\n", invitee.ID, err)
+				}
+			}
+		}
+	}
+}
+
+// CheckRSVPLink verifies the signed link of an invitation email without recording
+// anything, and returns the meeting it answers
+func (s *InvitationService) CheckRSVPLink(inviteeID uint, expires int64, signature string, response models.RSVPStatus) (*models.Meeting, error) {
+	invitee, err := s.linkInvitee(inviteeID, expires, signature, response)
+	if err != nil {
+		return nil, err
+	}
+	return s.meetingService.GetMeetingByID(invitee.MeetingID)
+}
+
+// RespondWithLink records the RSVP given through the signed link of an invitation email
+func (s *InvitationService) RespondWithLink(inviteeID uint, expires int64, signature string, response models.RSVPStatus) (*models.Meeting, error) {
+	invitee, err := s.linkInvitee(inviteeID, expires, signature, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.inviteeRepo.UpdateRSVP(invitee.ID, response, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.meetingService.GetMeetingByID(invitee.MeetingID)
+}
+
+// linkInvitee returns the invitee an RSVP link was signed for
+func (s *InvitationService) linkInvitee(inviteeID uint, expires int64, signature string, response models.RSVPStatus) (*models.MeetingInvitee, error) {
+	if !response.IsValidResponse() {
+		return nil, errors.New("invalid response: must be accepted, tentative or declined")
+	}
+
+	invitee, err := s.inviteeRepo.FindByID(inviteeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitee not found")
+		}
+		return nil, err
+	}
+
+	if err := verifyRSVPLink(s.cfg.JWT.Secret, invitee, expires, signature, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return invitee, nil
+}
+
+// RespondAsUser records the RSVP of a signed-in user invited to a meeting
+func (s *InvitationService) RespondAsUser(meetingID uint, userID uint, response models.RSVPStatus) (*models.MeetingInvitee, error) {
+	if !response.IsValidResponse() {
+		return nil, errors.New("invalid response: must be accepted, tentative or declined")
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	invitee, err := s.inviteeRepo.FindByMeetingAndEmail(meetingID, user.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitee not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.inviteeRepo.UpdateRSVP(invitee.ID, response, now); err != nil {
+		return nil, err
+	}
+
+	invitee.RSVPStatus = response
+	invitee.RespondedAt = &now
+	return invitee, nil
+}
+
+// meetingEmail returns the email describing a meeting and, when given, one of its occurrences
+func (s *InvitationService) meetingEmail(meeting *models.Meeting, occurrence *models.MeetingOccurrence) MeetingEmail {
+	email := MeetingEmail{
+		Title:    meeting.Title,
+		HostName: meeting.Creator.Name,
+		JoinURL:  s.meetingService.MeetingJoinURL(meeting),
+	}
+	if email.Title == "" {
+		email.Title = "Meeting " + meeting.MeetingCode
+	}
+
+	if occurrence != nil {
+		if occurrence.Title != "" {
+			email.Title = occurrence.Title
+		}
+		location, err := time.LoadLocation(meeting.TimeZone)
+		if err != nil {
+			location = time.UTC
+		}
+		email.When = occurrence.StartTime.In(location).Format("Mon, Jan 2, 2006 3:04 PM MST")
+		if meeting.IsRecurring() {
+			email.When += " (recurring)"
+		}
+	}
+
+	return email
+}
+
+// rsvpURL returns the signed link an invitee follows to answer an invitation
+func (s *InvitationService) rsvpURL(invitee *models.MeetingInvitee, response models.RSVPStatus, expires time.Time) string {
+	query := url.Values{}
+	query.Set("response", string(response))
+	query.Set("exp", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("sig", signRSVPLink(s.cfg.JWT.Secret, invitee, expires.Unix()))
+	return fmt.Sprintf("%s/api/v1/invitations/%d/rsvp?%s", strings.TrimRight(s.cfg.Server.APIURL, "/"), invitee.ID, query.Encode())
+}
+
+// signRSVPLink signs an invitee's identity and the expiry of their link (unix
+// seconds), so RSVP links cannot be forged for other invitees or extended
+func signRSVPLink(secret string, invitee *models.MeetingInvitee, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "rsvp:%d:%d:%s:%d", invitee.ID, invitee.MeetingID, strings.ToLower(invitee.Email), expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyRSVPLink checks that an RSVP link was signed for invitee and has not expired at now
+func verifyRSVPLink(secret string, invitee *models.MeetingInvitee, expires int64, signature string, now time.Time) error {
+	expected := signRSVPLink(secret, invitee, expires)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errors.New("unauthorized: invalid RSVP link")
+	}
+	if now.Unix() >= expires {
+		return errors.New("unauthorized: this RSVP link has expired, answer from the meeting page instead")
+	}
+	return nil
+}
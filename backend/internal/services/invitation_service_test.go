@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mini-meeting/internal/models"
+)
+
+func TestVerifyRSVPLink(t *testing.T) {
+	const secret = "test-secret"
+	now := time.Date(2026, time.March, 2, 14, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour).Unix()
+	invitee := &models.MeetingInvitee{ID: 7, MeetingID: 3, Email: "Alice@Acme.com"}
+	signature := signRSVPLink(secret, invitee, expires)
+
+	tests := []struct {
+		name      string
+		secret    string
+		invitee   models.MeetingInvitee
+		expires   int64
+		signature string
+		now       time.Time
+		err       string
+	}{
+		{"valid", secret, *invitee, expires, signature, now, ""},
+		{"email case", secret, models.MeetingInvitee{ID: 7, MeetingID: 3, Email: "alice@acme.com"}, expires, signature, now, ""},
+		{"just before expiry", secret, *invitee, expires, signature, time.Unix(expires-1, 0), ""},
+		{"expired", secret, *invitee, expires, signature, time.Unix(expires, 0), "expired"},
+		{"long expired", secret, *invitee, expires, signature, now.AddDate(1, 0, 0), "expired"},
+		{"extended expiry", secret, *invitee, expires + 3600, signature, now, "invalid RSVP link"},
+		{"missing expiry", secret, *invitee, 0, signature, now, "invalid RSVP link"},
+		{"other invitee", secret, models.MeetingInvitee{ID: 8, MeetingID: 3, Email: "alice@acme.com"}, expires, signature, now, "invalid RSVP link"},
+		{"other meeting", secret, models.MeetingInvitee{ID: 7, MeetingID: 4, Email: "alice@acme.com"}, expires, signature, now, "invalid RSVP link"},
+		{"other email", secret, models.MeetingInvitee{ID: 7, MeetingID: 3, Email: "bob@acme.com"}, expires, signature, now, "invalid RSVP link"},
+		{"tampered signature", secret, *invitee, expires, signature[:len(signature)-1] + "A", now, "invalid RSVP link"},
+		{"truncated signature", secret, *invitee, expires, signature[:10], now, "invalid RSVP link"},
+		{"empty signature", secret, *invitee, expires, "", now, "invalid RSVP link"},
+		{"other secret", "another-secret", *invitee, expires, signature, now, "invalid RSVP link"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyRSVPLink(tt.secret, &tt.invitee, tt.expires, tt.signature, tt.now)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("verifyRSVPLink: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("verifyRSVPLink: got error %v, want one containing %q", err, tt.err)
+			}
+			if !strings.HasPrefix(err.Error(), "unauthorized:") {
+				t.Errorf("error %q is not reported as unauthorized", err)
+			}
+		})
+	}
+}
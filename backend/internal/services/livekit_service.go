@@ -2,21 +2,32 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"mini-meeting/internal/cache"
 	"mini-meeting/internal/config"
+	"mini-meeting/internal/models"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/webhook"
 	lksdk "github.com/livekit/server-sdk-go/v2"
 )
 
 type LiveKitService struct {
-	roomService *lksdk.RoomServiceClient
-	apiKey      string
-	apiSecret   string
-	url         string
+	roomService   *lksdk.RoomServiceClient
+	egressService *lksdk.EgressClient
+	apiKey        string
+	apiSecret     string
+	url           string
+
+	emptyTimeout uint32
 }
 
 func NewLiveKitService(cfg *config.Config) *LiveKitService {
@@ -26,11 +37,20 @@ func NewLiveKitService(cfg *config.Config) *LiveKitService {
 		cfg.LiveKit.APISecret,
 	)
 
+	egressService := lksdk.NewEgressClient(
+		cfg.LiveKit.URL,
+		cfg.LiveKit.APIKey,
+		cfg.LiveKit.APISecret,
+	)
+
 	return &LiveKitService{
-		roomService: roomService,
-		apiKey:      cfg.LiveKit.APIKey,
-		apiSecret:   cfg.LiveKit.APISecret,
-		url:         cfg.LiveKit.URL,
+		roomService:   roomService,
+		egressService: egressService,
+		apiKey:        cfg.LiveKit.APIKey,
+		apiSecret:     cfg.LiveKit.APISecret,
+		url:           cfg.LiveKit.URL,
+
+		emptyTimeout: uint32(cfg.LiveKit.EmptyTimeoutSeconds),
 	}
 }
 
@@ -38,7 +58,7 @@ func NewLiveKitService(cfg *config.Config) *LiveKitService {
 // RoomCode should be the meeting code from the database
 // identity is the user ID as string
 // userName is the display name for the participant
-// userRole is the user's role (admin, user, etc.)
+// userRole is the user's role (admin, cohost, presenter, user, guest, attendee, viewer)
 // metadata can include user name, avatar, etc.
 func (s *LiveKitService) CreateJoinToken(
 	RoomCode string,
@@ -56,26 +76,25 @@ func (s *LiveKitService) CreateJoinToken(
 		at.SetMetadata(metadata)
 	}
 
-	// Base grant - all users can join, publish, and subscribe
-	grant := &auth.VideoGrant{
-		RoomJoin:       true,
-		Room:           RoomCode,
-		CanPublish:     &[]bool{true}[0],
-		CanSubscribe:   &[]bool{true}[0],
-		CanPublishData: &[]bool{true}[0], // For chat/data messages
+	// Role-based permissions
+	role := models.ParticipantRole(userRole)
+	permission := rolePermission(role)
+	sources := make([]string, 0, len(permission.CanPublishSources))
+	for _, source := range permission.CanPublishSources {
+		sources = append(sources, strings.ToLower(source.String()))
 	}
 
-	// Role-based permissions
-	if userRole == "admin" {
-		// Admin has full control
-		canUpdateMetadata := true
-		grant.CanUpdateOwnMetadata = &canUpdateMetadata
-		// Admin can also be marked as room admin
-		grant.RoomAdmin = true
-	} else {
-		// Regular users - more restricted
-		canUpdateMetadata := false
-		grant.CanUpdateOwnMetadata = &canUpdateMetadata
+	grant := &auth.VideoGrant{
+		RoomJoin:             true,
+		Room:                 RoomCode,
+		CanPublish:           &permission.CanPublish,
+		CanSubscribe:         &permission.CanSubscribe,
+		CanPublishData:       &permission.CanPublishData, // For chat/data messages
+		CanUpdateOwnMetadata: &permission.CanUpdateMetadata,
+		CanPublishSources:    sources,
+		Hidden:               permission.Hidden,
+		// Admin and co-hosts have full control of the room
+		RoomAdmin: role.IsHost(),
 	}
 
 	at.SetVideoGrant(grant)
@@ -91,6 +110,109 @@ func (s *LiveKitService) CreateJoinToken(
 	return token, nil
 }
 
+// participantMetadata is the LiveKit metadata given to people joining a meeting
+type participantMetadata struct {
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+	Role   string `json:"role"`
+}
+
+// ParticipantMetadata returns the LiveKit metadata of a person joining a meeting.
+// The name is chosen by the participant, so it is encoded rather than pasted
+// into the JSON.
+func ParticipantMetadata(name, avatar, role string) string {
+	data, err := json.Marshal(participantMetadata{Name: name, Avatar: avatar, Role: role})
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// rolePermission returns the LiveKit permissions that go with a participant role.
+// Unknown roles get the permissions of a regular participant.
+func rolePermission(role models.ParticipantRole) *livekit.ParticipantPermission {
+	media := []livekit.TrackSource{
+		livekit.TrackSource_CAMERA,
+		livekit.TrackSource_MICROPHONE,
+		livekit.TrackSource_SCREEN_SHARE,
+		livekit.TrackSource_SCREEN_SHARE_AUDIO,
+	}
+
+	switch role {
+	case models.RoleHost, models.RoleCohost:
+		return &livekit.ParticipantPermission{
+			CanSubscribe:      true,
+			CanPublish:        true,
+			CanPublishData:    true,
+			CanPublishSources: media,
+			CanUpdateMetadata: true,
+		}
+	case models.RolePresenter:
+		return &livekit.ParticipantPermission{
+			CanSubscribe:      true,
+			CanPublish:        true,
+			CanPublishData:    true,
+			CanPublishSources: media,
+		}
+	case models.RoleAttendee:
+		// Listen-only, may still chat
+		return &livekit.ParticipantPermission{
+			CanSubscribe:   true,
+			CanPublishData: true,
+		}
+	case models.RoleViewer:
+		return &livekit.ParticipantPermission{
+			CanSubscribe: true,
+			Hidden:       true,
+		}
+	}
+
+	return &livekit.ParticipantPermission{
+		CanSubscribe:      true,
+		CanPublish:        true,
+		CanPublishData:    true,
+		CanPublishSources: media,
+	}
+}
+
+// UpdateParticipantRole changes the role of a participant already in the room:
+// the "role" field of their metadata and the permissions that go with it.
+func (s *LiveKitService) UpdateParticipantRole(RoomCode string, participantIdentity string, role string) error {
+	ctx := context.Background()
+
+	participant, err := s.roomService.GetParticipant(ctx, &livekit.RoomParticipantIdentity{
+		Room:     RoomCode,
+		Identity: participantIdentity,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get participant: %w", err)
+	}
+
+	metadata := map[string]interface{}{}
+	if participant.Metadata != "" {
+		if err := json.Unmarshal([]byte(participant.Metadata), &metadata); err != nil {
+			return fmt.Errorf("failed to parse participant metadata: %w", err)
+		}
+	}
+	metadata["role"] = role
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal participant metadata: %w", err)
+	}
+
+	_, err = s.roomService.UpdateParticipant(ctx, &livekit.UpdateParticipantRequest{
+		Room:       RoomCode,
+		Identity:   participantIdentity,
+		Metadata:   string(data),
+		Permission: rolePermission(models.ParticipantRole(role)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update participant role: %w", err)
+	}
+	return nil
+}
+
 // RemoveParticipant removes a participant from a room (kick)
 func (s *LiveKitService) RemoveParticipant(RoomCode string, participantIdentity string) error {
 	_, err := s.roomService.RemoveParticipant(context.Background(), &livekit.RoomParticipantIdentity{
@@ -114,6 +236,47 @@ func (s *LiveKitService) ListParticipants(RoomCode string) ([]*livekit.Participa
 	return response.Participants, nil
 }
 
+// botSeats is the number of participants the server itself may add to a room:
+// the summarizer bot and the chat recorder
+const botSeats = 2
+
+// CreateRoom creates the room of a meeting with its participant limit (0 = no limit)
+// before anyone joins. Creating a room that already exists leaves it unchanged.
+func (s *LiveKitService) CreateRoom(RoomCode string, maxParticipants int) error {
+	// LiveKit counts the summarizer bot and the chat recorder toward its limit,
+	// so they get seats on top of the meeting's own
+	if maxParticipants > 0 {
+		maxParticipants += botSeats
+	}
+	_, err := s.roomService.CreateRoom(context.Background(), &livekit.CreateRoomRequest{
+		Name:            RoomCode,
+		EmptyTimeout:    s.emptyTimeout,
+		MaxParticipants: uint32(maxParticipants),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create room: %w", err)
+	}
+	return nil
+}
+
+// CountAttendees returns how many people take a seat in a room: hidden
+// participants, such as viewers and the chat recorder, and bots, agents and
+// egress are left out
+func (s *LiveKitService) CountAttendees(RoomCode string) (int, error) {
+	participants, err := s.ListParticipants(RoomCode)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, participant := range participants {
+		if isHumanParticipantInfo(participant) && !participant.GetPermission().GetHidden() {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // ListRooms lists all active rooms
 func (s *LiveKitService) ListRooms() ([]*livekit.Room, error) {
 	response, err := s.roomService.ListRooms(context.Background(), &livekit.ListRoomsRequest{})
@@ -148,6 +311,230 @@ func (s *LiveKitService) MuteParticipantTrack(RoomCode string, participantIdenti
 	return nil
 }
 
+// MuteAll mutes the tracks of the given sources published by every participant
+// of a room of the meeting who is not a host and returns how many tracks were
+// muted. With block set, the participants also lose permission to publish those
+// sources, so they cannot unmute themselves until AllowSources restores their
+// role's permissions.
+func (s *LiveKitService) MuteAll(meetingCode string, RoomCode string, sources []livekit.TrackSource, block bool) (int, error) {
+	participants, err := s.ListParticipants(RoomCode)
+	if err != nil {
+		return 0, err
+	}
+
+	muted := 0
+	for _, participant := range participants {
+		if participant.Kind != livekit.ParticipantInfo_STANDARD || participantRole(meetingCode, participant).IsHost() {
+			continue
+		}
+
+		for _, track := range participant.Tracks {
+			if track.Muted || !containsSource(sources, track.Source) {
+				continue
+			}
+			if err := s.MuteParticipantTrack(RoomCode, participant.Identity, track.Sid, true); err != nil {
+				return muted, err
+			}
+			muted++
+		}
+
+		if block {
+			if err := s.blockSources(meetingCode, RoomCode, participant, sources); err != nil {
+				return muted, err
+			}
+		}
+	}
+
+	return muted, nil
+}
+
+// AllowSources gives participants of a room of the meeting back the publish
+// permissions of their role, lifting blocks set by MuteAll. An empty identity
+// applies to everyone.
+func (s *LiveKitService) AllowSources(meetingCode string, RoomCode string, participantIdentity string) error {
+	participants, err := s.ListParticipants(RoomCode)
+	if err != nil {
+		return err
+	}
+
+	for _, participant := range participants {
+		if participant.Kind != livekit.ParticipantInfo_STANDARD {
+			continue
+		}
+		if participantIdentity != "" && participant.Identity != participantIdentity {
+			continue
+		}
+		_, err := s.roomService.UpdateParticipant(context.Background(), &livekit.UpdateParticipantRequest{
+			Room:       RoomCode,
+			Identity:   participant.Identity,
+			Permission: rolePermission(participantRole(meetingCode, participant)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update participant permissions: %w", err)
+		}
+	}
+	return nil
+}
+
+// AllowMicrophone lets one participant of a room of the meeting publish their
+// microphone again after a mute-all blocked it. Other blocks, such as disabled
+// video, stay in place, and roles that cannot publish audio are left unchanged.
+func (s *LiveKitService) AllowMicrophone(meetingCode string, RoomCode string, participantIdentity string) error {
+	participant, err := s.roomService.GetParticipant(context.Background(), &livekit.RoomParticipantIdentity{
+		Room:     RoomCode,
+		Identity: participantIdentity,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get participant: %w", err)
+	}
+
+	role := rolePermission(participantRole(meetingCode, participant))
+	if !containsSource(role.CanPublishSources, livekit.TrackSource_MICROPHONE) {
+		return nil
+	}
+
+	permission := participant.Permission
+	if permission == nil {
+		permission = role
+	}
+	// An empty list with publishing allowed means every source is allowed
+	allowed := permission.CanPublishSources
+	if permission.CanPublish && (len(allowed) == 0 || containsSource(allowed, livekit.TrackSource_MICROPHONE)) {
+		return nil
+	}
+
+	sources := make([]livekit.TrackSource, 0, len(allowed)+1)
+	for _, source := range allowed {
+		if source != livekit.TrackSource_MICROPHONE {
+			sources = append(sources, source)
+		}
+	}
+	sources = append(sources, livekit.TrackSource_MICROPHONE)
+
+	updated := &livekit.ParticipantPermission{
+		CanSubscribe:      permission.CanSubscribe,
+		CanPublish:        true,
+		CanPublishData:    permission.CanPublishData,
+		CanPublishSources: sources,
+		CanUpdateMetadata: permission.CanUpdateMetadata,
+		Hidden:            permission.Hidden,
+	}
+
+	_, err = s.roomService.UpdateParticipant(context.Background(), &livekit.UpdateParticipantRequest{
+		Room:       RoomCode,
+		Identity:   participant.Identity,
+		Permission: updated,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update participant permissions: %w", err)
+	}
+	return nil
+}
+
+// SendData sends a reliable data message on a topic to some participants of a room
+func (s *LiveKitService) SendData(RoomCode string, identities []string, topic string, payload []byte) error {
+	_, err := s.roomService.SendData(context.Background(), &livekit.SendDataRequest{
+		Room:                  RoomCode,
+		Data:                  payload,
+		Kind:                  livekit.DataPacket_RELIABLE,
+		DestinationIdentities: identities,
+		Topic:                 &topic,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send data: %w", err)
+	}
+	return nil
+}
+
+// StartRoomCompositeEgress starts recording the composed view of a room
+func (s *LiveKitService) StartRoomCompositeEgress(req *livekit.RoomCompositeEgressRequest) (*livekit.EgressInfo, error) {
+	info, err := s.egressService.StartRoomCompositeEgress(context.Background(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start room composite egress: %w", err)
+	}
+	return info, nil
+}
+
+// StartTrackEgress starts recording a single track of a room, without transcoding
+func (s *LiveKitService) StartTrackEgress(req *livekit.TrackEgressRequest) (*livekit.EgressInfo, error) {
+	info, err := s.egressService.StartTrackEgress(context.Background(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start track egress: %w", err)
+	}
+	return info, nil
+}
+
+// StopEgress stops an egress; its file is finalized and reported by webhook
+func (s *LiveKitService) StopEgress(egressID string) (*livekit.EgressInfo, error) {
+	info, err := s.egressService.StopEgress(context.Background(), &livekit.StopEgressRequest{
+		EgressId: egressID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop egress: %w", err)
+	}
+	return info, nil
+}
+
+// blockSources removes sources from what a participant may publish
+func (s *LiveKitService) blockSources(meetingCode string, RoomCode string, participant *livekit.ParticipantInfo, sources []livekit.TrackSource) error {
+	permission := participant.Permission
+	if permission == nil {
+		permission = rolePermission(participantRole(meetingCode, participant))
+	}
+
+	// An empty list allows every source, so start from the role's list
+	allowed := permission.CanPublishSources
+	if len(allowed) == 0 {
+		allowed = rolePermission(models.RoleParticipant).CanPublishSources
+	}
+
+	remaining := make([]livekit.TrackSource, 0, len(allowed))
+	for _, source := range allowed {
+		if !containsSource(sources, source) {
+			remaining = append(remaining, source)
+		}
+	}
+
+	updated := &livekit.ParticipantPermission{
+		CanSubscribe:      permission.CanSubscribe,
+		CanPublish:        permission.CanPublish && len(remaining) > 0,
+		CanPublishData:    permission.CanPublishData,
+		CanPublishSources: remaining,
+		CanUpdateMetadata: permission.CanUpdateMetadata,
+		Hidden:            permission.Hidden,
+	}
+
+	_, err := s.roomService.UpdateParticipant(context.Background(), &livekit.UpdateParticipantRequest{
+		Room:       RoomCode,
+		Identity:   participant.Identity,
+		Permission: updated,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update participant permissions: %w", err)
+	}
+	return nil
+}
+
+// participantRole returns the role the server gave a participant of a meeting.
+// The role in their metadata is only informative and is not trusted. Participants
+// the server has no record of get the listen-only attendee role.
+func participantRole(meetingCode string, participant *livekit.ParticipantInfo) models.ParticipantRole {
+	p, err := cache.GetParticipant(meetingCode, participant.Identity)
+	if err != nil || p.Role == "" {
+		return models.RoleAttendee
+	}
+	return models.ParticipantRole(p.Role)
+}
+
+func containsSource(sources []livekit.TrackSource, source livekit.TrackSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateParticipantMetadata updates participant metadata
 func (s *LiveKitService) UpdateParticipantMetadata(RoomCode string, participantIdentity string, metadata string) error {
 	_, err := s.roomService.UpdateParticipant(context.Background(), &livekit.UpdateParticipantRequest{
@@ -173,18 +560,123 @@ func (s *LiveKitService) UpdateRoomMetadata(RoomCode string, metadata string) er
 	return nil
 }
 
+// MergeRoomMetadata sets keys of a room's JSON metadata, keeping the other keys.
+// A nil value removes the key. Merges into the same room are serialized across
+// backend instances, so that concurrent writers do not drop each other's keys.
+func (s *LiveKitService) MergeRoomMetadata(RoomCode string, updates map[string]interface{}) error {
+	lock, err := cache.AcquireLock(roomMetadataLock(RoomCode), roomMetadataLockTTL, roomMetadataLockTTL)
+	if err != nil {
+		return fmt.Errorf("failed to lock room metadata: %w", err)
+	}
+	defer lock.Release()
+
+	response, err := s.roomService.ListRooms(context.Background(), &livekit.ListRoomsRequest{
+		Names: []string{RoomCode},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get room: %w", err)
+	}
+	if len(response.Rooms) == 0 {
+		return fmt.Errorf("room not found")
+	}
+
+	metadata := map[string]interface{}{}
+	if existing := response.Rooms[0].Metadata; existing != "" {
+		if err := json.Unmarshal([]byte(existing), &metadata); err != nil {
+			// Metadata that is not a JSON object is replaced
+			metadata = map[string]interface{}{}
+		}
+	}
+	for key, value := range updates {
+		if value == nil {
+			delete(metadata, key)
+		} else {
+			metadata[key] = value
+		}
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode room metadata: %w", err)
+	}
+	return s.UpdateRoomMetadata(RoomCode, string(encoded))
+}
+
+// roomMetadataLockTTL bounds how long one metadata merge may hold a room's lock,
+// and how long another merge waits for it
+const roomMetadataLockTTL = 5 * time.Second
+
+func roomMetadataLock(RoomCode string) string {
+	return "room-metadata:" + RoomCode
+}
+
+// ReceiveWebhook verifies that a webhook request was signed by LiveKit with our
+// API key and secret and parses the event
+func (s *LiveKitService) ReceiveWebhook(r *http.Request) (*livekit.WebhookEvent, error) {
+	return webhook.ReceiveWebhookEvent(r, auth.NewSimpleKeyProvider(s.apiKey, s.apiSecret))
+}
+
+// VerifyJoinToken checks that a room join token was issued with our API key and
+// secret and has not expired, and returns its grants. Participants, guests
+// included, present it to prove which identity and room they act for.
+func (s *LiveKitService) VerifyJoinToken(token string) (*auth.ClaimGrants, error) {
+	verifier, err := auth.ParseAPIToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if verifier.APIKey() != s.apiKey {
+		return nil, errors.New("invalid token: unknown API key")
+	}
+
+	_, claims, err := verifier.Verify(s.apiSecret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if claims.Identity == "" || claims.Video == nil || !claims.Video.RoomJoin || claims.Video.Room == "" {
+		return nil, errors.New("invalid token: not a room join token")
+	}
+
+	return claims, nil
+}
+
 // GetURL returns the LiveKit WebSocket URL
 func (s *LiveKitService) GetURL() string {
 	return s.url
 }
 
-// CreateBotToken creates a token for the summarizer bot hidden from other participants with audio-only subscription
-func (s *LiveKitService) CreateBotToken(RoomCode string, sessionID uint) (string, error) {
+// Identities of the bots the backend joins to rooms. People always get an
+// identity ending in "_" and their user or connection ID, so none can take one.
+const (
+	chatRecorderIdentity        = "chat-recorder"
+	summarizerBotIdentityPrefix = "summarizer-bot-"
+)
+
+func summarizerBotIdentity(sessionID uint) string {
+	return fmt.Sprintf("%s%d", summarizerBotIdentityPrefix, sessionID)
+}
+
+// isBotIdentity reports whether identity belongs to one of the backend's bots.
+// Bots are told apart by identity rather than by metadata, which carries the
+// names participants choose.
+func isBotIdentity(identity string) bool {
+	if identity == chatRecorderIdentity {
+		return true
+	}
+	id, ok := strings.CutPrefix(identity, summarizerBotIdentityPrefix)
+	if !ok {
+		return false
+	}
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
+
+// CreateBotToken creates a token for the summarizer bot with audio-only subscription.
+// A hidden bot does not show up in the participant list.
+func (s *LiveKitService) CreateBotToken(RoomCode string, sessionID uint, hidden bool) (string, error) {
 	at := auth.NewAccessToken(s.apiKey, s.apiSecret)
 
 	// Bot identity and metadata
-	botIdentity := fmt.Sprintf("summarizer-bot-%d", sessionID)
-	at.SetIdentity(botIdentity)
+	at.SetIdentity(summarizerBotIdentity(sessionID))
 	at.SetName("Summarizer Bot")
 	at.SetMetadata(fmt.Sprintf(`{"type":"bot","session_id":%d}`, sessionID))
 
@@ -196,7 +688,8 @@ func (s *LiveKitService) CreateBotToken(RoomCode string, sessionID uint) (string
 		Room:         RoomCode,
 		CanPublish:   &canPublish,
 		CanSubscribe: &canSubscribe,
-		Hidden:       true,
+		Hidden:       hidden,
+		Recorder:     true,
 	}
 
 	at.SetVideoGrant(grant)
@@ -211,3 +704,35 @@ func (s *LiveKitService) CreateBotToken(RoomCode string, sessionID uint) (string
 
 	return token, nil
 }
+
+// CreateChatRecorderToken creates a token for the hidden bot that records the chat
+// of a room. It receives data messages but cannot publish anything.
+func (s *LiveKitService) CreateChatRecorderToken(RoomCode string) (string, error) {
+	at := auth.NewAccessToken(s.apiKey, s.apiSecret)
+
+	at.SetIdentity(chatRecorderIdentity)
+	at.SetName("Chat Recorder")
+	at.SetMetadata(`{"type":"bot","bot":"chat-recorder"}`)
+
+	canPublish := false
+	canPublishData := false
+	canSubscribe := true
+	grant := &auth.VideoGrant{
+		RoomJoin:       true,
+		Room:           RoomCode,
+		CanPublish:     &canPublish,
+		CanPublishData: &canPublishData,
+		CanSubscribe:   &canSubscribe,
+		Hidden:         true,
+	}
+
+	at.SetVideoGrant(grant)
+	at.SetValidFor(24 * time.Hour)
+
+	token, err := at.ToJWT()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate chat recorder token: %w", err)
+	}
+
+	return token, nil
+}
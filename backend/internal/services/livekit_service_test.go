@@ -0,0 +1,115 @@
+package services
+
+import (
+	"testing"
+
+	"mini-meeting/internal/models"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+)
+
+// roleGrants is what a participant of a role may do in the room
+type roleGrants struct {
+	subscribe      bool
+	publish        bool
+	publishData    bool
+	updateMetadata bool
+	hidden         bool
+	roomAdmin      bool
+}
+
+var roleTests = []struct {
+	role models.ParticipantRole
+	want roleGrants
+}{
+	{models.RoleHost, roleGrants{subscribe: true, publish: true, publishData: true, updateMetadata: true, roomAdmin: true}},
+	{models.RoleCohost, roleGrants{subscribe: true, publish: true, publishData: true, updateMetadata: true, roomAdmin: true}},
+	{models.RolePresenter, roleGrants{subscribe: true, publish: true, publishData: true}},
+	{models.RoleParticipant, roleGrants{subscribe: true, publish: true, publishData: true}},
+	{models.RoleGuest, roleGrants{subscribe: true, publish: true, publishData: true}},
+	{models.RoleAttendee, roleGrants{subscribe: true, publishData: true}},
+	{models.RoleViewer, roleGrants{subscribe: true, hidden: true}},
+	{models.ParticipantRole("superuser"), roleGrants{subscribe: true, publish: true, publishData: true}},
+	{models.ParticipantRole(""), roleGrants{subscribe: true, publish: true, publishData: true}},
+}
+
+var mediaSources = []livekit.TrackSource{
+	livekit.TrackSource_CAMERA,
+	livekit.TrackSource_MICROPHONE,
+	livekit.TrackSource_SCREEN_SHARE,
+	livekit.TrackSource_SCREEN_SHARE_AUDIO,
+}
+
+func TestRolePermission(t *testing.T) {
+	for _, tt := range roleTests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			p := rolePermission(tt.role)
+
+			got := roleGrants{
+				subscribe:      p.CanSubscribe,
+				publish:        p.CanPublish,
+				publishData:    p.CanPublishData,
+				updateMetadata: p.CanUpdateMetadata,
+				hidden:         p.Hidden,
+				roomAdmin:      tt.want.roomAdmin, // not a participant permission
+			}
+			if got != tt.want {
+				t.Errorf("rolePermission(%q) = %+v, want %+v", tt.role, got, tt.want)
+			}
+
+			if tt.want.publish && len(p.CanPublishSources) != len(mediaSources) {
+				t.Errorf("role %q may publish %v, want %v", tt.role, p.CanPublishSources, mediaSources)
+			}
+			if !tt.want.publish && len(p.CanPublishSources) != 0 {
+				t.Errorf("role %q may not publish but has sources %v", tt.role, p.CanPublishSources)
+			}
+		})
+	}
+}
+
+// The join token carries the same grants as the permissions set during the meeting
+func TestCreateJoinTokenGrants(t *testing.T) {
+	s := &LiveKitService{apiKey: "test-key", apiSecret: "test-secret-test-secret-test-secret"}
+
+	for _, tt := range roleTests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			token, err := s.CreateJoinToken("abc-defg-hij", "Alice_12", "Alice", string(tt.role), "")
+			if err != nil {
+				t.Fatalf("CreateJoinToken: %v", err)
+			}
+
+			verifier, err := auth.ParseAPIToken(token)
+			if err != nil {
+				t.Fatalf("ParseAPIToken: %v", err)
+			}
+			_, claims, err := verifier.Verify(s.apiSecret)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			grant := claims.Video
+
+			if !grant.RoomJoin || grant.Room != "abc-defg-hij" {
+				t.Errorf("grant joins %q (%v), want abc-defg-hij", grant.Room, grant.RoomJoin)
+			}
+
+			got := roleGrants{
+				subscribe:      grant.GetCanSubscribe(),
+				publish:        grant.GetCanPublish(),
+				publishData:    grant.GetCanPublishData(),
+				updateMetadata: grant.GetCanUpdateOwnMetadata(),
+				hidden:         grant.Hidden,
+				roomAdmin:      grant.RoomAdmin,
+			}
+			if got != tt.want {
+				t.Errorf("grant of %q = %+v, want %+v", tt.role, got, tt.want)
+			}
+
+			for _, source := range mediaSources {
+				if grant.GetCanPublishSource(source) != tt.want.publish {
+					t.Errorf("role %q publishing %s = %v, want %v", tt.role, source, !tt.want.publish, tt.want.publish)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mini-meeting/internal/models"
+	"mini-meeting/pkg/ical"
+	"net/url"
+	"strings"
+)
+
+const calendarProdID = "-//Mini Meeting//Meetings//EN"
+
+// MeetingInvite returns an iCalendar invite for a scheduled meeting to one of its
+// hosts or invitees. The meeting is reported as not found to anyone else, so
+// that its join URL cannot be looked up by ID.
+func (s *MeetingService) MeetingInvite(meetingID uint, userID uint) (*ical.Calendar, error) {
+	meeting, err := s.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsHost(meeting, userID) && !s.isListedInvitee(meeting, userID) {
+		return nil, errors.New("meeting not found")
+	}
+
+	return s.InviteCalendar(meeting)
+}
+
+// isListedInvitee reports whether the email of userID is on the invitee list of
+// the meeting, whatever they answered to the invitation
+func (s *MeetingService) isListedInvitee(meeting *models.Meeting, userID uint) bool {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return false
+	}
+	_, err = s.inviteeRepo.FindByMeetingAndEmail(meeting.ID, user.Email)
+	return err == nil
+}
+
+// InviteCalendar returns the iCalendar invite of a scheduled meeting
+func (s *MeetingService) InviteCalendar(meeting *models.Meeting) (*ical.Calendar, error) {
+	if !meeting.IsScheduled() {
+		return nil, errors.New("invalid invite: meeting has no start time")
+	}
+
+	events, err := s.calendarEvents(meeting)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ical.Calendar{
+		ProdID: calendarProdID,
+		Method: "PUBLISH",
+		Events: events,
+	}, nil
+}
+
+// CalendarFeed returns the subscription calendar of a user, listing every
+// scheduled meeting they create, co-host or are invited to
+func (s *MeetingService) CalendarFeed(user *models.User) (*ical.Calendar, error) {
+	meetings, err := s.repo.FindScheduledForUser(user.ID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   "Mini Meeting",
+	}
+	for i := range meetings {
+		events, err := s.calendarEvents(&meetings[i])
+		if err != nil {
+			fmt.Printf("MeetingService: Skipping meeting %d in calendar feed: %v\n", meetings[i].ID, err)
+			continue
+		}
+		calendar.Events = append(calendar.Events, events...)
+	}
+
+	return calendar, nil
+}
+
+// MeetingJoinURL returns the frontend URL that opens a meeting
+func (s *MeetingService) MeetingJoinURL(meeting *models.Meeting) string {
+	return strings.TrimRight(s.cfg.Server.FrontendURL, "/") + "/" + meeting.MeetingCode
+}
+
+// calendarEvents returns the VEVENTs of a scheduled meeting: the meeting itself and,
+// for recurring meetings, one event per moved or renamed occurrence. Cancelled
+// occurrences are excluded from the recurrence.
+func (s *MeetingService) calendarEvents(meeting *models.Meeting) ([]ical.Event, error) {
+	joinURL := s.MeetingJoinURL(meeting)
+
+	title := meeting.Title
+	if title == "" {
+		title = "Meeting " + meeting.MeetingCode
+	}
+
+	description := "Join: " + joinURL
+	if meeting.Description != "" {
+		description = meeting.Description + "\n\n" + description
+	}
+
+	status := ical.StatusConfirmed
+	if meeting.Status == models.MeetingStatusCancelled {
+		status = ical.StatusCancelled
+	}
+
+	event := ical.Event{
+		UID:          s.calendarUID(meeting),
+		Start:        *meeting.StartTime,
+		End:          meeting.EndTime,
+		TimeZone:     meeting.TimeZone,
+		Summary:      title,
+		Description:  description,
+		Location:     joinURL,
+		URL:          joinURL,
+		Status:       status,
+		Created:      meeting.CreatedAt,
+		LastModified: meeting.UpdatedAt,
+	}
+	if meeting.Creator.Email != "" {
+		event.Organizer = meeting.Creator.Email
+	}
+
+	if !meeting.IsRecurring() {
+		return []ical.Event{event}, nil
+	}
+
+	event.RRule = meeting.RecurrenceRule
+
+	overrides, err := s.overrideRepo.FindByMeetingID(meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []ical.Event{event}
+	for _, override := range overrides {
+		start := override.OccurrenceStart
+		if override.Cancelled {
+			events[0].ExDates = append(events[0].ExDates, start)
+			continue
+		}
+
+		occurrence := applyOverride(meeting, ruleOccurrence(meeting, start), &override)
+		moved := event
+		moved.RRule = ""
+		moved.RecurrenceID = &start
+		moved.Start = occurrence.StartTime
+		moved.End = occurrence.EndTime
+		if occurrence.Title != "" {
+			moved.Summary = occurrence.Title
+		}
+		moved.LastModified = override.UpdatedAt
+		events = append(events, moved)
+	}
+
+	return events, nil
+}
+
+// calendarUID returns the stable UID of a meeting's calendar event
+func (s *MeetingService) calendarUID(meeting *models.Meeting) string {
+	host := "mini-meeting"
+	if frontend, err := url.Parse(s.cfg.Server.FrontendURL); err == nil && frontend.Hostname() != "" {
+		host = frontend.Hostname()
+	}
+	return fmt.Sprintf("meeting-%d-%s@%s", meeting.ID, meeting.MeetingCode, host)
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"mini-meeting/pkg/rrule"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxOccurrenceRange bounds how far apart from and to may be when listing occurrences
+const maxOccurrenceRange = 366 * 24 * time.Hour
+
+// maxRecurrenceCount bounds the COUNT of a recurrence rule, since occurrences of
+// COUNT rules are walked from the first one every time a meeting is listed
+const maxRecurrenceCount = 1000
+
+// Occurrences returns the occurrences of a scheduled meeting starting in [from, to),
+// with overrides applied. Cancelled occurrences are included and flagged.
+// A one-off scheduled meeting has a single occurrence; an instant meeting has none.
+func (s *MeetingService) Occurrences(meeting *models.Meeting, from, to time.Time) ([]models.MeetingOccurrence, error) {
+	if !meeting.IsScheduled() {
+		return []models.MeetingOccurrence{}, nil
+	}
+
+	if !meeting.IsRecurring() {
+		occurrence := oneOffOccurrence(meeting)
+		if occurrence.StartTime.Before(from) || !occurrence.StartTime.Before(to) {
+			return []models.MeetingOccurrence{}, nil
+		}
+		return []models.MeetingOccurrence{occurrence}, nil
+	}
+
+	rule, dtstart, err := recurrence(meeting)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := s.overrideRepo.FindByMeetingID(meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+	byStart := make(map[int64]*models.MeetingOccurrenceOverride, len(overrides))
+	for i := range overrides {
+		byStart[overrides[i].OccurrenceStart.Unix()] = &overrides[i]
+	}
+
+	occurrences := []models.MeetingOccurrence{}
+	seen := make(map[int64]bool)
+	add := func(start time.Time) {
+		occurrence := applyOverride(meeting, ruleOccurrence(meeting, start), byStart[start.Unix()])
+		seen[occurrence.OccurrenceID] = true
+		if !occurrence.StartTime.Before(from) && occurrence.StartTime.Before(to) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+
+	for _, start := range rule.Between(dtstart, from, to) {
+		add(start)
+	}
+
+	// Overrides may move an occurrence into the range from outside of it
+	for _, override := range overrides {
+		start := override.OccurrenceStart
+		if seen[start.Unix()] || override.StartTime == nil {
+			continue
+		}
+		if override.StartTime.Before(from) || !override.StartTime.Before(to) {
+			continue
+		}
+		if isRuleOccurrence(rule, dtstart, start) {
+			add(start)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+
+	return occurrences, nil
+}
+
+// NextOccurrence returns the first occurrence that is not cancelled and has not
+// finished by after, or nil when the meeting has no occurrences left
+func (s *MeetingService) NextOccurrence(meeting *models.Meeting, after time.Time) *models.MeetingOccurrence {
+	if !meeting.IsScheduled() {
+		return nil
+	}
+
+	if !meeting.IsRecurring() {
+		occurrence := oneOffOccurrence(meeting)
+		if !occurrenceOver(&occurrence, after) {
+			return &occurrence
+		}
+		return nil
+	}
+
+	rule, dtstart, err := recurrence(meeting)
+	if err != nil {
+		fmt.Printf("MeetingService: Invalid recurrence rule on meeting %d: %v\n", meeting.ID, err)
+		return nil
+	}
+
+	// Look back one occurrence length so an occurrence in progress still counts,
+	// and scan a year at a time since cancelled occurrences are skipped
+	from := after
+	if meeting.EndTime != nil {
+		from = from.Add(-meeting.EndTime.Sub(*meeting.StartTime))
+	}
+	for window := 0; window < 5; window++ {
+		to := from.Add(maxOccurrenceRange)
+		occurrences, err := s.Occurrences(meeting, from, to)
+		if err != nil {
+			fmt.Printf("MeetingService: Failed to expand meeting %d: %v\n", meeting.ID, err)
+			return nil
+		}
+		for i := range occurrences {
+			if !occurrences[i].Cancelled && !occurrenceOver(&occurrences[i], after) {
+				return &occurrences[i]
+			}
+		}
+		if _, ok := rule.After(dtstart, to); !ok {
+			return nil
+		}
+		from = to
+	}
+
+	return nil
+}
+
+// ClosestOccurrence returns the occurrence of a scheduled meeting that is in
+// progress at now or, failing that, the one starting or ending nearest to now.
+// Cancelled occurrences are skipped; nil means none is within a year of now.
+func (s *MeetingService) ClosestOccurrence(meeting *models.Meeting, now time.Time) *models.MeetingOccurrence {
+	if !meeting.IsRecurring() {
+		if !meeting.IsScheduled() {
+			return nil
+		}
+		occurrence := oneOffOccurrence(meeting)
+		return &occurrence
+	}
+
+	occurrences, err := s.Occurrences(meeting, now.Add(-maxOccurrenceRange), now.Add(maxOccurrenceRange))
+	if err != nil {
+		fmt.Printf("MeetingService: Failed to expand meeting %d: %v\n", meeting.ID, err)
+		return nil
+	}
+
+	var closest *models.MeetingOccurrence
+	var closestDistance time.Duration
+	for i := range occurrences {
+		if occurrences[i].Cancelled {
+			continue
+		}
+		distance := occurrenceDistance(&occurrences[i], now)
+		if closest == nil || distance < closestDistance {
+			closest, closestDistance = &occurrences[i], distance
+		}
+	}
+	return closest
+}
+
+// GetOccurrences lists the occurrences of a meeting for its hosts
+func (s *MeetingService) GetOccurrences(meetingID uint, userID uint, from, to time.Time) ([]models.MeetingOccurrence, error) {
+	meeting, err := s.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can view occurrences")
+	}
+
+	if !to.After(from) || to.Sub(from) > maxOccurrenceRange {
+		return nil, errors.New("invalid range: to must be after from and at most 366 days later")
+	}
+
+	return s.Occurrences(meeting, from, to)
+}
+
+// SetOccurrenceOverride moves, renames or cancels one occurrence of a recurring meeting
+func (s *MeetingService) SetOccurrenceOverride(meetingID uint, userID uint, occurrenceID int64, req *dto.OccurrenceOverrideRequest) (*models.MeetingOccurrence, error) {
+	meeting, start, err := s.getOwnedOccurrence(meetingID, userID, occurrenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	override := &models.MeetingOccurrenceOverride{
+		MeetingID:       meeting.ID,
+		OccurrenceStart: start,
+		Cancelled:       req.Cancelled,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		Title:           strings.TrimSpace(req.Title),
+	}
+
+	if len(override.Title) > 255 {
+		return nil, errors.New("invalid title: must be at most 255 characters")
+	}
+	if override.StartTime != nil {
+		startTime := override.StartTime.UTC()
+		override.StartTime = &startTime
+	}
+	if override.EndTime != nil {
+		endTime := override.EndTime.UTC()
+		override.EndTime = &endTime
+	}
+
+	occurrence := applyOverride(meeting, ruleOccurrence(meeting, start), override)
+	if occurrence.EndTime != nil && !occurrence.EndTime.After(occurrence.StartTime) {
+		return nil, errors.New("invalid schedule: end_time must be after start_time")
+	}
+
+	if err := s.overrideRepo.Save(override); err != nil {
+		return nil, err
+	}
+
+	return &occurrence, nil
+}
+
+// RemoveOccurrenceOverride restores an occurrence to what the recurrence rule gives it
+func (s *MeetingService) RemoveOccurrenceOverride(meetingID uint, userID uint, occurrenceID int64) error {
+	meeting, start, err := s.getOwnedOccurrence(meetingID, userID, occurrenceID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.overrideRepo.Delete(meeting.ID, start)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("occurrence not found")
+	}
+
+	return nil
+}
+
+// getOwnedOccurrence loads a recurring meeting owned by userID and checks that
+// occurrenceID is one of the occurrences its rule gives
+func (s *MeetingService) getOwnedOccurrence(meetingID uint, userID uint, occurrenceID int64) (*models.Meeting, time.Time, error) {
+	meeting, err := s.getOwnedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if !meeting.IsRecurring() {
+		return nil, time.Time{}, errors.New("occurrence not found")
+	}
+
+	rule, dtstart, err := recurrence(meeting)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	start := time.Unix(occurrenceID, 0).UTC()
+	if !isRuleOccurrence(rule, dtstart, start) {
+		return nil, time.Time{}, errors.New("occurrence not found")
+	}
+
+	return meeting, start, nil
+}
+
+// recurrence returns the parsed rule of a recurring meeting and its first start
+// in the meeting's time zone, which keeps occurrences at the same wall-clock time
+func recurrence(meeting *models.Meeting) (*rrule.Rule, time.Time, error) {
+	location, err := time.LoadLocation(meeting.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	rule, err := rrule.ParseInLocation(meeting.RecurrenceRule, location)
+	if err != nil {
+		return nil, time.Time{}, errors.New("invalid recurrence rule: " + err.Error())
+	}
+
+	return rule, meeting.StartTime.In(location), nil
+}
+
+// isRuleOccurrence reports whether the rule gives an occurrence starting exactly at start
+func isRuleOccurrence(rule *rrule.Rule, dtstart, start time.Time) bool {
+	next, ok := rule.After(dtstart, start)
+	return ok && next.Equal(start)
+}
+
+// oneOffOccurrence returns the single occurrence of a scheduled meeting that does not repeat
+func oneOffOccurrence(meeting *models.Meeting) models.MeetingOccurrence {
+	return ruleOccurrence(meeting, *meeting.StartTime)
+}
+
+// ruleOccurrence returns the occurrence starting at start as the meeting defines it,
+// lasting as long as the meeting's first occurrence
+func ruleOccurrence(meeting *models.Meeting, start time.Time) models.MeetingOccurrence {
+	start = start.UTC()
+	occurrence := models.MeetingOccurrence{
+		OccurrenceID: start.Unix(),
+		StartTime:    start,
+		Title:        meeting.Title,
+		Cancelled:    meeting.Status == models.MeetingStatusCancelled,
+	}
+	if meeting.EndTime != nil {
+		end := start.Add(meeting.EndTime.Sub(*meeting.StartTime))
+		occurrence.EndTime = &end
+	}
+	return occurrence
+}
+
+// applyOverride returns the occurrence changed by override, which may be nil
+func applyOverride(meeting *models.Meeting, occurrence models.MeetingOccurrence, override *models.MeetingOccurrenceOverride) models.MeetingOccurrence {
+	if override == nil {
+		return occurrence
+	}
+
+	occurrence.Overridden = true
+	occurrence.Cancelled = occurrence.Cancelled || override.Cancelled
+	if override.Title != "" {
+		occurrence.Title = override.Title
+	}
+	if override.StartTime != nil {
+		occurrence.StartTime = override.StartTime.UTC()
+		if meeting.EndTime != nil {
+			end := occurrence.StartTime.Add(meeting.EndTime.Sub(*meeting.StartTime))
+			occurrence.EndTime = &end
+		}
+	}
+	if override.EndTime != nil {
+		end := override.EndTime.UTC()
+		occurrence.EndTime = &end
+	}
+	return occurrence
+}
+
+// occurrenceOver reports whether an occurrence finished before now. Occurrences
+// without an end are over once they started.
+func occurrenceOver(occurrence *models.MeetingOccurrence, now time.Time) bool {
+	if occurrence.EndTime != nil {
+		return !occurrence.EndTime.After(now)
+	}
+	return occurrence.StartTime.Before(now)
+}
+
+// occurrenceDistance returns how far now is from an occurrence; zero while it is in progress
+func occurrenceDistance(occurrence *models.MeetingOccurrence, now time.Time) time.Duration {
+	if now.Before(occurrence.StartTime) {
+		return occurrence.StartTime.Sub(now)
+	}
+	end := occurrence.StartTime
+	if occurrence.EndTime != nil {
+		end = *occurrence.EndTime
+	}
+	if now.After(end) {
+		return now.Sub(end)
+	}
+	return 0
+}
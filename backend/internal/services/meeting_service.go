@@ -3,18 +3,62 @@ package services
 import (
 	"crypto/rand"
 	"errors"
+	"fmt"
+	"mini-meeting/internal/cache"
+	"mini-meeting/internal/config"
+	"mini-meeting/internal/handlers/dto"
 	"mini-meeting/internal/models"
 	"mini-meeting/internal/repositories"
+	"mini-meeting/pkg/rrule"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
 
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type MeetingService struct {
-	repo *repositories.MeetingRepository
+	repo         *repositories.MeetingRepository
+	settingsRepo *repositories.MeetingSettingsRepository
+	inviteeRepo  *repositories.MeetingInviteeRepository
+	banRepo      *repositories.MeetingBanRepository
+	cohostRepo   *repositories.MeetingCohostRepository
+	userRepo     *repositories.UserRepository
+	overrideRepo *repositories.MeetingOccurrenceOverrideRepository
+	roleRepo     *repositories.MeetingRoleAssignmentRepository
+	cfg          *config.Config
 }
 
-func NewMeetingService(repo *repositories.MeetingRepository) *MeetingService {
-	return &MeetingService{repo: repo}
+// Meeting list filters accepted by GetMyMeetings
+const (
+	MeetingFilterUpcoming = "upcoming"
+	MeetingFilterPast     = "past"
+)
+
+func NewMeetingService(
+	repo *repositories.MeetingRepository,
+	settingsRepo *repositories.MeetingSettingsRepository,
+	inviteeRepo *repositories.MeetingInviteeRepository,
+	banRepo *repositories.MeetingBanRepository,
+	cohostRepo *repositories.MeetingCohostRepository,
+	userRepo *repositories.UserRepository,
+	overrideRepo *repositories.MeetingOccurrenceOverrideRepository,
+	roleRepo *repositories.MeetingRoleAssignmentRepository,
+	cfg *config.Config,
+) *MeetingService {
+	return &MeetingService{
+		repo:         repo,
+		settingsRepo: settingsRepo,
+		inviteeRepo:  inviteeRepo,
+		banRepo:      banRepo,
+		cohostRepo:   cohostRepo,
+		userRepo:     userRepo,
+		overrideRepo: overrideRepo,
+		roleRepo:     roleRepo,
+		cfg:          cfg,
+	}
 }
 
 // generateMeetingCode generates a unique meeting code in format "xxx-xxxx-xxx"
@@ -60,17 +104,35 @@ func (s *MeetingService) generateMeetingCode() (string, error) {
 	return "", errors.New("failed to generate unique meeting code")
 }
 
-func (s *MeetingService) CreateMeeting(creatorID uint) (*models.Meeting, error) {
+// CreateMeeting creates an instant meeting, or a scheduled one when req has a start time.
+// req may be nil.
+func (s *MeetingService) CreateMeeting(creatorID uint, req *dto.CreateMeetingRequest) (*models.Meeting, error) {
+	meeting := &models.Meeting{
+		CreatorID: creatorID,
+		TimeZone:  "UTC",
+		Status:    models.MeetingStatusScheduled,
+	}
+
+	if req != nil {
+		meeting.Title = strings.TrimSpace(req.Title)
+		meeting.Description = strings.TrimSpace(req.Description)
+		meeting.StartTime = req.StartTime
+		meeting.EndTime = req.EndTime
+		meeting.RecurrenceRule = strings.TrimSpace(req.RecurrenceRule)
+		if req.TimeZone != "" {
+			meeting.TimeZone = req.TimeZone
+		}
+		if err := validateSchedule(meeting); err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate unique meeting code
 	meetingCode, err := s.generateMeetingCode()
 	if err != nil {
 		return nil, err
 	}
-
-	meeting := &models.Meeting{
-		CreatorID:   creatorID,
-		MeetingCode: meetingCode,
-	}
+	meeting.MeetingCode = meetingCode
 
 	if err := s.repo.Create(meeting); err != nil {
 		return nil, err
@@ -105,6 +167,233 @@ func (s *MeetingService) GetMeetingsByCreatorID(creatorID uint) ([]models.Meetin
 	return s.repo.FindByCreatorID(creatorID)
 }
 
+// GetMyMeetings returns the meetings created by creatorID, optionally filtered
+// to upcoming or past ones. An empty filter returns every meeting.
+// A recurring meeting is upcoming while it has occurrences left; upcoming
+// recurring meetings carry their next occurrence.
+func (s *MeetingService) GetMyMeetings(creatorID uint, filter string) ([]models.Meeting, error) {
+	now := time.Now()
+
+	var meetings []models.Meeting
+	var err error
+	switch filter {
+	case "":
+		return s.repo.FindByCreatorID(creatorID)
+	case MeetingFilterUpcoming:
+		meetings, err = s.repo.FindUpcomingByCreatorID(creatorID, now)
+	case MeetingFilterPast:
+		meetings, err = s.repo.FindPastByCreatorID(creatorID, now)
+	default:
+		return nil, errors.New("invalid filter: must be upcoming or past")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	recurring, err := s.repo.FindRecurringByCreatorID(creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range recurring {
+		next := s.NextOccurrence(&recurring[i], now)
+		if (next != nil) == (filter == MeetingFilterUpcoming) {
+			recurring[i].NextOccurrence = next
+			meetings = append(meetings, recurring[i])
+		}
+	}
+
+	if filter == MeetingFilterUpcoming {
+		sort.SliceStable(meetings, func(i, j int) bool {
+			a, b := effectiveStart(&meetings[i]), effectiveStart(&meetings[j])
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return a.Before(*b)
+		})
+	}
+
+	return meetings, nil
+}
+
+// effectiveStart returns when a meeting next takes place, or nil for instant meetings
+func effectiveStart(meeting *models.Meeting) *time.Time {
+	if meeting.NextOccurrence != nil {
+		return &meeting.NextOccurrence.StartTime
+	}
+	return meeting.StartTime
+}
+
+// UpdateMeeting changes the details and schedule of a meeting owned by userID
+func (s *MeetingService) UpdateMeeting(meetingID uint, userID uint, req *dto.UpdateMeetingRequest) (*models.Meeting, error) {
+	meeting, err := s.getOwnedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.Status == models.MeetingStatusCancelled || meeting.Status == models.MeetingStatusEnded {
+		return nil, errors.New("invalid update: the meeting is " + string(meeting.Status))
+	}
+
+	if req.Title != nil {
+		meeting.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		meeting.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.StartTime != nil {
+		meeting.StartTime = req.StartTime
+	}
+	if req.EndTime != nil {
+		meeting.EndTime = req.EndTime
+	}
+	if req.TimeZone != nil {
+		meeting.TimeZone = *req.TimeZone
+	}
+	if req.RecurrenceRule != nil {
+		meeting.RecurrenceRule = strings.TrimSpace(*req.RecurrenceRule)
+	}
+
+	if err := validateSchedule(meeting); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(meeting); err != nil {
+		return nil, err
+	}
+
+	return meeting, nil
+}
+
+// CancelMeeting cancels a meeting owned by userID that has not started yet
+func (s *MeetingService) CancelMeeting(meetingID uint, userID uint) (*models.Meeting, error) {
+	meeting, err := s.getOwnedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateStatus(meeting.ID, []models.MeetingStatus{models.MeetingStatusScheduled}, models.MeetingStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, errors.New("invalid cancellation: the meeting is " + string(meeting.Status))
+	}
+
+	meeting.Status = models.MeetingStatusCancelled
+	return meeting, nil
+}
+
+// MarkLive records that a meeting is in progress. A meeting that ended can go live again.
+func (s *MeetingService) MarkLive(meeting *models.Meeting) {
+	if meeting.Status == models.MeetingStatusLive {
+		return
+	}
+	if _, err := s.repo.UpdateStatus(meeting.ID, []models.MeetingStatus{models.MeetingStatusScheduled, models.MeetingStatusEnded}, models.MeetingStatusLive); err != nil {
+		fmt.Printf("MeetingService: Failed to mark meeting %d live: %v\n", meeting.ID, err)
+	}
+}
+
+// MarkEnded records that a meeting is over. A recurring meeting goes back to
+// scheduled since its next occurrences are still to come.
+func (s *MeetingService) MarkEnded(meeting *models.Meeting) {
+	if meeting.IsRecurring() {
+		if _, err := s.repo.UpdateStatus(meeting.ID, []models.MeetingStatus{models.MeetingStatusLive}, models.MeetingStatusScheduled); err != nil {
+			fmt.Printf("MeetingService: Failed to mark meeting %d scheduled: %v\n", meeting.ID, err)
+		}
+		return
+	}
+	if _, err := s.repo.UpdateStatus(meeting.ID, []models.MeetingStatus{models.MeetingStatusScheduled, models.MeetingStatusLive}, models.MeetingStatusEnded); err != nil {
+		fmt.Printf("MeetingService: Failed to mark meeting %d ended: %v\n", meeting.ID, err)
+	}
+}
+
+// CheckJoinWindow returns an error when userID may not join the meeting right now.
+// Cancelled meetings can never be joined. When the join window is enforced,
+// participants of a scheduled meeting are refused too early before its start
+// or too late after its end; hosts may always join.
+func (s *MeetingService) CheckJoinWindow(meeting *models.Meeting, userID uint) error {
+	if meeting.Status == models.MeetingStatusCancelled {
+		return errors.New("meeting has been cancelled")
+	}
+
+	window := s.cfg.Scheduling
+	if !window.EnforceJoinWindow || !meeting.IsScheduled() || (userID > 0 && s.IsHost(meeting, userID)) {
+		return nil
+	}
+
+	// Recurring meetings are checked against the occurrence closest to now
+	now := time.Now()
+	occurrence := s.ClosestOccurrence(meeting, now)
+	if occurrence == nil {
+		return errors.New("meeting has ended")
+	}
+
+	opensAt := occurrence.StartTime.Add(-time.Duration(window.JoinEarlyMinutes) * time.Minute)
+	if now.Before(opensAt) {
+		return fmt.Errorf("meeting has not started yet: joining opens at %s", opensAt.UTC().Format(time.RFC3339))
+	}
+
+	if occurrence.EndTime != nil && now.After(occurrence.EndTime.Add(time.Duration(window.JoinLateMinutes)*time.Minute)) {
+		return errors.New("meeting has ended")
+	}
+
+	return nil
+}
+
+// validateSchedule checks the details and schedule of a meeting
+func validateSchedule(meeting *models.Meeting) error {
+	if len(meeting.Title) > 255 {
+		return errors.New("invalid title: must be at most 255 characters")
+	}
+
+	location, err := time.LoadLocation(meeting.TimeZone)
+	if err != nil || meeting.TimeZone == "" || meeting.TimeZone == "Local" {
+		return errors.New("invalid time zone: " + meeting.TimeZone)
+	}
+
+	if meeting.EndTime != nil {
+		if meeting.StartTime == nil {
+			return errors.New("invalid schedule: end_time requires start_time")
+		}
+		if !meeting.EndTime.After(*meeting.StartTime) {
+			return errors.New("invalid schedule: end_time must be after start_time")
+		}
+	}
+
+	if meeting.RecurrenceRule != "" {
+		if meeting.StartTime == nil {
+			return errors.New("invalid recurrence rule: recurring meetings require start_time")
+		}
+		rule, err := rrule.ParseInLocation(meeting.RecurrenceRule, location)
+		if err != nil {
+			return errors.New("invalid recurrence rule: " + err.Error())
+		}
+		if rule.Count > maxRecurrenceCount {
+			return fmt.Errorf("invalid recurrence rule: COUNT must be at most %d", maxRecurrenceCount)
+		}
+		meeting.RecurrenceRule = rule.String()
+
+		// e.g. a rule whose UNTIL is before the start, or weekdays BYMONTHDAY never falls on
+		dtstart := meeting.StartTime.In(location)
+		if _, ok := rule.After(dtstart, dtstart); !ok {
+			return errors.New("invalid recurrence rule: it never occurs from start_time")
+		}
+	}
+
+	// Store planned times in UTC
+	if meeting.StartTime != nil {
+		start := meeting.StartTime.UTC()
+		meeting.StartTime = &start
+	}
+	if meeting.EndTime != nil {
+		end := meeting.EndTime.UTC()
+		meeting.EndTime = &end
+	}
+
+	return nil
+}
+
 func (s *MeetingService) GetAllMeetings() ([]models.Meeting, error) {
 	return s.repo.FindAll()
 }
@@ -129,3 +418,555 @@ func (s *MeetingService) DeleteMeeting(id uint, userID uint) error {
 func (s *MeetingService) DeleteByCreatorID(creatorID uint) error {
 	return s.repo.DeleteByCreatorID(creatorID)
 }
+
+// getOwnedMeeting loads a meeting and verifies that userID is its creator
+func (s *MeetingService) getOwnedMeeting(meetingID uint, userID uint) (*models.Meeting, error) {
+	meeting, err := s.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.CreatorID != userID {
+		return nil, errors.New("unauthorized: only meeting creator can manage this meeting")
+	}
+
+	return meeting, nil
+}
+
+// getHostedMeeting loads a meeting and verifies that userID is one of its hosts
+func (s *MeetingService) getHostedMeeting(meetingID uint, userID uint, action string) (*models.Meeting, error) {
+	meeting, err := s.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can " + action)
+	}
+
+	return meeting, nil
+}
+
+// IsCohost reports whether userID was made a co-host of the meeting
+func (s *MeetingService) IsCohost(meetingID uint, userID uint) bool {
+	if userID == 0 {
+		return false
+	}
+	cohost, err := s.cohostRepo.ExistsByMeetingAndUser(meetingID, userID)
+	return err == nil && cohost
+}
+
+// IsHost reports whether userID may run the meeting: its creator or one of its co-hosts
+func (s *MeetingService) IsHost(meeting *models.Meeting, userID uint) bool {
+	return meeting.CreatorID == userID || s.IsCohost(meeting.ID, userID)
+}
+
+// ParticipantRole returns the role carried in the LiveKit metadata of a
+// participant: "admin" for the creator, "cohost", the role a host gave them
+// during the meeting, the role set on their invitation, the meeting's default
+// role, or "user" ("guest" when user is nil)
+func (s *MeetingService) ParticipantRole(meeting *models.Meeting, user *models.User) string {
+	if user != nil {
+		if meeting.CreatorID == user.ID {
+			return string(models.RoleHost)
+		}
+		if s.IsCohost(meeting.ID, user.ID) {
+			return string(models.RoleCohost)
+		}
+		if assignment, err := s.roleRepo.FindByMeetingAndUser(meeting.ID, user.ID); err == nil {
+			return string(assignment.Role)
+		}
+		if invitee, err := s.inviteeRepo.FindByMeetingAndEmail(meeting.ID, user.Email); err == nil && invitee.Role != "" {
+			return string(invitee.Role)
+		}
+	}
+
+	if settings, err := s.GetSettings(meeting.ID); err == nil && settings.DefaultRole != "" {
+		return string(settings.DefaultRole)
+	}
+
+	if user == nil {
+		return string(models.RoleGuest)
+	}
+	return string(models.RoleParticipant)
+}
+
+// AssignRole keeps the role a host gave userID during the meeting, so that they
+// join with it again after leaving
+func (s *MeetingService) AssignRole(meetingID uint, userID uint, role models.ParticipantRole) error {
+	if !role.IsAssignable() {
+		return errors.New("invalid role: must be presenter, user, guest, attendee or viewer")
+	}
+	return s.roleRepo.Save(&models.MeetingRoleAssignment{
+		MeetingID: meetingID,
+		UserID:    userID,
+		Role:      role,
+	})
+}
+
+// SetInviteeRole sets the role an invitee of a meeting owned by userID joins with
+func (s *MeetingService) SetInviteeRole(meetingID uint, userID uint, inviteeID uint, role models.ParticipantRole) error {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return err
+	}
+
+	if role != "" && !role.IsAssignable() {
+		return errors.New("invalid role: must be presenter, user, guest, attendee or viewer")
+	}
+
+	updated, err := s.inviteeRepo.UpdateRole(meetingID, inviteeID, role)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errors.New("invitee not found")
+	}
+	return nil
+}
+
+// GetSettings returns the settings of a meeting, falling back to defaults when none were saved
+func (s *MeetingService) GetSettings(meetingID uint) (*models.MeetingSettings, error) {
+	settings, err := s.settingsRepo.FindByMeetingID(meetingID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.MeetingSettings{
+				MeetingID:         meetingID,
+				AdmissionPolicy:   models.AdmissionManual,
+				LobbyRequired:     models.LobbyRequiredNone,
+				AllowGuests:       true,
+				SummarizerAllowed: true,
+			}, nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetSettingsForOwner returns the settings of a meeting owned by userID
+func (s *MeetingService) GetSettingsForOwner(meetingID uint, userID uint) (*models.MeetingSettings, error) {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+	return s.GetSettings(meetingID)
+}
+
+// UpdateSettings applies a partial update to the settings of a meeting owned by userID
+func (s *MeetingService) UpdateSettings(meetingID uint, userID uint, req *dto.UpdateMeetingSettingsRequest) (*models.MeetingSettings, error) {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetSettings(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AdmissionPolicy != nil {
+		policy := models.AdmissionPolicy(*req.AdmissionPolicy)
+		if !policy.IsValid() {
+			return nil, errors.New("invalid admission policy")
+		}
+		settings.AdmissionPolicy = policy
+	}
+
+	if req.AllowedDomains != nil {
+		settings.SetDomains(*req.AllowedDomains)
+	}
+
+	if req.LobbyTimeoutSeconds != nil {
+		// 0 restores the server default, otherwise between 30 seconds and 24 hours
+		if *req.LobbyTimeoutSeconds != 0 && (*req.LobbyTimeoutSeconds < 30 || *req.LobbyTimeoutSeconds > 24*60*60) {
+			return nil, errors.New("invalid lobby timeout: must be 0 or between 30 and 86400 seconds")
+		}
+		settings.LobbyTimeoutSeconds = *req.LobbyTimeoutSeconds
+	}
+
+	if req.AutoSummarize != nil {
+		settings.AutoSummarize = *req.AutoSummarize
+	}
+
+	if req.ConsentMode != nil {
+		settings.ConsentMode = *req.ConsentMode
+	}
+
+	if req.LobbyRequired != nil {
+		requirement := models.LobbyRequirement(*req.LobbyRequired)
+		if !requirement.IsValid() {
+			return nil, errors.New("invalid lobby requirement")
+		}
+		settings.LobbyRequired = requirement
+	}
+
+	if req.Passcode != nil {
+		passcode := strings.TrimSpace(*req.Passcode)
+		if passcode == "" {
+			settings.Passcode = ""
+		} else {
+			if len(passcode) < 4 || len(passcode) > 64 {
+				return nil, errors.New("invalid passcode: must be between 4 and 64 characters")
+			}
+			hash, err := bcrypt.GenerateFromPassword([]byte(passcode), bcrypt.DefaultCost)
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash passcode: %w", err)
+			}
+			settings.Passcode = string(hash)
+		}
+	}
+
+	if req.AllowGuests != nil {
+		settings.AllowGuests = *req.AllowGuests
+	}
+
+	if req.MaxParticipants != nil {
+		if *req.MaxParticipants < 0 || *req.MaxParticipants > 1000 {
+			return nil, errors.New("invalid max participants: must be between 0 and 1000")
+		}
+		settings.MaxParticipants = *req.MaxParticipants
+	}
+
+	if req.Locked != nil {
+		settings.Locked = *req.Locked
+	}
+
+	if req.SummarizerAllowed != nil {
+		settings.SummarizerAllowed = *req.SummarizerAllowed
+	}
+
+	if req.DefaultRole != nil {
+		role := models.ParticipantRole(*req.DefaultRole)
+		switch role {
+		case "", models.RolePresenter, models.RoleAttendee, models.RoleViewer:
+			settings.DefaultRole = role
+		default:
+			return nil, errors.New("invalid default role: must be presenter, attendee or viewer")
+		}
+	}
+
+	if settings.AdmissionPolicy == models.AdmissionDomain && len(settings.Domains()) == 0 {
+		return nil, errors.New("allowed_domains is required for the domain admission policy")
+	}
+
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// CanAutoAdmit evaluates the meeting's admission policy for a participant.
+// user is nil for guests. The meeting creator and co-hosts are always admitted.
+func (s *MeetingService) CanAutoAdmit(meeting *models.Meeting, user *models.User) bool {
+	if user != nil && s.IsHost(meeting, user.ID) {
+		return true
+	}
+
+	settings, err := s.GetSettings(meeting.ID)
+	if err != nil {
+		return false
+	}
+
+	return autoAdmits(settings, user, func() bool { return s.isInvited(meeting, user) })
+}
+
+// autoAdmits applies the admission policy of settings to a participant who is
+// not a host. invited is only called for the policies that admit invitees.
+func autoAdmits(settings *models.MeetingSettings, user *models.User, invited func() bool) bool {
+	switch settings.AdmissionPolicy {
+	case models.AdmissionEveryone:
+		return true
+	case models.AdmissionAuthenticated:
+		return user != nil
+	case models.AdmissionDomain:
+		return user != nil && (settings.AllowsDomain(user.Email) || invited())
+	case models.AdmissionInvited:
+		return user != nil && invited()
+	}
+
+	return false
+}
+
+// CheckAccess enforces the access settings of a meeting on someone asking to
+// join it. user is nil for guests. Hosts are never refused.
+func (s *MeetingService) CheckAccess(meeting *models.Meeting, user *models.User, passcode string) error {
+	if user != nil && s.IsHost(meeting, user.ID) {
+		return nil
+	}
+
+	settings, err := s.GetSettings(meeting.ID)
+	if err != nil {
+		return err
+	}
+	return checkAccess(settings, user, passcode)
+}
+
+// checkAccess applies the access settings to someone who is not a host
+func checkAccess(settings *models.MeetingSettings, user *models.User, passcode string) error {
+	if err := checkAdmission(settings, user); err != nil {
+		return err
+	}
+	if settings.HasPasscode() && bcrypt.CompareHashAndPassword([]byte(settings.Passcode), []byte(passcode)) != nil {
+		return errors.New("invalid passcode")
+	}
+
+	return nil
+}
+
+// CheckAdmission enforces the access settings of a meeting on a visitor a host
+// admits from the lobby: the meeting may have been locked or closed to guests
+// since they knocked. Their passcode was checked when they knocked.
+func (s *MeetingService) CheckAdmission(meeting *models.Meeting, user *models.User) error {
+	if user != nil && s.IsHost(meeting, user.ID) {
+		return nil
+	}
+
+	settings, err := s.GetSettings(meeting.ID)
+	if err != nil {
+		return err
+	}
+	return checkAdmission(settings, user)
+}
+
+func checkAdmission(settings *models.MeetingSettings, user *models.User) error {
+	if settings.Locked {
+		return errors.New("meeting is locked")
+	}
+	if user == nil && !settings.AllowGuests {
+		return errors.New("guests are not allowed in this meeting")
+	}
+	return nil
+}
+
+// RequiresAdmissionTicket reports whether a participant must present a ticket
+// from the lobby to get a LiveKit token directly. user is nil for guests.
+func (s *MeetingService) RequiresAdmissionTicket(meeting *models.Meeting, user *models.User) bool {
+	if user != nil && s.IsHost(meeting, user.ID) {
+		return false
+	}
+
+	settings, err := s.GetSettings(meeting.ID)
+	if err != nil {
+		return true
+	}
+
+	return requiresAdmissionTicket(settings, user, func() bool { return s.isInvited(meeting, user) })
+}
+
+// requiresAdmissionTicket applies the lobby requirement of settings to a
+// participant who is not a host. invited is only called for signed-in users.
+func requiresAdmissionTicket(settings *models.MeetingSettings, user *models.User, invited func() bool) bool {
+	if autoAdmits(settings, user, invited) {
+		return false
+	}
+
+	switch settings.LobbyRequired {
+	case models.LobbyRequiredGuests:
+		return user == nil
+	case models.LobbyRequiredUninvited:
+		return user == nil || !invited()
+	}
+
+	return false
+}
+
+// isInvited reports whether the user's email is on the invitee list of the meeting.
+// Invitees who declined are no longer treated as invited.
+func (s *MeetingService) isInvited(meeting *models.Meeting, user *models.User) bool {
+	invited, err := s.inviteeRepo.ExistsAttendingByMeetingAndEmail(meeting.ID, user.Email)
+	return err == nil && invited
+}
+
+// GetInvitees returns the invitee list of a meeting owned by userID
+func (s *MeetingService) GetInvitees(meetingID uint, userID uint) ([]models.MeetingInvitee, error) {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+	return s.inviteeRepo.FindByMeetingID(meetingID)
+}
+
+// AddInvitees adds email addresses to the invitee list of a meeting owned by userID
+func (s *MeetingService) AddInvitees(meetingID uint, userID uint, emails []string) ([]models.MeetingInvitee, error) {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	invitees := make([]models.MeetingInvitee, 0, len(emails))
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, errors.New("invalid email address: " + email)
+		}
+		invitees = append(invitees, models.MeetingInvitee{
+			MeetingID: meetingID,
+			Email:     email,
+		})
+	}
+
+	if err := s.inviteeRepo.CreateMany(invitees); err != nil {
+		return nil, err
+	}
+
+	return s.inviteeRepo.FindByMeetingID(meetingID)
+}
+
+// RemoveInvitee removes an invitee from a meeting owned by userID
+func (s *MeetingService) RemoveInvitee(meetingID uint, userID uint, inviteeID uint) error {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.inviteeRepo.Delete(meetingID, inviteeID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("invitee not found")
+	}
+	return nil
+}
+
+// IsBanned reports whether a participant matches any ban of the meeting.
+// Lookup failures are treated as banned, so that a blocked participant cannot get
+// back in while the database is unavailable.
+// Guests are matched on values their client supplies, so guest bans are best-effort (see MeetingBan).
+func (s *MeetingService) IsBanned(meetingID uint, userID uint, email, fingerprint, identity string) bool {
+	banned, err := s.banRepo.ExistsMatching(meetingID, userID, email, fingerprint, identity)
+	if err != nil {
+		fmt.Printf("MeetingService: Failed to look up bans of meeting %d: %v\n", meetingID, err)
+		return true
+	}
+	return banned
+}
+
+// CheckParticipant verifies that the participant a join token was issued to may
+// still act in the meeting of room, a meeting room or one of its breakout rooms,
+// and returns the meeting. Join tokens stay valid for a day, so a participant
+// who was removed or blocked since is refused here.
+func (s *MeetingService) CheckParticipant(room string, identity string) (*models.Meeting, error) {
+	meetingCode := room
+	if code, _, ok := ParseBreakoutRoomName(room); ok {
+		meetingCode = code
+	}
+
+	meeting, err := s.GetMeetingByCode(meetingCode)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := cache.GetParticipant(meeting.MeetingCode, identity)
+	if err != nil {
+		return nil, errors.New("unauthorized: you are no longer in this meeting")
+	}
+	if s.IsBanned(meeting.ID, p.UserID, p.Email, p.Fingerprint, p.Identity) {
+		return nil, errors.New("unauthorized: you have been blocked from this meeting")
+	}
+
+	return meeting, nil
+}
+
+// BanParticipant adds a ban to a meeting hosted by userID
+func (s *MeetingService) BanParticipant(meeting *models.Meeting, userID uint, ban *models.MeetingBan) error {
+	if !s.IsHost(meeting, userID) {
+		return errors.New("unauthorized: only meeting hosts can block participants")
+	}
+
+	if ban.UserID != nil && *ban.UserID == meeting.CreatorID {
+		return errors.New("invalid ban: the meeting creator cannot be blocked")
+	}
+
+	if ban.UserID == nil && ban.Email == "" && ban.Fingerprint == "" && ban.Identity == "" {
+		return errors.New("invalid ban: no participant attributes to block")
+	}
+
+	ban.MeetingID = meeting.ID
+	ban.BannedBy = userID
+	ban.Email = strings.ToLower(ban.Email)
+
+	return s.banRepo.Create(ban)
+}
+
+// GetBans returns the ban list of a meeting hosted by userID
+func (s *MeetingService) GetBans(meetingID uint, userID uint) ([]models.MeetingBan, error) {
+	if _, err := s.getHostedMeeting(meetingID, userID, "view blocked participants"); err != nil {
+		return nil, err
+	}
+	return s.banRepo.FindByMeetingID(meetingID)
+}
+
+// LiftBan removes a ban from a meeting hosted by userID
+func (s *MeetingService) LiftBan(meetingID uint, userID uint, banID uint) error {
+	if _, err := s.getHostedMeeting(meetingID, userID, "unblock participants"); err != nil {
+		return err
+	}
+
+	deleted, err := s.banRepo.Delete(meetingID, banID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("ban not found")
+	}
+	return nil
+}
+
+// GetCohosts returns the co-hosts of a meeting owned by userID
+func (s *MeetingService) GetCohosts(meetingID uint, userID uint) ([]models.MeetingCohost, error) {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+	return s.cohostRepo.FindByMeetingID(meetingID)
+}
+
+// AddCohost makes the registered user with the given email a co-host of a meeting owned by userID
+func (s *MeetingService) AddCohost(meetingID uint, userID uint, email string) (*models.MeetingCohost, error) {
+	meeting, err := s.getOwnedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	return s.addCohost(meeting, user.ID)
+}
+
+// PromoteToCohost makes cohostUserID a co-host of a meeting owned by userID
+func (s *MeetingService) PromoteToCohost(meeting *models.Meeting, userID uint, cohostUserID uint) (*models.MeetingCohost, error) {
+	if meeting.CreatorID != userID {
+		return nil, errors.New("unauthorized: only meeting creator can manage this meeting")
+	}
+	return s.addCohost(meeting, cohostUserID)
+}
+
+func (s *MeetingService) addCohost(meeting *models.Meeting, cohostUserID uint) (*models.MeetingCohost, error) {
+	if cohostUserID == meeting.CreatorID {
+		return nil, errors.New("invalid co-host: the meeting creator is already a host")
+	}
+
+	cohost := &models.MeetingCohost{
+		MeetingID: meeting.ID,
+		UserID:    cohostUserID,
+	}
+	if err := s.cohostRepo.Create(cohost); err != nil {
+		return nil, err
+	}
+
+	return cohost, nil
+}
+
+// RemoveCohost revokes the co-host rights of cohostUserID on a meeting owned by userID
+func (s *MeetingService) RemoveCohost(meetingID uint, userID uint, cohostUserID uint) error {
+	if _, err := s.getOwnedMeeting(meetingID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.cohostRepo.Delete(meetingID, cohostUserID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("co-host not found")
+	}
+	return nil
+}
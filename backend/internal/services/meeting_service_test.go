@@ -0,0 +1,204 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestValidateScheduleRecurrence(t *testing.T) {
+	start := time.Date(2026, time.March, 2, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		timeZone string
+		rule     string
+		want     string // rule as stored, or the error it is refused with
+		err      bool
+	}{
+		{"daily", "UTC", "FREQ=DAILY;COUNT=10", "FREQ=DAILY;COUNT=10", false},
+		{"largest COUNT", "UTC", "FREQ=DAILY;COUNT=1000", "FREQ=DAILY;COUNT=1000", false},
+		{"COUNT too large", "UTC", "FREQ=DAILY;COUNT=1001", "COUNT must be at most 1000", true},
+		{"COUNT far too large", "UTC", "FREQ=DAILY;COUNT=100000", "COUNT must be at most 1000", true},
+		{"floating UNTIL in the meeting's zone", "Europe/Paris", "FREQ=DAILY;UNTIL=20260310T150000", "FREQ=DAILY;UNTIL=20260310T140000Z", false},
+		{"UTC UNTIL", "Europe/Paris", "FREQ=DAILY;UNTIL=20260310T150000Z", "FREQ=DAILY;UNTIL=20260310T150000Z", false},
+		{"UNTIL before start", "UTC", "FREQ=DAILY;UNTIL=20260301T000000Z", "never occurs", true},
+		{"malformed", "UTC", "FREQ=DAILY;COUNT", "invalid recurrence rule", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meetingStart := start
+			meeting := &models.Meeting{StartTime: &meetingStart, TimeZone: tt.timeZone, RecurrenceRule: tt.rule}
+
+			err := validateSchedule(meeting)
+			if tt.err {
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Fatalf("validateSchedule(%q): got error %v, want one containing %q", tt.rule, err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateSchedule(%q): %v", tt.rule, err)
+			}
+			if meeting.RecurrenceRule != tt.want {
+				t.Errorf("stored rule %q, want %q", meeting.RecurrenceRule, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoAdmits(t *testing.T) {
+	member := &models.User{Email: "alice@acme.com"}
+	outsider := &models.User{Email: "bob@example.com"}
+
+	tests := []struct {
+		name    string
+		policy  models.AdmissionPolicy
+		user    *models.User
+		invited bool
+		want    bool
+	}{
+		{"manual admits no one", models.AdmissionManual, member, true, false},
+		{"manual guest", models.AdmissionManual, nil, false, false},
+		{"everyone admits guests", models.AdmissionEveryone, nil, false, true},
+		{"everyone admits users", models.AdmissionEveryone, outsider, false, true},
+		{"authenticated admits users", models.AdmissionAuthenticated, outsider, false, true},
+		{"authenticated refuses guests", models.AdmissionAuthenticated, nil, false, false},
+		{"domain admits members", models.AdmissionDomain, member, false, true},
+		{"domain admits invitees", models.AdmissionDomain, outsider, true, true},
+		{"domain refuses outsiders", models.AdmissionDomain, outsider, false, false},
+		{"domain refuses guests", models.AdmissionDomain, nil, true, false},
+		{"invited admits invitees", models.AdmissionInvited, outsider, true, true},
+		{"invited refuses members who are not invited", models.AdmissionInvited, member, false, false},
+		{"invited refuses guests", models.AdmissionInvited, nil, true, false},
+		{"unknown policy", models.AdmissionPolicy("bogus"), member, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &models.MeetingSettings{AdmissionPolicy: tt.policy}
+			settings.SetDomains([]string{"acme.com"})
+
+			got := autoAdmits(settings, tt.user, func() bool {
+				if tt.user == nil {
+					t.Fatal("invitee list looked up for a guest")
+				}
+				return tt.invited
+			})
+			if got != tt.want {
+				t.Errorf("autoAdmits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A participant is refused when the ban list cannot be read
+func TestIsBannedFailsClosed(t *testing.T) {
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1"), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	service := &MeetingService{banRepo: repositories.NewMeetingBanRepository(db)}
+
+	if !service.IsBanned(1, 2, "alice@acme.com", "device", "Alice_2") {
+		t.Error("participant admitted although the ban lookup failed")
+	}
+}
+
+func TestRequiresAdmissionTicket(t *testing.T) {
+	user := &models.User{Email: "bob@example.com"}
+
+	tests := []struct {
+		name     string
+		policy   models.AdmissionPolicy
+		required models.LobbyRequirement
+		user     *models.User
+		invited  bool
+		want     bool
+	}{
+		{"lobby not required for guests", models.AdmissionManual, models.LobbyRequiredNone, nil, false, false},
+		{"lobby not required for users", models.AdmissionManual, models.LobbyRequiredNone, user, false, false},
+		{"guests required, guest", models.AdmissionManual, models.LobbyRequiredGuests, nil, false, true},
+		{"guests required, user", models.AdmissionManual, models.LobbyRequiredGuests, user, false, false},
+		{"uninvited required, guest", models.AdmissionManual, models.LobbyRequiredUninvited, nil, false, true},
+		{"uninvited required, user not invited", models.AdmissionManual, models.LobbyRequiredUninvited, user, false, true},
+		{"uninvited required, invitee", models.AdmissionManual, models.LobbyRequiredUninvited, user, true, false},
+		{"auto-admitted guest", models.AdmissionEveryone, models.LobbyRequiredGuests, nil, false, false},
+		{"auto-admitted user not invited", models.AdmissionAuthenticated, models.LobbyRequiredUninvited, user, false, false},
+		{"guest not auto-admitted", models.AdmissionAuthenticated, models.LobbyRequiredUninvited, nil, false, true},
+		{"invitee auto-admitted", models.AdmissionInvited, models.LobbyRequiredUninvited, user, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &models.MeetingSettings{AdmissionPolicy: tt.policy, LobbyRequired: tt.required}
+
+			got := requiresAdmissionTicket(settings, tt.user, func() bool {
+				if tt.user == nil {
+					t.Fatal("invitee list looked up for a guest")
+				}
+				return tt.invited
+			})
+			if got != tt.want {
+				t.Errorf("requiresAdmissionTicket = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAccess(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	user := &models.User{Email: "bob@example.com"}
+
+	tests := []struct {
+		name     string
+		settings models.MeetingSettings
+		user     *models.User
+		passcode string
+		err      string
+	}{
+		{"open meeting, guest", models.MeetingSettings{AllowGuests: true}, nil, "", ""},
+		{"open meeting, user", models.MeetingSettings{AllowGuests: true}, user, "", ""},
+		{"guests not allowed, guest", models.MeetingSettings{}, nil, "", "guests are not allowed"},
+		{"guests not allowed, user", models.MeetingSettings{}, user, "", ""},
+		{"locked, user", models.MeetingSettings{AllowGuests: true, Locked: true}, user, "", "meeting is locked"},
+		{"locked, guest", models.MeetingSettings{AllowGuests: true, Locked: true}, nil, "", "meeting is locked"},
+		{"locked with the right passcode", models.MeetingSettings{AllowGuests: true, Locked: true, Passcode: string(hash)}, user, "123456", "meeting is locked"},
+		{"right passcode", models.MeetingSettings{AllowGuests: true, Passcode: string(hash)}, nil, "123456", ""},
+		{"wrong passcode", models.MeetingSettings{AllowGuests: true, Passcode: string(hash)}, user, "654321", "invalid passcode"},
+		{"missing passcode", models.MeetingSettings{AllowGuests: true, Passcode: string(hash)}, nil, "", "invalid passcode"},
+		{"hash given as passcode", models.MeetingSettings{AllowGuests: true, Passcode: string(hash)}, user, string(hash), "invalid passcode"},
+		{"passcode without one set", models.MeetingSettings{AllowGuests: true}, user, "123456", ""},
+		{"guest refused before passcode", models.MeetingSettings{Passcode: string(hash)}, nil, "123456", "guests are not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAccess(&tt.settings, tt.user, tt.passcode)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("checkAccess: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("checkAccess: got error %v, want one containing %q", err, tt.err)
+			}
+		})
+	}
+}
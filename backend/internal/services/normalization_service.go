@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"mini-meeting/internal/models"
 	"mini-meeting/internal/repositories"
+	"sort"
 	"strings"
 	"time"
 )
@@ -11,13 +12,23 @@ import (
 type NormalizationService struct {
 	sessionRepo          *repositories.SummarizerSessionRepository
 	transcriptRepo       *repositories.TranscriptRepository
+	chatRepo             *repositories.ChatMessageRepository
+	consentRepo          *repositories.SummarizerConsentRepository
 	summarizationService *SummarizationService
 }
 
-func NewNormalizationService(sessionRepo *repositories.SummarizerSessionRepository, transcriptRepo *repositories.TranscriptRepository, summarizationService *SummarizationService) *NormalizationService {
+func NewNormalizationService(
+	sessionRepo *repositories.SummarizerSessionRepository,
+	transcriptRepo *repositories.TranscriptRepository,
+	chatRepo *repositories.ChatMessageRepository,
+	consentRepo *repositories.SummarizerConsentRepository,
+	summarizationService *SummarizationService,
+) *NormalizationService {
 	return &NormalizationService{
 		sessionRepo:          sessionRepo,
 		transcriptRepo:       transcriptRepo,
+		chatRepo:             chatRepo,
+		consentRepo:          consentRepo,
 		summarizationService: summarizationService,
 	}
 }
@@ -28,6 +39,7 @@ type MergedSegment struct {
 	Text         string
 	StartTime    float64
 	EndTime      float64
+	Chat         bool // Typed in chat rather than spoken
 }
 
 // ProcessSession normalizes all transcripts for a given session
@@ -61,8 +73,9 @@ func (s *NormalizationService) ProcessSession(sessionID uint) error {
 	// 3. Merge consecutive segments from same speaker
 	mergedSegments := s.mergeConsecutiveSpeakers(transcripts)
 
-	// 4. Format into meeting document
-	normalizedText := s.formatMeetingDocument(mergedSegments)
+	// 4. Interleave the chat typed during the session, then format into meeting document
+	chatSegments := s.chatSegments(session)
+	normalizedText := s.formatMeetingDocument(s.interleave(mergedSegments, chatSegments))
 
 	// 5. Update session status to NORMALIZED
 	now := time.Now()
@@ -82,8 +95,8 @@ func (s *NormalizationService) ProcessSession(sessionID uint) error {
 		fmt.Printf("Cleaned up %d transcript records for session %d\n", len(transcripts), sessionID)
 	}
 
-	fmt.Printf("Successfully normalized session %d (%d segments merged into %d)\n",
-		sessionID, len(transcripts), len(mergedSegments))
+	fmt.Printf("Successfully normalized session %d (%d segments merged into %d, %d chat messages)\n",
+		sessionID, len(transcripts), len(mergedSegments), len(chatSegments))
 
 	// Trigger summarization in background
 	go func() {
@@ -135,6 +148,60 @@ func (s *NormalizationService) mergeConsecutiveSpeakers(transcripts []models.Tra
 	return merged
 }
 
+// chatSegments turns the chat typed in the session's room while it was captured
+// into segments, leaving out participants who declined to be transcribed
+func (s *NormalizationService) chatSegments(session *models.SummarizerSession) []MergedSegment {
+	endedAt := time.Now()
+	if session.EndedAt != nil {
+		endedAt = *session.EndedAt
+	}
+
+	messages, err := s.chatRepo.FindByRoomBetween(session.MeetingID, session.RoomName, session.StartedAt, endedAt)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load chat for session %d: %v\n", session.ID, err)
+		return nil
+	}
+
+	declined := map[string]bool{}
+	if consents, err := s.consentRepo.FindBySessionID(session.ID); err == nil {
+		for _, consent := range consents {
+			declined[consent.Identity] = !consent.Consented
+		}
+	}
+
+	segments := make([]MergedSegment, 0, len(messages))
+	for _, message := range messages {
+		if declined[message.Identity] {
+			continue
+		}
+		offset := message.SentAt.Sub(session.StartedAt).Seconds()
+		segments = append(segments, MergedSegment{
+			UserIdentity: message.Identity,
+			Text:         strings.TrimSpace(message.Message),
+			StartTime:    offset,
+			EndTime:      offset,
+			Chat:         true,
+		})
+	}
+	return segments
+}
+
+// interleave merges chat segments into the spoken segments by start time. Spoken
+// segments keep their order; a message goes after speech starting at the same time.
+func (s *NormalizationService) interleave(spoken []MergedSegment, chat []MergedSegment) []MergedSegment {
+	if len(chat) == 0 {
+		return spoken
+	}
+
+	segments := make([]MergedSegment, 0, len(spoken)+len(chat))
+	segments = append(segments, spoken...)
+	segments = append(segments, chat...)
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].StartTime < segments[j].StartTime
+	})
+	return segments
+}
+
 // formatMeetingDocument formats merged segments into a clean meeting document
 func (s *NormalizationService) formatMeetingDocument(segments []MergedSegment) string {
 	var builder strings.Builder
@@ -143,6 +210,9 @@ func (s *NormalizationService) formatMeetingDocument(segments []MergedSegment) s
 		timestamp := s.formatTimestamp(segment.StartTime)
 
 		displayName := s.extractDisplayName(segment.UserIdentity)
+		if segment.Chat {
+			displayName += " (chat)"
+		}
 
 		builder.WriteString(fmt.Sprintf("[%s] %s: %s\n\n", timestamp, displayName, segment.Text))
 	}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mini-meeting/internal/cache"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+	"strings"
+	"time"
+)
+
+// pollTopic is the data topic poll updates are sent on. A message has a payload
+// of {"type": "poll_opened" | "poll_results" | "poll_closed", "poll": ...}.
+const pollTopic = "poll"
+
+const (
+	minPollOptions = 2
+	maxPollOptions = 10
+)
+
+// PollService runs the polls hosts ask in a live meeting. Participants, guests
+// included, vote with the LiveKit token they joined with, and every change is
+// sent to the main room and any breakout rooms of the meeting.
+type PollService struct {
+	pollRepo       *repositories.PollRepository
+	meetingService *MeetingService
+	livekitService *LiveKitService
+}
+
+func NewPollService(pollRepo *repositories.PollRepository, meetingService *MeetingService, livekitService *LiveKitService) *PollService {
+	return &PollService{
+		pollRepo:       pollRepo,
+		meetingService: meetingService,
+		livekitService: livekitService,
+	}
+}
+
+// CreatePoll creates a draft poll for a meeting (hosts only)
+func (s *PollService) CreatePoll(meetingID uint, userID uint, req *dto.CreatePollRequest) (*dto.PollResponse, error) {
+	if _, err := s.hostMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return nil, errors.New("invalid poll: question is required")
+	}
+
+	if len(req.Options) < minPollOptions || len(req.Options) > maxPollOptions {
+		return nil, fmt.Errorf("invalid poll: between %d and %d options are required", minPollOptions, maxPollOptions)
+	}
+
+	options := make([]models.PollOption, 0, len(req.Options))
+	seen := make(map[string]bool)
+	for i, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, errors.New("invalid poll: options cannot be empty")
+		}
+		if len(text) > 255 {
+			return nil, errors.New("invalid poll: options cannot be longer than 255 characters")
+		}
+		if seen[strings.ToLower(text)] {
+			return nil, errors.New("invalid poll: options must be distinct")
+		}
+		seen[strings.ToLower(text)] = true
+		options = append(options, models.PollOption{Position: i, Text: text})
+	}
+
+	poll := &models.Poll{
+		MeetingID:      meetingID,
+		CreatedBy:      userID,
+		Question:       question,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		Status:         models.PollStatusDraft,
+		Options:        options,
+	}
+	if err := s.pollRepo.Create(poll); err != nil {
+		return nil, fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	return tallyPoll(poll, nil, true), nil
+}
+
+// ListPolls returns the polls of a meeting with their results (hosts only)
+func (s *PollService) ListPolls(meetingID uint, userID uint) ([]dto.PollResponse, error) {
+	if _, err := s.hostMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	polls, err := s.pollRepo.FindByMeetingID(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch polls: %w", err)
+	}
+
+	return s.results(polls, true, "")
+}
+
+// GetResults returns a poll of a meeting with its results (hosts only)
+func (s *PollService) GetResults(meetingID uint, pollID uint, userID uint) (*dto.PollResponse, error) {
+	if _, err := s.hostMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	poll, err := s.findPoll(meetingID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.result(poll, true, "")
+}
+
+// OpenPoll starts accepting votes for a draft poll and announces it (hosts only)
+func (s *PollService) OpenPoll(meetingID uint, pollID uint, userID uint) (*dto.PollResponse, error) {
+	meeting, err := s.hostMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findPoll(meetingID, pollID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.pollRepo.UpdateStatusFrom(pollID, models.PollStatusDraft, models.PollStatusOpen, "opened_at", time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open poll: %w", err)
+	}
+	if updated == 0 {
+		return nil, errors.New("invalid poll status: only draft polls can be opened")
+	}
+
+	return s.announce(meeting, pollID, "poll_opened")
+}
+
+// ClosePoll stops accepting votes for an open poll and announces its final
+// results (hosts only)
+func (s *PollService) ClosePoll(meetingID uint, pollID uint, userID uint) (*dto.PollResponse, error) {
+	meeting, err := s.hostMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findPoll(meetingID, pollID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.pollRepo.UpdateStatusFrom(pollID, models.PollStatusOpen, models.PollStatusClosed, "closed_at", time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to close poll: %w", err)
+	}
+	if updated == 0 {
+		return nil, errors.New("invalid poll status: only open polls can be closed")
+	}
+
+	return s.announce(meeting, pollID, "poll_closed")
+}
+
+// Vote records the choice of the participant a join token was issued to,
+// replacing their earlier vote, and sends the updated results to the meeting
+func (s *PollService) Vote(pollID uint, token string, optionIDs []uint) (*dto.PollResponse, error) {
+	claims, err := s.livekitService.VerifyJoinToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	meeting, err := s.meetingService.CheckParticipant(claims.Video.Room, claims.Identity)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := s.findPoll(meeting.ID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.Status != models.PollStatusOpen {
+		return nil, errors.New("invalid vote: the poll is not open")
+	}
+
+	chosen := make(map[uint]bool)
+	for _, optionID := range optionIDs {
+		chosen[optionID] = true
+	}
+	if len(chosen) == 0 {
+		return nil, errors.New("invalid vote: choose at least one option")
+	}
+	if !poll.MultipleChoice && len(chosen) > 1 {
+		return nil, errors.New("invalid vote: this poll accepts a single option")
+	}
+
+	var userID *uint
+	if p, err := cache.GetParticipant(meeting.MeetingCode, claims.Identity); err == nil && p.UserID > 0 {
+		participantUserID := p.UserID
+		userID = &participantUserID
+	}
+
+	votes := make([]models.PollVote, 0, len(chosen))
+	for _, option := range poll.Options {
+		if !chosen[option.ID] {
+			continue
+		}
+		votes = append(votes, models.PollVote{
+			PollID:   poll.ID,
+			OptionID: option.ID,
+			Identity: claims.Identity,
+			Name:     participantName(claims),
+			UserID:   userID,
+		})
+	}
+	if len(votes) != len(chosen) {
+		return nil, errors.New("invalid vote: unknown option")
+	}
+
+	// The poll may have been closed since it was loaded
+	open, err := s.pollRepo.ReplaceVotes(poll.ID, claims.Identity, votes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record vote: %w", err)
+	}
+	if !open {
+		return nil, errors.New("invalid vote: the poll is not open")
+	}
+
+	if _, err := s.announce(meeting, poll.ID, "poll_results"); err != nil {
+		fmt.Printf("PollService: Failed to send results of poll %d: %v\n", poll.ID, err)
+	}
+
+	return s.result(poll, false, claims.Identity)
+}
+
+// ListParticipantPolls returns the open and closed polls of the meeting a join
+// token was issued for, with the options its participant chose, so that people
+// who join late can catch up
+func (s *PollService) ListParticipantPolls(token string) ([]dto.PollResponse, error) {
+	claims, err := s.livekitService.VerifyJoinToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	meeting, err := s.meetingService.CheckParticipant(claims.Video.Room, claims.Identity)
+	if err != nil {
+		return nil, err
+	}
+
+	polls, err := s.pollRepo.FindByMeetingID(meeting.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch polls: %w", err)
+	}
+
+	visible := make([]models.Poll, 0, len(polls))
+	for _, poll := range polls {
+		if poll.Status != models.PollStatusDraft {
+			visible = append(visible, poll)
+		}
+	}
+
+	return s.results(visible, false, claims.Identity)
+}
+
+// announce sends a poll with its current results to every room of a meeting
+func (s *PollService) announce(meeting *models.Meeting, pollID uint, messageType string) (*dto.PollResponse, error) {
+	poll, err := s.pollRepo.FindByID(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch poll: %w", err)
+	}
+
+	votes, err := s.pollRepo.FindVotes(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch votes: %w", err)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type": messageType,
+		"poll": tallyPoll(poll, votes, false),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal poll: %w", err)
+	}
+
+	rooms := []string{meeting.MeetingCode}
+	if breakouts, err := cache.GetBreakouts(meeting.MeetingCode); err == nil && breakouts.Opened {
+		for _, room := range breakouts.Rooms {
+			rooms = append(rooms, room.RoomName)
+		}
+	}
+	for _, room := range rooms {
+		if err := s.livekitService.SendData(room, nil, pollTopic, payload); err != nil {
+			fmt.Printf("PollService: Failed to send poll %d to room %s: %v\n", pollID, room, err)
+		}
+	}
+
+	return tallyPoll(poll, votes, true), nil
+}
+
+// results tallies the votes of several polls
+func (s *PollService) results(polls []models.Poll, withVoters bool, identity string) ([]dto.PollResponse, error) {
+	responses := make([]dto.PollResponse, 0, len(polls))
+	for i := range polls {
+		response, err := s.result(&polls[i], withVoters, identity)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
+
+// result tallies the votes of a poll, along with the options identity chose when set
+func (s *PollService) result(poll *models.Poll, withVoters bool, identity string) (*dto.PollResponse, error) {
+	votes, err := s.pollRepo.FindVotes(poll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch votes: %w", err)
+	}
+
+	response := tallyPoll(poll, votes, withVoters)
+	if identity != "" {
+		for _, vote := range votes {
+			if vote.Identity == identity {
+				response.MyVotes = append(response.MyVotes, vote.OptionID)
+			}
+		}
+	}
+	return response, nil
+}
+
+// hostMeeting returns a meeting userID hosts
+func (s *PollService) hostMeeting(meetingID uint, userID uint) (*models.Meeting, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can manage polls")
+	}
+	return meeting, nil
+}
+
+// findPoll returns a poll of a meeting
+func (s *PollService) findPoll(meetingID uint, pollID uint) (*models.Poll, error) {
+	poll, err := s.pollRepo.FindByID(pollID)
+	if err != nil || poll.MeetingID != meetingID {
+		return nil, errors.New("poll not found")
+	}
+	return poll, nil
+}
+
+// tallyPoll counts the votes of each option of a poll. Voters are listed when
+// withVoters is set, unless the poll is anonymous.
+func tallyPoll(poll *models.Poll, votes []models.PollVote, withVoters bool) *dto.PollResponse {
+	withVoters = withVoters && !poll.Anonymous
+
+	byOption := make(map[uint][]models.PollVote)
+	voters := make(map[string]bool)
+	for _, vote := range votes {
+		byOption[vote.OptionID] = append(byOption[vote.OptionID], vote)
+		voters[vote.Identity] = true
+	}
+
+	options := make([]dto.PollOptionResult, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		result := dto.PollOptionResult{
+			ID:    option.ID,
+			Text:  option.Text,
+			Votes: len(byOption[option.ID]),
+		}
+		if withVoters {
+			for _, vote := range byOption[option.ID] {
+				result.Voters = append(result.Voters, dto.PollVoter{
+					Identity: vote.Identity,
+					Name:     vote.Name,
+					UserID:   vote.UserID,
+				})
+			}
+		}
+		options = append(options, result)
+	}
+
+	return &dto.PollResponse{
+		ID:             poll.ID,
+		MeetingID:      poll.MeetingID,
+		Question:       poll.Question,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Status:         string(poll.Status),
+		OpenedAt:       poll.OpenedAt,
+		ClosedAt:       poll.ClosedAt,
+		CreatedAt:      poll.CreatedAt,
+		TotalVoters:    len(voters),
+		Options:        options,
+	}
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mini-meeting/internal/config"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"mini-meeting/internal/repositories"
+	"mini-meeting/pkg/s3"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+)
+
+const (
+	recordingStorageLocal = "local"
+	recordingStorageS3    = "s3"
+
+	// recordingURLExpiration is how long a presigned download URL is valid
+	recordingURLExpiration = 15 * time.Minute
+)
+
+// RecordingService records meeting rooms with LiveKit Egress, either the whole
+// room composed into one file or tracks on their own, and follows the jobs
+// through egress webhooks until their files are written
+type RecordingService struct {
+	recordingRepo  *repositories.RecordingRepository
+	meetingService *MeetingService
+	livekitService *LiveKitService
+	cfg            *config.Config
+}
+
+func NewRecordingService(recordingRepo *repositories.RecordingRepository, meetingService *MeetingService, livekitService *LiveKitService, cfg *config.Config) *RecordingService {
+	return &RecordingService{
+		recordingRepo:  recordingRepo,
+		meetingService: meetingService,
+		livekitService: livekitService,
+		cfg:            cfg,
+	}
+}
+
+// StartRecording starts recording a room of a meeting and returns the recordings
+// started, one per track for track recordings (hosts only)
+func (s *RecordingService) StartRecording(meetingID uint, userID uint, req *dto.StartRecordingRequest) ([]models.Recording, error) {
+	meeting, err := s.hostMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	room := meeting.MeetingCode
+	if req.RoomName != "" {
+		if code, _, ok := ParseBreakoutRoomName(req.RoomName); !ok || code != meeting.MeetingCode {
+			return nil, errors.New("invalid room_name: not a breakout room of this meeting")
+		}
+		room = req.RoomName
+	}
+
+	switch models.RecordingType(req.Type) {
+	case "", models.RecordingTypeRoomComposite:
+		recording, err := s.startRoomComposite(meeting, room, userID, req)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Recording{*recording}, nil
+	case models.RecordingTypeTrack:
+		return s.startTracks(meeting, room, userID, req)
+	default:
+		return nil, errors.New("invalid type: must be room_composite or track")
+	}
+}
+
+func (s *RecordingService) startRoomComposite(meeting *models.Meeting, room string, userID uint, req *dto.StartRecordingRequest) (*models.Recording, error) {
+	active, err := s.recordingRepo.FindActiveByMeetingID(meeting.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recordings: %w", err)
+	}
+	for _, recording := range active {
+		if recording.Type == models.RecordingTypeRoomComposite && s.livekitRoom(meeting, &recording) == room {
+			return nil, errors.New("invalid request: the room is already being recorded")
+		}
+	}
+
+	fileType, extension := livekit.EncodedFileType_MP4, "mp4"
+	if req.AudioOnly {
+		fileType, extension = livekit.EncodedFileType_OGG, "ogg"
+	}
+
+	output := &livekit.EncodedFileOutput{
+		FileType: fileType,
+		Filepath: s.outputPath(meeting.MeetingCode, fmt.Sprintf("%s-{time}.%s", room, extension)),
+	}
+	if s.cfg.Recording.Storage == recordingStorageS3 {
+		output.Output = &livekit.EncodedFileOutput_S3{S3: s.s3Upload()}
+	}
+
+	info, err := s.livekitService.StartRoomCompositeEgress(&livekit.RoomCompositeEgressRequest{
+		RoomName:    room,
+		Layout:      req.Layout,
+		AudioOnly:   req.AudioOnly,
+		FileOutputs: []*livekit.EncodedFileOutput{output},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	recording := s.newRecording(meeting, room, userID, info)
+	recording.Type = models.RecordingTypeRoomComposite
+	recording.AudioOnly = req.AudioOnly
+	if err := s.recordingRepo.Create(recording); err != nil {
+		return nil, fmt.Errorf("failed to save recording: %w", err)
+	}
+
+	fmt.Printf("RecordingService: Started recording %s of room %s\n", recording.EgressID, room)
+	return recording, nil
+}
+
+func (s *RecordingService) startTracks(meeting *models.Meeting, room string, userID uint, req *dto.StartRecordingRequest) ([]models.Recording, error) {
+	if req.ParticipantIdentity == "" && req.TrackSID == "" {
+		return nil, errors.New("invalid request: participant_identity or track_sid is required for track recordings")
+	}
+
+	participants, err := s.livekitService.ListParticipants(room)
+	if err != nil {
+		return nil, err
+	}
+
+	type recordedTrack struct {
+		identity string
+		track    *livekit.TrackInfo
+	}
+	tracks := make([]recordedTrack, 0)
+	participantFound := false
+	for _, participant := range participants {
+		if req.ParticipantIdentity != "" && participant.Identity != req.ParticipantIdentity {
+			continue
+		}
+		participantFound = true
+		for _, track := range participant.Tracks {
+			if req.TrackSID != "" && track.Sid != req.TrackSID {
+				continue
+			}
+			if req.AudioOnly && track.Type != livekit.TrackType_AUDIO {
+				continue
+			}
+			tracks = append(tracks, recordedTrack{identity: participant.Identity, track: track})
+		}
+	}
+	if !participantFound {
+		return nil, errors.New("participant not found")
+	}
+	if len(tracks) == 0 {
+		return nil, errors.New("track not found")
+	}
+
+	recordings := make([]models.Recording, 0, len(tracks))
+	for _, t := range tracks {
+		output := &livekit.DirectFileOutput{
+			Filepath: s.outputPath(meeting.MeetingCode, fmt.Sprintf("%s-{track_id}-{time}", room)),
+		}
+		if s.cfg.Recording.Storage == recordingStorageS3 {
+			output.Output = &livekit.DirectFileOutput_S3{S3: s.s3Upload()}
+		}
+
+		info, err := s.livekitService.StartTrackEgress(&livekit.TrackEgressRequest{
+			RoomName: room,
+			TrackId:  t.track.Sid,
+			Output:   &livekit.TrackEgressRequest_File{File: output},
+		})
+		if err != nil {
+			if len(recordings) > 0 {
+				// Keep the tracks already recording rather than failing the whole request
+				fmt.Printf("RecordingService: Failed to record track %s of room %s: %v\n", t.track.Sid, room, err)
+				continue
+			}
+			return nil, err
+		}
+
+		recording := s.newRecording(meeting, room, userID, info)
+		recording.Type = models.RecordingTypeTrack
+		recording.TrackID = t.track.Sid
+		recording.ParticipantIdentity = t.identity
+		recording.AudioOnly = t.track.Type == livekit.TrackType_AUDIO
+		if err := s.recordingRepo.Create(recording); err != nil {
+			return nil, fmt.Errorf("failed to save recording: %w", err)
+		}
+		recordings = append(recordings, *recording)
+	}
+
+	fmt.Printf("RecordingService: Started recording %d tracks of room %s\n", len(recordings), room)
+	return recordings, nil
+}
+
+// StopRecording stops a recording of a meeting, or all of its active recordings
+// when recordingID is 0, and returns the recordings stopped (hosts only). Their
+// files are reported by egress webhooks once written.
+func (s *RecordingService) StopRecording(meetingID uint, userID uint, recordingID uint) ([]models.Recording, error) {
+	if _, err := s.hostMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	var recordings []models.Recording
+	if recordingID != 0 {
+		recording, err := s.findRecording(meetingID, recordingID)
+		if err != nil {
+			return nil, err
+		}
+		if !recording.IsActive() {
+			return nil, errors.New("invalid request: the recording is not active")
+		}
+		recordings = []models.Recording{*recording}
+	} else {
+		active, err := s.recordingRepo.FindActiveByMeetingID(meetingID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch recordings: %w", err)
+		}
+		recordings = active
+	}
+
+	stopped := make([]models.Recording, 0, len(recordings))
+	for i := range recordings {
+		recording := &recordings[i]
+		info, err := s.livekitService.StopEgress(recording.EgressID)
+		if err != nil {
+			if recordingID != 0 {
+				return nil, err
+			}
+			fmt.Printf("RecordingService: Failed to stop recording %s: %v\n", recording.EgressID, err)
+			continue
+		}
+		s.applyEgressInfo(recording, info)
+		if err := s.recordingRepo.Update(recording); err != nil {
+			fmt.Printf("RecordingService: Failed to update recording %s: %v\n", recording.EgressID, err)
+		}
+		stopped = append(stopped, *recording)
+	}
+
+	return stopped, nil
+}
+
+// ListRecordings returns the recordings of a meeting, newest first (hosts only)
+func (s *RecordingService) ListRecordings(meetingID uint, userID uint) ([]models.Recording, error) {
+	if _, err := s.hostMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	recordings, err := s.recordingRepo.FindByMeetingID(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recordings: %w", err)
+	}
+	return recordings, nil
+}
+
+// GetDownload returns where the file of a completed recording can be fetched (hosts only)
+func (s *RecordingService) GetDownload(meetingID uint, recordingID uint, userID uint) (*dto.RecordingDownload, error) {
+	if _, err := s.hostMeeting(meetingID, userID); err != nil {
+		return nil, err
+	}
+
+	recording, err := s.findRecording(meetingID, recordingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if recording.Status != models.RecordingStatusComplete || recording.FilePath == "" {
+		return nil, errors.New("invalid request: the recording is not available yet")
+	}
+
+	download := &dto.RecordingDownload{
+		Recording: recording,
+		FileName:  path.Base(recording.FilePath),
+	}
+
+	switch recording.Storage {
+	case recordingStorageS3:
+		bucket := s.s3Bucket()
+		url, err := bucket.PresignGetURL(recording.FilePath, recordingURLExpiration, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign download URL: %w", err)
+		}
+		download.URL = url
+	default:
+		localPath := filepath.Join(s.cfg.Recording.LocalDir, filepath.FromSlash(recording.FilePath))
+		if rel, err := filepath.Rel(s.cfg.Recording.LocalDir, localPath); err != nil || strings.HasPrefix(rel, "..") {
+			return nil, errors.New("recording file not found")
+		}
+		if _, err := os.Stat(localPath); err != nil {
+			return nil, errors.New("recording file not found")
+		}
+		download.LocalPath = localPath
+	}
+
+	return download, nil
+}
+
+// HandleWebhookEvent follows recordings through the egress_started, egress_updated
+// and egress_ended events
+func (s *RecordingService) HandleWebhookEvent(event *livekit.WebhookEvent) {
+	if event.EgressInfo == nil {
+		return
+	}
+
+	switch event.Event {
+	case "egress_started", "egress_updated", "egress_ended":
+		recording, err := s.recordingRepo.FindByEgressID(event.EgressInfo.EgressId)
+		if err != nil {
+			// Not a recording of ours
+			return
+		}
+		s.applyEgressInfo(recording, event.EgressInfo)
+		if err := s.recordingRepo.Update(recording); err != nil {
+			fmt.Printf("RecordingService: Failed to update recording %s: %v\n", recording.EgressID, err)
+			return
+		}
+		if event.Event == "egress_ended" {
+			fmt.Printf("RecordingService: Recording %s ended with status %s\n", recording.EgressID, recording.Status)
+		}
+	}
+}
+
+// applyEgressInfo copies the status of an egress, and its file once written, into a
+// recording. Webhooks may arrive out of order, so info older than what the
+// recording already shows, such as ACTIVE after COMPLETE, is ignored.
+func (s *RecordingService) applyEgressInfo(recording *models.Recording, info *livekit.EgressInfo) {
+	status := models.RecordingStatus(strings.TrimPrefix(info.Status.String(), "EGRESS_"))
+	if status.Progress() < recording.Status.Progress() ||
+		(status.Progress() == recording.Status.Progress() && status != recording.Status) {
+		return
+	}
+	recording.Status = status
+
+	if info.Error != "" {
+		errMsg := info.Error
+		recording.Error = &errMsg
+	}
+
+	if info.EndedAt > 0 {
+		endedAt := time.Unix(0, info.EndedAt)
+		recording.EndedAt = &endedAt
+	}
+
+	file := info.GetFile()
+	if len(info.FileResults) > 0 {
+		file = info.FileResults[0]
+	}
+	if file != nil && file.Filename != "" {
+		recording.FilePath = s.storageKey(file.Filename)
+		recording.SizeBytes = file.Size
+		recording.DurationSeconds = time.Duration(file.Duration).Seconds()
+	}
+}
+
+func (s *RecordingService) newRecording(meeting *models.Meeting, room string, userID uint, info *livekit.EgressInfo) *models.Recording {
+	recording := &models.Recording{
+		MeetingID: meeting.ID,
+		StartedBy: userID,
+		EgressID:  info.EgressId,
+		Storage:   s.storage(),
+		StartedAt: time.Now(),
+	}
+	if room != meeting.MeetingCode {
+		recording.RoomName = room
+	}
+	s.applyEgressInfo(recording, info)
+	return recording
+}
+
+// outputPath is where Egress writes a file of a meeting: under the output
+// directory, or under the key prefix in the bucket
+func (s *RecordingService) outputPath(meetingCode string, name string) string {
+	if s.storage() == recordingStorageS3 {
+		return path.Join(s.cfg.Recording.S3.Prefix, meetingCode, name)
+	}
+	return path.Join(s.cfg.Recording.OutputDir, meetingCode, name)
+}
+
+// storageKey turns the file name Egress reports into the path of the file under
+// the recording directory, or its key in the bucket
+func (s *RecordingService) storageKey(filename string) string {
+	if s.storage() == recordingStorageS3 {
+		return strings.TrimPrefix(filename, "/")
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(filename, s.cfg.Recording.OutputDir), "/")
+}
+
+func (s *RecordingService) storage() string {
+	if s.cfg.Recording.Storage == recordingStorageS3 {
+		return recordingStorageS3
+	}
+	return recordingStorageLocal
+}
+
+func (s *RecordingService) s3Upload() *livekit.S3Upload {
+	return &livekit.S3Upload{
+		AccessKey:      s.cfg.Recording.S3.AccessKey,
+		Secret:         s.cfg.Recording.S3.SecretKey,
+		Region:         s.cfg.Recording.S3.Region,
+		Endpoint:       s.cfg.Recording.S3.Endpoint,
+		Bucket:         s.cfg.Recording.S3.Bucket,
+		ForcePathStyle: s.cfg.Recording.S3.ForcePathStyle,
+	}
+}
+
+func (s *RecordingService) s3Bucket() *s3.Bucket {
+	return &s3.Bucket{
+		Endpoint:       s.cfg.Recording.S3.Endpoint,
+		Region:         s.cfg.Recording.S3.Region,
+		Name:           s.cfg.Recording.S3.Bucket,
+		AccessKey:      s.cfg.Recording.S3.AccessKey,
+		SecretKey:      s.cfg.Recording.S3.SecretKey,
+		ForcePathStyle: s.cfg.Recording.S3.ForcePathStyle,
+	}
+}
+
+// livekitRoom returns the LiveKit room a recording captured
+func (s *RecordingService) livekitRoom(meeting *models.Meeting, recording *models.Recording) string {
+	if recording.RoomName != "" {
+		return recording.RoomName
+	}
+	return meeting.MeetingCode
+}
+
+// hostMeeting returns a meeting userID hosts
+func (s *RecordingService) hostMeeting(meetingID uint, userID uint) (*models.Meeting, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can manage recordings")
+	}
+	return meeting, nil
+}
+
+// findRecording returns a recording of a meeting
+func (s *RecordingService) findRecording(meetingID uint, recordingID uint) (*models.Recording, error) {
+	recording, err := s.recordingRepo.FindByID(recordingID)
+	if err != nil || recording.MeetingID != meetingID {
+		return nil, errors.New("recording not found")
+	}
+	return recording, nil
+}
@@ -6,6 +6,7 @@ import (
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/models"
 	"mini-meeting/internal/repositories"
+	"strings"
 	"time"
 )
 
@@ -26,6 +27,7 @@ type SummarizationResponse struct {
 
 type SummarizationService struct {
 	sessionRepo       *repositories.SummarizerSessionRepository
+	pollRepo          *repositories.PollRepository
 	userService       *UserService
 	openRouterService *OpenRouterService
 	emailService      *EmailService
@@ -34,6 +36,7 @@ type SummarizationService struct {
 
 func NewSummarizationService(
 	sessionRepo *repositories.SummarizerSessionRepository,
+	pollRepo *repositories.PollRepository,
 	userService *UserService,
 	openRouterService *OpenRouterService,
 	emailService *EmailService,
@@ -41,6 +44,7 @@ func NewSummarizationService(
 ) *SummarizationService {
 	return &SummarizationService{
 		sessionRepo:       sessionRepo,
+		pollRepo:          pollRepo,
 		userService:       userService,
 		openRouterService: openRouterService,
 		emailService:      emailService,
@@ -69,7 +73,7 @@ func (s *SummarizationService) ProcessSummarization(sessionID uint) error {
 		return fmt.Errorf("no normalized transcript found for session %d", sessionID)
 	}
 
-	transcript := *session.Transcript
+	transcript := *session.Transcript + s.pollContext(session)
 
 	fmt.Printf("Starting summarization for session %d (transcript length: %d chars)\n", sessionID, len(transcript))
 
@@ -119,3 +123,58 @@ func (s *SummarizationService) ProcessSummarization(sessionID uint) error {
 
 	return nil
 }
+
+// pollContext describes the polls opened while a session was captured and their
+// results, to be summarized along with the transcript. Voters of named polls are
+// listed; anonymous polls only have counts.
+func (s *SummarizationService) pollContext(session *models.SummarizerSession) string {
+	endedAt := time.Now()
+	if session.EndedAt != nil {
+		endedAt = *session.EndedAt
+	}
+
+	polls, err := s.pollRepo.FindOpenedBetween(session.MeetingID, session.StartedAt, endedAt)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load polls for session %d: %v\n", session.ID, err)
+		return ""
+	}
+	if len(polls) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n## Polls\n")
+	for i := range polls {
+		votes, err := s.pollRepo.FindVotes(polls[i].ID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to load votes of poll %d: %v\n", polls[i].ID, err)
+			continue
+		}
+		result := tallyPoll(&polls[i], votes, true)
+
+		kind := "single choice"
+		if result.MultipleChoice {
+			kind = "multiple choice"
+		}
+		if result.Anonymous {
+			kind += ", anonymous"
+		}
+		if polls[i].Status == models.PollStatusOpen {
+			kind += ", still open"
+		}
+		fmt.Fprintf(&b, "\nPoll: %s (%s, %d voters)\n", result.Question, kind, result.TotalVoters)
+
+		for _, option := range result.Options {
+			fmt.Fprintf(&b, "- %s: %d votes", option.Text, option.Votes)
+			if len(option.Voters) > 0 {
+				names := make([]string, 0, len(option.Voters))
+				for _, voter := range option.Voters {
+					names = append(names, voter.Name)
+				}
+				fmt.Fprintf(&b, " (%s)", strings.Join(names, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"mini-meeting/internal/handlers/dto"
+	"mini-meeting/internal/models"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// importIdentity is the speaker of imported audio that mixes everyone together
+	importIdentity = "Recording"
+
+	// importTimeout bounds fetching and splitting one imported recording
+	importTimeout = 2 * time.Hour
+)
+
+// importExtensions are the audio and video formats that can be imported
+var importExtensions = map[string]bool{
+	".aac": true, ".flac": true, ".m4a": true, ".mkv": true, ".mov": true, ".mp3": true,
+	".mp4": true, ".oga": true, ".ogg": true, ".opus": true, ".wav": true, ".webm": true,
+}
+
+// ImportSession creates a session for a meeting that was recorded without the
+// summarizer, from an uploaded file or a completed recording of the meeting.
+// The session starts out IMPORTING while its audio is split into chunks in the
+// background, so that the transcription worker leaves it alone. It becomes
+// CAPTURED once the chunks exist, or FAILED, and then goes through transcription,
+// normalization and summarization like a captured session.
+func (s *SummarizerService) ImportSession(userID uint, req *dto.ImportSessionRequest, upload *multipart.FileHeader) (*models.SummarizerSession, error) {
+	if (upload == nil) == (req.RecordingID == 0) {
+		return nil, errors.New("invalid request: upload a file or choose a recording_id")
+	}
+
+	meeting, err := s.meetingService.GetMeetingByID(req.MeetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can import recordings")
+	}
+
+	settings, err := s.meetingService.GetSettings(meeting.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load meeting settings: %w", err)
+	}
+	if !settings.SummarizerAllowed {
+		return nil, fmt.Errorf("summarizer is not allowed for this meeting")
+	}
+
+	if upload != nil {
+		if !importExtensions[strings.ToLower(filepath.Ext(upload.Filename))] {
+			return nil, errors.New("invalid file: unsupported audio or video format")
+		}
+		if upload.Size > int64(s.cfg.Summarizer.MaxImportMB)*1024*1024 {
+			return nil, fmt.Errorf("invalid file: larger than %d MB", s.cfg.Summarizer.MaxImportMB)
+		}
+	}
+
+	if _, err := exec.LookPath(s.cfg.Summarizer.FFmpegPath); err != nil {
+		return nil, errors.New("recording import is unavailable: ffmpeg is not installed")
+	}
+
+	if err := s.checkQuota(userID); err != nil {
+		return nil, err
+	}
+
+	session := &models.SummarizerSession{
+		MeetingID: meeting.ID,
+		UserID:    userID,
+		Status:    models.StatusImporting,
+		StartedAt: time.Now(),
+	}
+
+	var download *dto.RecordingDownload
+	identity := importIdentity
+	if req.RecordingID != 0 {
+		download, err = s.recordingService.GetDownload(meeting.ID, req.RecordingID, userID)
+		if err != nil {
+			return nil, err
+		}
+		// Place the session when the recording was made, so that the chat and
+		// polls of that time are summarized along with it
+		recording := download.Recording
+		session.StartedAt = recording.StartedAt
+		session.EndedAt = recording.EndedAt
+		session.RoomName = recording.RoomName
+		if recording.ParticipantIdentity != "" {
+			identity = recording.ParticipantIdentity
+		}
+	}
+
+	if occurrence := s.meetingService.ClosestOccurrence(meeting, session.StartedAt); occurrence != nil {
+		occurrenceStart := occurrence.OriginalStart()
+		session.OccurrenceStart = &occurrenceStart
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	// The upload only lives as long as the request, so keep a copy
+	sessionDir := filepath.Join(s.cfg.Summarizer.TempDir, fmt.Sprintf("%d", session.ID))
+	var input string
+	if upload != nil {
+		input = filepath.Join(sessionDir, "import"+strings.ToLower(filepath.Ext(upload.Filename)))
+		if err := saveUpload(upload, input); err != nil {
+			s.failImport(session.ID, err)
+			return nil, fmt.Errorf("failed to save upload: %w", err)
+		}
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+		defer cancel()
+
+		if download != nil {
+			if download.URL != "" {
+				input = filepath.Join(sessionDir, "import"+filepath.Ext(download.FileName))
+				if err := downloadFile(ctx, download.URL, input); err != nil {
+					s.failImport(session.ID, fmt.Errorf("failed to download recording: %w", err))
+					return
+				}
+			} else {
+				input = download.LocalPath
+			}
+		}
+
+		if err := s.splitRecording(ctx, session, identity, input); err != nil {
+			s.failImport(session.ID, err)
+			return
+		}
+		if strings.HasPrefix(input, sessionDir) {
+			os.Remove(input)
+		}
+
+		if _, err := s.sessionRepo.UpdateStatusFrom(session.ID, models.StatusImporting, models.StatusCaptured, nil); err != nil {
+			s.failImport(session.ID, fmt.Errorf("failed to update session status: %w", err))
+			return
+		}
+
+		fmt.Printf("Triggering background transcription for imported session %d\n", session.ID)
+		if err := s.transcriptionService.ProcessSession(session.ID); err != nil {
+			fmt.Printf("Failed to process session %d: %v\n", session.ID, err)
+		}
+	}()
+
+	return session, nil
+}
+
+// splitRecording extracts the audio of a recording into chunks of the configured
+// length, laid out like the chunks the bot captures
+func (s *SummarizerService) splitRecording(ctx context.Context, session *models.SummarizerSession, identity string, input string) error {
+	userDir, err := s.setupUserDirectory(session.ID, identity)
+	if err != nil {
+		return err
+	}
+
+	chunkSeconds := s.cfg.Summarizer.ChunkDurationSeconds
+	cmd := exec.CommandContext(ctx, s.cfg.Summarizer.FFmpegPath,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", input,
+		"-vn", "-ac", "1", "-ar", "16000", "-c:a", "libopus", "-b:a", "32k",
+		"-f", "segment", "-segment_format", "ogg",
+		"-segment_time", strconv.Itoa(chunkSeconds), "-reset_timestamps", "1",
+		filepath.Join(userDir, "chunk_%04d.ogg"),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to split recording: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	files, err := filepath.Glob(filepath.Join(userDir, "chunk_*.ogg"))
+	if err != nil {
+		return fmt.Errorf("failed to list chunks: %w", err)
+	}
+	sort.Strings(files)
+
+	// Every chunk gets the nominal length: transcription places chunks by
+	// index times duration, and only the last one is shorter
+	chunkDuration := time.Duration(chunkSeconds) * time.Second
+	for i, file := range files {
+		start := session.StartedAt.Add(time.Duration(i) * chunkDuration)
+		chunk := &models.AudioChunk{
+			SessionID:       session.ID,
+			UserIdentity:    identity,
+			ChunkIndex:      i,
+			FilePath:        file,
+			StartTimestamp:  start,
+			EndTimestamp:    start.Add(chunkDuration),
+			DurationSeconds: chunkDuration.Seconds(),
+		}
+		if err := s.chunkRepo.Create(chunk); err != nil {
+			return fmt.Errorf("failed to create audio chunk metadata: %w", err)
+		}
+	}
+
+	fmt.Printf("Split imported recording of session %d into %d chunks\n", session.ID, len(files))
+	return nil
+}
+
+// failImport records why an imported session could not be prepared
+func (s *SummarizerService) failImport(sessionID uint, err error) {
+	errMsg := fmt.Sprintf("Failed to import recording: %v", err)
+	now := time.Now()
+	s.sessionRepo.UpdateStatus(sessionID, models.StatusFailed, &errMsg, &now)
+	fmt.Printf("%s (session %d)\n", errMsg, sessionID)
+}
+
+// saveUpload copies an uploaded file to path
+func saveUpload(upload *multipart.FileHeader, path string) error {
+	src, err := upload.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return writeFile(src, path)
+}
+
+// downloadFile fetches url into path
+func downloadFile(ctx context.Context, url string, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage responded with status %d", resp.StatusCode)
+	}
+	return writeFile(resp.Body, path)
+}
+
+func writeFile(src io.Reader, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
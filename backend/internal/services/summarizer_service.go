@@ -1,9 +1,12 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mini-meeting/internal/cache"
 	"mini-meeting/internal/config"
 	"mini-meeting/internal/handlers/dto"
 	"mini-meeting/internal/models"
@@ -13,34 +16,60 @@ import (
 	"sync"
 	"time"
 
+	"github.com/livekit/protocol/livekit"
 	lksdk "github.com/livekit/server-sdk-go/v2"
 	"github.com/pion/webrtc/v4"
 	"github.com/pion/webrtc/v4/pkg/media/oggwriter"
 )
 
+// consentTopic is the data message topic participants answer the consent request
+// on, with a payload of {"consent": true} or {"consent": false}
+const consentTopic = "summarizer-consent"
+
 type SummarizerService struct {
 	sessionRepo          *repositories.SummarizerSessionRepository
 	chunkRepo            *repositories.AudioChunkRepository
 	transcriptRepo       *repositories.TranscriptRepository
+	consentRepo          *repositories.SummarizerConsentRepository
 	meetingService       *MeetingService
 	livekitService       *LiveKitService
+	recordingService     *RecordingService
 	transcriptionService *TranscriptionService
 	cfg                  *config.Config
 
-	// Active rooms tracking for graceful shutdown
-	activeRooms map[uint]*lksdk.Room
-	roomMutex   sync.RWMutex
+	// Bots currently capturing a session, by session ID
+	activeBots map[uint]*summarizerBot
+	roomMutex  sync.RWMutex
+}
+
+// summarizerBot is the bot connection capturing one session
+type summarizerBot struct {
+	room        *lksdk.Room
+	meetingCode string
+	// roomName is the LiveKit room captured: the meeting code or a breakout room
+	roomName    string
+	consentMode bool
 
-	// Goroutine tracking
+	// Identities of participants who declined to be transcribed
+	declined     map[string]bool
+	consentMutex sync.RWMutex
+
+	// Audio capture goroutines of the session
 	wg sync.WaitGroup
+
+	// idleTimer stops the session once the room has stayed empty for the idle timeout
+	idleTimer *time.Timer
+	idleMutex sync.Mutex
 }
 
 func NewSummarizerService(
 	sessionRepo *repositories.SummarizerSessionRepository,
 	chunkRepo *repositories.AudioChunkRepository,
 	transcriptRepo *repositories.TranscriptRepository,
+	consentRepo *repositories.SummarizerConsentRepository,
 	meetingService *MeetingService,
 	livekitService *LiveKitService,
+	recordingService *RecordingService,
 	transcriptionService *TranscriptionService,
 	cfg *config.Config,
 ) *SummarizerService {
@@ -48,47 +77,115 @@ func NewSummarizerService(
 		sessionRepo:          sessionRepo,
 		chunkRepo:            chunkRepo,
 		transcriptRepo:       transcriptRepo,
+		consentRepo:          consentRepo,
 		meetingService:       meetingService,
 		livekitService:       livekitService,
+		recordingService:     recordingService,
 		transcriptionService: transcriptionService,
 		cfg:                  cfg,
-		activeRooms:          make(map[uint]*lksdk.Room),
+		activeBots:           make(map[uint]*summarizerBot),
 	}
 }
 
 // StartSummarizer starts a new summarizer session for a meeting
 func (s *SummarizerService) StartSummarizer(meetingID uint, userID uint) (*models.SummarizerSession, error) {
-	// Validate meeting exists and user is the creator
+	// Validate meeting exists and user is a host
 	meeting, err := s.meetingService.GetMeetingByID(meetingID)
 	if err != nil {
 		return nil, fmt.Errorf("meeting not found: %w", err)
 	}
 
-	if meeting.CreatorID != userID {
-		return nil, fmt.Errorf("unauthorized: only meeting creator can start summarizer")
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, fmt.Errorf("unauthorized: only meeting hosts can start summarizer")
+	}
+
+	return s.startSession(meeting, userID, "")
+}
+
+// StartBreakoutSummarizer starts a session capturing one breakout room of a
+// meeting. The caller checks that userID is a host.
+func (s *SummarizerService) StartBreakoutSummarizer(meeting *models.Meeting, userID uint, roomName string) (*models.SummarizerSession, error) {
+	return s.startSession(meeting, userID, roomName)
+}
+
+// AutoStart starts a session for a meeting whose settings ask for every meeting
+// to be summarized. The session belongs to the meeting creator and counts
+// toward their quota.
+func (s *SummarizerService) AutoStart(meeting *models.Meeting) {
+	settings, err := s.meetingService.GetSettings(meeting.ID)
+	if err != nil || !settings.AutoSummarize {
+		return
+	}
+
+	session, err := s.startSession(meeting, meeting.CreatorID, "")
+	if err != nil {
+		if err.Error() != "summarizer already running for this meeting" {
+			fmt.Printf("Failed to auto-start summarizer for meeting %d: %v\n", meeting.ID, err)
+		}
+		return
+	}
+
+	fmt.Printf("Auto-started summarizer session %d for meeting %d\n", session.ID, meeting.ID)
+}
+
+// startSession creates a session for a meeting on behalf of userID and sends the
+// bot into its room, or into one of its breakout rooms when roomName is set
+func (s *SummarizerService) startSession(meeting *models.Meeting, userID uint, roomName string) (*models.SummarizerSession, error) {
+	settings, err := s.meetingService.GetSettings(meeting.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load meeting settings: %w", err)
+	}
+	if !settings.SummarizerAllowed {
+		return nil, fmt.Errorf("summarizer is not allowed for this meeting")
+	}
+
+	alreadyRunning := fmt.Errorf("summarizer already running for this meeting")
+	if roomName != "" {
+		alreadyRunning = fmt.Errorf("summarizer already running for this room")
 	}
 
 	// Check if there's already an active session
-	existingSession, err := s.sessionRepo.FindActiveByMeetingID(meetingID)
+	existingSession, err := s.sessionRepo.FindActiveByRoomName(meeting.ID, roomName)
 	if err == nil && existingSession != nil {
-		return nil, fmt.Errorf("summarizer already running for this meeting")
+		return nil, alreadyRunning
+	}
+
+	if err := s.checkQuota(userID); err != nil {
+		return nil, err
 	}
 
 	// Create new session
 	session := &models.SummarizerSession{
-		MeetingID: meetingID,
+		MeetingID: meeting.ID,
 		UserID:    userID,
 		Status:    models.StatusStarted,
 		StartedAt: time.Now(),
+		RoomName:  roomName,
 	}
 
-	if err := s.sessionRepo.Create(session); err != nil {
+	// Link the session to the occurrence of a scheduled meeting it belongs to
+	if occurrence := s.meetingService.ClosestOccurrence(meeting, session.StartedAt); occurrence != nil {
+		occurrenceStart := occurrence.OriginalStart()
+		session.OccurrenceStart = &occurrenceStart
+	}
+
+	// A session started meanwhile, possibly by another instance, wins
+	created, err := s.sessionRepo.CreateActive(session)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	if !created {
+		return nil, alreadyRunning
+	}
+
+	livekitRoom := meeting.MeetingCode
+	if roomName != "" {
+		livekitRoom = roomName
+	}
 
 	// Join LiveKit room as bot in background
 	go func() {
-		if err := s.joinLiveKitRoom(meeting.MeetingCode, session.ID); err != nil {
+		if err := s.joinLiveKitRoom(meeting.MeetingCode, livekitRoom, session.ID, settings.ConsentMode); err != nil {
 			// Update session status with error
 			now := time.Now()
 			errMsg := fmt.Sprintf("Failed to join LiveKit room: %v", err)
@@ -108,14 +205,14 @@ func (s *SummarizerService) StopSummarizer(sessionID uint, userID uint) (int64,
 		return 0, fmt.Errorf("session not found: %w", err)
 	}
 
-	// Validate user is meeting creator
+	// Validate user is a meeting host
 	meeting, err := s.meetingService.GetMeetingByID(session.MeetingID)
 	if err != nil {
 		return 0, fmt.Errorf("meeting not found: %w", err)
 	}
 
-	if meeting.CreatorID != userID {
-		return 0, fmt.Errorf("unauthorized: only meeting creator can stop summarizer")
+	if !s.meetingService.IsHost(meeting, userID) {
+		return 0, fmt.Errorf("unauthorized: only meeting hosts can stop summarizer")
 	}
 
 	// Validate session is in STARTED state
@@ -123,22 +220,161 @@ func (s *SummarizerService) StopSummarizer(sessionID uint, userID uint) (int64,
 		return 0, fmt.Errorf("session is not active (current status: %s)", session.Status)
 	}
 
+	return s.finishCapture(sessionID)
+}
+
+// RecordConsent records the answer of a signed-in participant to the active
+// session of a meeting. identity must be one of the user's LiveKit identities
+// in the meeting.
+func (s *SummarizerService) RecordConsent(meetingID uint, userID uint, identity string, consented bool) error {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return err
+	}
+
+	participant, err := cache.GetParticipant(meeting.MeetingCode, identity)
+	if err != nil || participant.UserID != userID {
+		return errors.New("unauthorized: identity does not belong to user")
+	}
+
+	session, err := s.sessionRepo.FindActiveByMeetingID(meetingID)
+	if err != nil {
+		return errors.New("no active summarizer session found")
+	}
+
+	s.roomMutex.RLock()
+	bot := s.activeBots[session.ID]
+	s.roomMutex.RUnlock()
+
+	return s.recordConsent(session.ID, meeting.MeetingCode, bot, identity, consented)
+}
+
+// recordConsent stores a participant's answer and applies it to the bot capturing
+// the session. When the bot runs on another server (bot is nil), the answer is
+// published for that server to apply.
+func (s *SummarizerService) recordConsent(sessionID uint, meetingCode string, bot *summarizerBot, identity string, consented bool) error {
+	consent := &models.SummarizerConsent{
+		SessionID:   sessionID,
+		Identity:    identity,
+		Consented:   consented,
+		RespondedAt: time.Now(),
+	}
+	if participant, err := cache.GetParticipant(meetingCode, identity); err == nil && participant.UserID > 0 {
+		consent.UserID = &participant.UserID
+	}
+
+	if err := s.consentRepo.Save(consent); err != nil {
+		return fmt.Errorf("failed to save consent: %w", err)
+	}
+
+	if bot != nil {
+		bot.setDeclined(identity, !consented)
+	} else if err := cache.PublishConsentChange(&cache.ConsentChange{
+		SessionID: sessionID,
+		Identity:  identity,
+		Consented: consented,
+	}); err != nil {
+		// The answer is stored, so a bot that joins later still applies it
+		fmt.Printf("Failed to publish consent of %s in session %d: %v\n", identity, sessionID, err)
+	}
+
+	fmt.Printf("Participant %s answered consent %t in session %d\n", identity, consented, sessionID)
+	return nil
+}
+
+// RunConsentListener applies the consent answers recorded by other servers to the
+// bots capturing on this one. It blocks until ctx is cancelled, so it should be
+// started in its own goroutine after Redis is connected.
+func (s *SummarizerService) RunConsentListener(ctx context.Context) {
+	cache.SubscribeConsentChanges(ctx, func(change *cache.ConsentChange) {
+		s.roomMutex.RLock()
+		bot := s.activeBots[change.SessionID]
+		s.roomMutex.RUnlock()
+
+		if bot != nil {
+			bot.setDeclined(change.Identity, !change.Consented)
+		}
+	})
+}
+
+// checkQuota returns an error when starting one more session would exceed the
+// server-wide limit of capturing sessions or the user's daily limit
+func (s *SummarizerService) checkQuota(userID uint) error {
+	if limit := s.cfg.Summarizer.MaxActiveSessions; limit > 0 {
+		active, err := s.sessionRepo.CountByStatus(models.StatusStarted)
+		if err != nil {
+			return fmt.Errorf("failed to check quota: %w", err)
+		}
+		if active >= int64(limit) {
+			return fmt.Errorf("quota exceeded: too many summarizer sessions are running, try again later")
+		}
+	}
+
+	if limit := s.cfg.Summarizer.MaxDailySessionsPerUser; limit > 0 {
+		started, err := s.sessionRepo.CountByUserSince(userID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("failed to check quota: %w", err)
+		}
+		if started >= int64(limit) {
+			return fmt.Errorf("quota exceeded: at most %d summarizer sessions per day", limit)
+		}
+	}
+
+	return nil
+}
+
+// announce tells participants through the room metadata whether their meeting
+// is being recorded and transcribed and, in consent mode, that they are asked to
+// answer on the consent data topic
+func (s *SummarizerService) announce(bot *summarizerBot, sessionID uint, active bool) {
+	updates := map[string]interface{}{
+		"summarizer_active":           active,
+		"summarizer_session_id":       nil,
+		"summarizer_consent_required": nil,
+	}
+	if active {
+		updates["summarizer_session_id"] = sessionID
+		if bot.consentMode {
+			updates["summarizer_consent_required"] = true
+			updates["summarizer_consent_topic"] = consentTopic
+		}
+	} else {
+		updates["summarizer_consent_topic"] = nil
+	}
+	if err := s.livekitService.MergeRoomMetadata(bot.roomName, updates); err != nil {
+		fmt.Printf("Failed to announce summarizer session %d: %v\n", sessionID, err)
+	}
+}
+
+// finishCapture disconnects the bot of a session, moves the session to CAPTURED
+// and starts its transcription. It is safe to call more than once: only the
+// first call for a STARTED session does anything.
+func (s *SummarizerService) finishCapture(sessionID uint) (int64, error) {
 	// Disconnect from LiveKit room
 	s.roomMutex.Lock()
-	if room, exists := s.activeRooms[sessionID]; exists {
-		room.Disconnect()
-		delete(s.activeRooms, sessionID)
-	}
+	bot, exists := s.activeBots[sessionID]
+	delete(s.activeBots, sessionID)
 	s.roomMutex.Unlock()
 
-	// Wait for all audio processing goroutines to finish
-	s.wg.Wait()
+	if exists {
+		bot.cancelIdleTimer()
+		if bot.room != nil {
+			bot.room.Disconnect()
+		}
+		s.announce(bot, sessionID, false)
+		// Wait for the session's audio processing goroutines to finish
+		bot.wg.Wait()
+	}
 
 	// Update session status to CAPTURED
 	now := time.Now()
-	if err := s.sessionRepo.UpdateStatus(sessionID, models.StatusCaptured, nil, &now); err != nil {
+	updated, err := s.sessionRepo.UpdateStatusFrom(sessionID, models.StatusStarted, models.StatusCaptured, &now)
+	if err != nil {
 		return 0, fmt.Errorf("failed to update session status: %w", err)
 	}
+	if updated == 0 {
+		return 0, fmt.Errorf("session is not active")
+	}
 
 	// Count total chunks
 	totalChunks, err := s.chunkRepo.CountBySessionID(sessionID)
@@ -157,17 +393,42 @@ func (s *SummarizerService) StopSummarizer(sessionID uint, userID uint) (int64,
 	return totalChunks, nil
 }
 
-// joinLiveKitRoom joins the LiveKit room as a bot participant
-func (s *SummarizerService) joinLiveKitRoom(meetingCode string, sessionID uint) error {
-	fmt.Printf("Attempting to join room %s for session %d\n", meetingCode, sessionID)
+// joinLiveKitRoom joins the LiveKit room roomName of a meeting as a bot participant
+func (s *SummarizerService) joinLiveKitRoom(meetingCode string, roomName string, sessionID uint, consentMode bool) error {
+	fmt.Printf("Attempting to join room %s for session %d\n", roomName, sessionID)
 
-	// Create a bot token with hidden permission
-	token, err := s.livekitService.CreateBotToken(meetingCode, sessionID)
+	// Create a bot token, hidden unless participants are asked for consent and
+	// should see who is listening
+	token, err := s.livekitService.CreateBotToken(roomName, sessionID, !consentMode)
 	if err != nil {
 		return fmt.Errorf("failed to create bot token: %w", err)
 	}
 
-	fmt.Printf("Connecting bot to room %s using token (with Hidden=true permission)\n", meetingCode)
+	// Register the bot before connecting: callbacks may fire while joining
+	bot := &summarizerBot{
+		meetingCode: meetingCode,
+		roomName:    roomName,
+		consentMode: consentMode,
+		declined:    make(map[string]bool),
+	}
+	s.roomMutex.Lock()
+	s.activeBots[sessionID] = bot
+	s.roomMutex.Unlock()
+
+	// Answers given before the bot was registered, e.g. to a bot that was
+	// restarted, still apply; later ones reach it directly or by pub/sub
+	consents, err := s.consentRepo.FindBySessionID(sessionID)
+	if err != nil {
+		s.roomMutex.Lock()
+		delete(s.activeBots, sessionID)
+		s.roomMutex.Unlock()
+		return fmt.Errorf("failed to load consents: %w", err)
+	}
+	for _, consent := range consents {
+		bot.setDeclined(consent.Identity, !consent.Consented)
+	}
+
+	fmt.Printf("Connecting bot to room %s using token (hidden: %t)\n", roomName, !consentMode)
 	room, err := lksdk.ConnectToRoomWithToken(s.livekitService.GetURL(), token, &lksdk.RoomCallback{
 		ParticipantCallback: lksdk.ParticipantCallback{
 			OnTrackSubscribed: func(track *webrtc.TrackRemote, publication *lksdk.RemoteTrackPublication, participant *lksdk.RemoteParticipant) {
@@ -175,34 +436,190 @@ func (s *SummarizerService) joinLiveKitRoom(meetingCode string, sessionID uint)
 				// Only process audio tracks
 				if track.Kind() == webrtc.RTPCodecTypeAudio {
 					fmt.Printf("Starting audio capture for user %s\n", participant.Identity())
-					s.wg.Add(1)
-					go s.handleAudioTrack(track, participant, sessionID)
+					bot.wg.Add(1)
+					go s.handleAudioTrack(track, participant, sessionID, bot)
+				}
+			},
+			OnDataPacket: func(data lksdk.DataPacket, params lksdk.DataReceiveParams) {
+				packet, ok := data.(*lksdk.UserDataPacket)
+				if !ok || packet.Topic != consentTopic {
+					return
+				}
+				var answer struct {
+					Consent bool `json:"consent"`
+				}
+				if err := json.Unmarshal(packet.Payload, &answer); err != nil {
+					fmt.Printf("Ignoring malformed consent from %s: %v\n", params.SenderIdentity, err)
+					return
+				}
+				if err := s.recordConsent(sessionID, meetingCode, bot, params.SenderIdentity, answer.Consent); err != nil {
+					fmt.Printf("Failed to record consent of %s: %v\n", params.SenderIdentity, err)
 				}
 			},
 			OnTrackPublished: func(publication *lksdk.RemoteTrackPublication, participant *lksdk.RemoteParticipant) {
 				fmt.Printf("Track published: %s from %s\n", publication.SID(), participant.Identity())
 			},
 		},
+		OnParticipantConnected: func(participant *lksdk.RemoteParticipant) {
+			if isHumanParticipant(participant) {
+				bot.cancelIdleTimer()
+			}
+		},
+		OnParticipantDisconnected: func(participant *lksdk.RemoteParticipant) {
+			s.checkRoomEmpty(sessionID, bot, participant.Identity())
+		},
+		OnDisconnectedWithReason: func(reason lksdk.DisconnectionReason) {
+			// Our own Disconnect reports LeaveRequested; anything else means the room
+			// is gone or the bot was dropped, so nothing more can be captured
+			if reason != lksdk.LeaveRequested {
+				s.autoStop(sessionID, fmt.Sprintf("bot disconnected (%s)", reason))
+			}
+		},
 	})
 
 	if err != nil {
+		s.roomMutex.Lock()
+		delete(s.activeBots, sessionID)
+		s.roomMutex.Unlock()
 		return fmt.Errorf("failed to connect to room: %w", err)
 	}
 
-	// Store room for later disconnection
+	// Store room for later disconnection, unless the session was stopped while joining
 	s.roomMutex.Lock()
-	s.activeRooms[sessionID] = room
+	_, stillActive := s.activeBots[sessionID]
+	if stillActive {
+		bot.room = room
+	}
 	s.roomMutex.Unlock()
+	if !stillActive {
+		room.Disconnect()
+		return nil
+	}
 
-	fmt.Printf("Bot successfully joined room %s for session %d\n", meetingCode, sessionID)
+	fmt.Printf("Bot successfully joined room %s for session %d\n", roomName, sessionID)
+	s.announce(bot, sessionID, true)
 	fmt.Printf("Waiting for participants to publish audio tracks...\n")
 
+	s.checkRoomEmpty(sessionID, bot, "")
+
 	return nil
 }
 
+// checkRoomEmpty starts the idle timer of a session when no participant other
+// than leavingIdentity is left in the room
+func (s *SummarizerService) checkRoomEmpty(sessionID uint, bot *summarizerBot, leavingIdentity string) {
+	s.roomMutex.RLock()
+	room := bot.room
+	s.roomMutex.RUnlock()
+	if room == nil {
+		return
+	}
+
+	for _, participant := range room.GetRemoteParticipants() {
+		if participant.Identity() != leavingIdentity && isHumanParticipant(participant) {
+			return
+		}
+	}
+
+	timeout := time.Duration(s.cfg.Summarizer.IdleTimeoutSeconds) * time.Second
+	fmt.Printf("Room of session %d is empty, stopping in %v unless someone joins\n", sessionID, timeout)
+	bot.startIdleTimer(timeout, func() {
+		s.autoStop(sessionID, "room empty")
+	})
+}
+
+// autoStop stops a session without a host asking for it
+func (s *SummarizerService) autoStop(sessionID uint, reason string) {
+	fmt.Printf("Stopping summarizer session %d automatically: %s\n", sessionID, reason)
+	// finishCapture waits for capture goroutines, which may be the caller's own
+	// callbacks, so it runs on its own goroutine
+	go func() {
+		if _, err := s.finishCapture(sessionID); err != nil {
+			fmt.Printf("Failed to stop session %d automatically: %v\n", sessionID, err)
+		}
+	}()
+}
+
+// HandleWebhookEvent auto-starts the summarizer when a person joins a meeting
+// that asks for it, and stops the active session of a room that finished
+func (s *SummarizerService) HandleWebhookEvent(event *livekit.WebhookEvent) {
+	if event.Room == nil {
+		return
+	}
+
+	switch event.Event {
+	case "participant_joined":
+		if !isHumanParticipantInfo(event.Participant) {
+			return
+		}
+		meeting, err := s.meetingService.GetMeetingByCode(event.Room.Name)
+		if err != nil {
+			return
+		}
+		s.AutoStart(meeting)
+	case "room_finished":
+		meetingCode, roomName := event.Room.Name, ""
+		if code, _, ok := ParseBreakoutRoomName(event.Room.Name); ok {
+			meetingCode, roomName = code, event.Room.Name
+		}
+		meeting, err := s.meetingService.GetMeetingByCode(meetingCode)
+		if err != nil {
+			return
+		}
+		session, err := s.sessionRepo.FindActiveByRoomName(meeting.ID, roomName)
+		if err != nil {
+			return
+		}
+		s.autoStop(session.ID, "room finished")
+	}
+}
+
+// isHumanParticipant reports whether a room participant is a person rather than
+// another bot, an egress or an agent
+func isHumanParticipant(participant *lksdk.RemoteParticipant) bool {
+	if participant.Kind() != lksdk.ParticipantStandard {
+		return false
+	}
+	return !isBotIdentity(participant.Identity())
+}
+
+func (b *summarizerBot) setDeclined(identity string, declined bool) {
+	b.consentMutex.Lock()
+	defer b.consentMutex.Unlock()
+	if declined {
+		b.declined[identity] = true
+	} else {
+		delete(b.declined, identity)
+	}
+}
+
+func (b *summarizerBot) hasDeclined(identity string) bool {
+	b.consentMutex.RLock()
+	defer b.consentMutex.RUnlock()
+	return b.declined[identity]
+}
+
+func (b *summarizerBot) startIdleTimer(timeout time.Duration, stop func()) {
+	b.idleMutex.Lock()
+	defer b.idleMutex.Unlock()
+	if b.idleTimer != nil {
+		return
+	}
+	b.idleTimer = time.AfterFunc(timeout, stop)
+}
+
+func (b *summarizerBot) cancelIdleTimer() {
+	b.idleMutex.Lock()
+	defer b.idleMutex.Unlock()
+	if b.idleTimer != nil {
+		b.idleTimer.Stop()
+		b.idleTimer = nil
+	}
+}
+
 // handleAudioTrack processes audio from a participant's track
-func (s *SummarizerService) handleAudioTrack(track *webrtc.TrackRemote, participant *lksdk.RemoteParticipant, sessionID uint) {
-	defer s.wg.Done()
+func (s *SummarizerService) handleAudioTrack(track *webrtc.TrackRemote, participant *lksdk.RemoteParticipant, sessionID uint, bot *summarizerBot) {
+	defer bot.wg.Done()
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("Recovered from panic in audio handler: %v\n", r)
@@ -226,6 +643,7 @@ func (s *SummarizerService) handleAudioTrack(track *webrtc.TrackRemote, particip
 		chunkStartTime   time.Time
 		chunkIndex       = 0
 		packetCount      = 0
+		paused           = false
 	)
 
 	// closeChunk finalizes the current chunk
@@ -278,6 +696,26 @@ func (s *SummarizerService) handleAudioTrack(track *webrtc.TrackRemote, particip
 			continue
 		}
 
+		// Drop audio while the participant declines to be transcribed; capture
+		// resumes in a new chunk if they consent later
+		if bot.hasDeclined(userIdentity) {
+			if !paused {
+				closeChunk()
+				writer = nil
+				paused = true
+				fmt.Printf("Paused audio capture for user %s: consent declined\n", userIdentity)
+			}
+			continue
+		}
+		if paused {
+			chunkIndex++
+			if err := openNewChunk(); err != nil {
+				fmt.Printf("Failed to create new chunk: %v\n", err)
+				return
+			}
+			paused = false
+		}
+
 		packetCount++
 		if packetCount%100 == 0 {
 			fmt.Printf("Received %d packets from %s\n", packetCount, userIdentity)
@@ -346,6 +784,15 @@ func (s *SummarizerService) GetActiveSession(meetingID uint) (*models.Summarizer
 	return session, nil
 }
 
+// GetActiveRoomSession returns the active session of one breakout room of a meeting
+func (s *SummarizerService) GetActiveRoomSession(meetingID uint, roomName string) (*models.SummarizerSession, error) {
+	session, err := s.sessionRepo.FindActiveByRoomName(meetingID, roomName)
+	if err != nil {
+		return nil, fmt.Errorf("no active summarizer session found")
+	}
+	return session, nil
+}
+
 // GetSessionByID returns a session by its ID
 func (s *SummarizerService) GetSessionByID(sessionID uint) (*models.SummarizerSession, error) {
 	return s.sessionRepo.FindByID(sessionID)
@@ -369,12 +816,7 @@ func (s *SummarizerService) GetSessions(userID uint, page, pageSize int) (*dto.P
 	// Map models to response type
 	sessionList := make([]dto.SessionsList, len(sessions))
 	for i, session := range sessions {
-		sessionList[i] = dto.SessionsList{
-			ID:        session.ID,
-			Status:    session.Status,
-			Error:     session.Error,
-			StartedAt: session.StartedAt,
-		}
+		sessionList[i] = toSessionsList(&session)
 	}
 
 	// Calculate total pages
@@ -403,6 +845,11 @@ func (s *SummarizerService) GetSession(sessionID, userID uint) (*dto.SessionResp
 		return nil, errors.New("unauthorized: session does not belong to user")
 	}
 
+	consents, err := s.consentRepo.FindBySessionID(session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load consents: %w", err)
+	}
+
 	return &dto.SessionResponse{
 		ID:         session.ID,
 		Status:     session.Status,
@@ -411,9 +858,49 @@ func (s *SummarizerService) GetSession(sessionID, userID uint) (*dto.SessionResp
 		Summary:    session.Summary,
 		StartedAt:  session.StartedAt,
 		EndedAt:    session.EndedAt,
+
+		OccurrenceStart: session.OccurrenceStart,
+		RoomName:        session.RoomName,
+
+		Consents: consents,
 	}, nil
 }
 
+// GetMeetingSessions lists the sessions of a meeting for its hosts, in occurrence
+// order, so the summaries of a recurring meeting can be browsed one after another
+func (s *SummarizerService) GetMeetingSessions(meetingID, userID uint) ([]dto.SessionsList, error) {
+	meeting, err := s.meetingService.GetMeetingByID(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.meetingService.IsHost(meeting, userID) {
+		return nil, errors.New("unauthorized: only meeting hosts can view meeting sessions")
+	}
+
+	sessions, err := s.sessionRepo.FindByMeetingIDByOccurrence(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sessions: %w", err)
+	}
+
+	sessionList := make([]dto.SessionsList, len(sessions))
+	for i, session := range sessions {
+		sessionList[i] = toSessionsList(&session)
+	}
+	return sessionList, nil
+}
+
+func toSessionsList(session *models.SummarizerSession) dto.SessionsList {
+	return dto.SessionsList{
+		ID:              session.ID,
+		Status:          session.Status,
+		Error:           session.Error,
+		StartedAt:       session.StartedAt,
+		OccurrenceStart: session.OccurrenceStart,
+		RoomName:        session.RoomName,
+	}
+}
+
 // DeleteSession deletes a session (verifying ownership)
 func (s *SummarizerService) DeleteSession(sessionID, userID uint) error {
 	session, err := s.sessionRepo.FindByID(sessionID)
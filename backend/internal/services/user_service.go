@@ -1,6 +1,8 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"mini-meeting/internal/handlers/dto"
 	"mini-meeting/internal/models"
@@ -196,3 +198,44 @@ func (s *UserService) GetUserByProvider(provider models.Provider, providerID str
 	}
 	return user, nil
 }
+
+// GetCalendarToken returns the secret of the user's calendar feed, creating it on first use
+func (s *UserService) GetCalendarToken(userID uint) (string, error) {
+	user, err := s.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	if user.CalendarToken != nil {
+		return *user.CalendarToken, nil
+	}
+
+	return s.RotateCalendarToken(userID)
+}
+
+// RotateCalendarToken replaces the secret of the user's calendar feed, so the old feed URL stops working
+func (s *UserService) RotateCalendarToken(userID uint) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	if err := s.repo.UpdateCalendarToken(userID, token); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// GetUserByCalendarToken returns the user owning a calendar feed secret
+func (s *UserService) GetUserByCalendarToken(token string) (*models.User, error) {
+	user, err := s.repo.FindByCalendarToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,33 @@
+package workers
+
+import (
+	"time"
+)
+
+// BreakoutDeadlineProcessor recalls breakout rooms that ran out of time and
+// deletes the rooms whose grace period is over
+type BreakoutDeadlineProcessor interface {
+	ProcessDueDeadlines()
+}
+
+type BreakoutDeadlineWorker struct {
+	processor BreakoutDeadlineProcessor
+	interval  time.Duration
+}
+
+func NewBreakoutDeadlineWorker(processor BreakoutDeadlineProcessor, interval time.Duration) *BreakoutDeadlineWorker {
+	return &BreakoutDeadlineWorker{
+		processor: processor,
+		interval:  interval,
+	}
+}
+
+// Start begins the worker loop
+func (w *BreakoutDeadlineWorker) Start() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		w.processor.ProcessDueDeadlines()
+	}
+}
@@ -0,0 +1,32 @@
+package workers
+
+import (
+	"time"
+)
+
+// LobbyExpirer expires lobby requests that waited longer than their meeting's lobby timeout
+type LobbyExpirer interface {
+	ExpireTimedOutRequests()
+}
+
+type LobbyTimeoutWorker struct {
+	expirer  LobbyExpirer
+	interval time.Duration
+}
+
+func NewLobbyTimeoutWorker(expirer LobbyExpirer, interval time.Duration) *LobbyTimeoutWorker {
+	return &LobbyTimeoutWorker{
+		expirer:  expirer,
+		interval: interval,
+	}
+}
+
+// Start begins the worker loop
+func (w *LobbyTimeoutWorker) Start() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		w.expirer.ExpireTimedOutRequests()
+	}
+}
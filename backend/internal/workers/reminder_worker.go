@@ -0,0 +1,32 @@
+package workers
+
+import (
+	"time"
+)
+
+// ReminderSender reminds invitees of meetings that are about to start
+type ReminderSender interface {
+	SendDueReminders()
+}
+
+type ReminderWorker struct {
+	sender   ReminderSender
+	interval time.Duration
+}
+
+func NewReminderWorker(sender ReminderSender, interval time.Duration) *ReminderWorker {
+	return &ReminderWorker{
+		sender:   sender,
+		interval: interval,
+	}
+}
+
+// Start begins the worker loop
+func (w *ReminderWorker) Start() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		w.sender.SendDueReminders()
+	}
+}
@@ -0,0 +1,4 @@
+-- Migration Rollback: create_meeting_settings
+-- Created: 2026-10-19 09:12:40
+
+DROP TABLE IF EXISTS meeting_settings;
@@ -0,0 +1,12 @@
+-- Migration: create_meeting_settings
+-- Created: 2026-10-19 09:12:40
+
+-- Per-meeting settings, one row per meeting (missing row = defaults)
+CREATE TABLE IF NOT EXISTS meeting_settings (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL UNIQUE REFERENCES meetings(id) ON DELETE CASCADE,
+    admission_policy VARCHAR(20) NOT NULL DEFAULT 'manual',
+    allowed_domains TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
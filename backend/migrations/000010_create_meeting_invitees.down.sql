@@ -0,0 +1,4 @@
+-- Migration Rollback: create_meeting_invitees
+-- Created: 2026-10-19 09:13:05
+
+DROP TABLE IF EXISTS meeting_invitees;
@@ -0,0 +1,12 @@
+-- Migration: create_meeting_invitees
+-- Created: 2026-10-19 09:13:05
+
+CREATE TABLE IF NOT EXISTS meeting_invitees (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    email VARCHAR(320) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_meeting_invitees_meeting_email UNIQUE (meeting_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_invitees_meeting_id ON meeting_invitees(meeting_id);
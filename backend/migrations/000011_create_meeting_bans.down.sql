@@ -0,0 +1,4 @@
+-- Migration Rollback: create_meeting_bans
+-- Created: 2026-10-19 10:02:17
+
+DROP TABLE IF EXISTS meeting_bans;
@@ -0,0 +1,17 @@
+-- Migration: create_meeting_bans
+-- Created: 2026-10-19 10:02:17
+
+CREATE TABLE IF NOT EXISTS meeting_bans (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(320) NOT NULL DEFAULT '',
+    fingerprint VARCHAR(255) NOT NULL DEFAULT '',
+    identity VARCHAR(255) NOT NULL DEFAULT '',
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    banned_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_bans_meeting_id ON meeting_bans(meeting_id);
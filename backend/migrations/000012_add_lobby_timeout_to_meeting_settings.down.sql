@@ -0,0 +1,4 @@
+-- Migration Rollback: add_lobby_timeout_to_meeting_settings
+-- Created: 2026-10-19 10:48:31
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS lobby_timeout_seconds;
@@ -0,0 +1,5 @@
+-- Migration: add_lobby_timeout_to_meeting_settings
+-- Created: 2026-10-19 10:48:31
+
+-- How long a visitor may wait in the lobby before the request times out (0 = server default)
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS lobby_timeout_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Migration Rollback: create_meeting_cohosts
+-- Created: 2026-10-19 11:02:41
+
+DROP TABLE IF EXISTS meeting_cohosts;
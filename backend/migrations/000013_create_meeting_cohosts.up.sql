@@ -0,0 +1,13 @@
+-- Migration: create_meeting_cohosts
+-- Created: 2026-10-19 11:02:41
+
+CREATE TABLE IF NOT EXISTS meeting_cohosts (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_meeting_cohosts_meeting_user UNIQUE (meeting_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_cohosts_meeting_id ON meeting_cohosts(meeting_id);
+CREATE INDEX IF NOT EXISTS idx_meeting_cohosts_user_id ON meeting_cohosts(user_id);
@@ -0,0 +1,17 @@
+-- Migration Rollback: add_schedule_to_meetings
+-- Created: 2026-10-19 11:37:12
+
+DROP INDEX IF EXISTS idx_meetings_status;
+DROP INDEX IF EXISTS idx_meetings_start_time;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS status;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS time_zone;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS end_time;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS start_time;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS description;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS title;
@@ -0,0 +1,20 @@
+-- Migration: add_schedule_to_meetings
+-- Created: 2026-10-19 11:37:12
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS title VARCHAR(255);
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS description TEXT;
+
+-- Planned start/end in UTC (NULL for instant meetings)
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS start_time TIMESTAMPTZ;
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS end_time TIMESTAMPTZ;
+
+-- IANA time zone the meeting was planned in
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- Lifecycle: scheduled, live, ended, cancelled
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'scheduled';
+
+CREATE INDEX IF NOT EXISTS idx_meetings_start_time ON meetings(start_time);
+CREATE INDEX IF NOT EXISTS idx_meetings_status ON meetings(status);
@@ -0,0 +1,10 @@
+-- Migration Rollback: add_meeting_recurrence
+-- Created: 2026-10-19 12:14:08
+
+DROP INDEX IF EXISTS idx_summarizer_sessions_meeting_occurrence;
+
+ALTER TABLE summarizer_sessions DROP COLUMN IF EXISTS occurrence_start;
+
+DROP TABLE IF EXISTS meeting_occurrence_overrides;
+
+ALTER TABLE meetings DROP COLUMN IF EXISTS recurrence_rule;
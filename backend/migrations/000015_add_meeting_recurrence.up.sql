@@ -0,0 +1,26 @@
+-- Migration: add_meeting_recurrence
+-- Created: 2026-10-19 12:14:08
+
+-- RFC 5545 RRULE of a recurring meeting ('' for one-off meetings)
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS recurrence_rule VARCHAR(500) NOT NULL DEFAULT '';
+
+-- Per-occurrence changes and exceptions (cancelled occurrences)
+CREATE TABLE IF NOT EXISTS meeting_occurrence_overrides (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    occurrence_start TIMESTAMPTZ NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    start_time TIMESTAMPTZ,
+    end_time TIMESTAMPTZ,
+    title VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_meeting_occurrence_overrides_meeting_start UNIQUE (meeting_id, occurrence_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_occurrence_overrides_meeting_id ON meeting_occurrence_overrides(meeting_id);
+
+-- Occurrence a summarizer session recorded (original start of the occurrence)
+ALTER TABLE summarizer_sessions ADD COLUMN IF NOT EXISTS occurrence_start TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_summarizer_sessions_meeting_occurrence ON summarizer_sessions(meeting_id, occurrence_start);
@@ -0,0 +1,6 @@
+-- Migration Rollback: add_calendar_token_to_users
+-- Created: 2026-10-19 14:05:41
+
+DROP INDEX IF EXISTS idx_users_calendar_token;
+
+ALTER TABLE users DROP COLUMN IF EXISTS calendar_token;
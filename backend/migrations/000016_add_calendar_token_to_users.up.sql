@@ -0,0 +1,7 @@
+-- Migration: add_calendar_token_to_users
+-- Created: 2026-10-19 14:05:41
+
+-- Secret used in the user's calendar subscription URL (NULL until requested)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS calendar_token VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_calendar_token ON users(calendar_token);
@@ -0,0 +1,10 @@
+-- Migration Rollback: add_rsvp_to_meeting_invitees
+-- Created: 2026-10-19 14:52:17
+
+ALTER TABLE meeting_invitees DROP COLUMN IF EXISTS reminded_for;
+
+ALTER TABLE meeting_invitees DROP COLUMN IF EXISTS invited_at;
+
+ALTER TABLE meeting_invitees DROP COLUMN IF EXISTS responded_at;
+
+ALTER TABLE meeting_invitees DROP COLUMN IF EXISTS rsvp_status;
@@ -0,0 +1,13 @@
+-- Migration: add_rsvp_to_meeting_invitees
+-- Created: 2026-10-19 14:52:17
+
+-- Invitee answer: pending, accepted, declined, tentative
+ALTER TABLE meeting_invitees ADD COLUMN IF NOT EXISTS rsvp_status VARCHAR(20) NOT NULL DEFAULT 'pending';
+
+ALTER TABLE meeting_invitees ADD COLUMN IF NOT EXISTS responded_at TIMESTAMPTZ;
+
+-- When the invitation email was sent (NULL until sent)
+ALTER TABLE meeting_invitees ADD COLUMN IF NOT EXISTS invited_at TIMESTAMPTZ;
+
+-- Start of the last occurrence the invitee was reminded of
+ALTER TABLE meeting_invitees ADD COLUMN IF NOT EXISTS reminded_for TIMESTAMPTZ;
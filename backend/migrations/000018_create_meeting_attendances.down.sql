@@ -0,0 +1,4 @@
+-- Migration Rollback: create_meeting_attendances
+-- Created: 2026-10-19 15:31:09
+
+DROP TABLE IF EXISTS meeting_attendances;
@@ -0,0 +1,20 @@
+-- Migration: create_meeting_attendances
+-- Created: 2026-10-19 15:31:09
+
+CREATE TABLE IF NOT EXISTS meeting_attendances (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    room_sid VARCHAR(64) NOT NULL,
+    participant_sid VARCHAR(64) NOT NULL,
+    identity VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    joined_at TIMESTAMPTZ NOT NULL,
+    left_at TIMESTAMPTZ,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_meeting_attendances_participant_sid UNIQUE (participant_sid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_attendances_meeting_id ON meeting_attendances(meeting_id);
+CREATE INDEX IF NOT EXISTS idx_meeting_attendances_room_sid ON meeting_attendances(room_sid);
+CREATE INDEX IF NOT EXISTS idx_meeting_attendances_user_id ON meeting_attendances(user_id);
@@ -0,0 +1,4 @@
+-- Migration Rollback: add_auto_summarize_to_meeting_settings
+-- Created: 2026-10-19 15:58:42
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS auto_summarize;
@@ -0,0 +1,5 @@
+-- Migration: add_auto_summarize_to_meeting_settings
+-- Created: 2026-10-19 15:58:42
+
+-- Start the summarizer automatically when the first participant joins
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS auto_summarize BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+-- Migration Rollback: add_summarizer_consent
+-- Created: 2026-10-19 16:21:05
+
+DROP TABLE IF EXISTS summarizer_consents;
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS consent_mode;
@@ -0,0 +1,15 @@
+-- Migration: add_summarizer_consent
+-- Created: 2026-10-19 16:21:05
+
+-- Announce the summarizer to participants and ask for their consent
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS consent_mode BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS summarizer_consents (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES summarizer_sessions(id) ON DELETE CASCADE,
+    identity VARCHAR(255) NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    consented BOOLEAN NOT NULL,
+    responded_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT idx_summarizer_consents_session_identity UNIQUE (session_id, identity)
+);
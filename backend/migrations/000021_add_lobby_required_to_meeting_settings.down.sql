@@ -0,0 +1,4 @@
+-- Migration Rollback: add_lobby_required_to_meeting_settings
+-- Created: 2026-10-19 16:47:30
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS lobby_required;
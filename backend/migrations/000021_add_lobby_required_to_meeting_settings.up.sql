@@ -0,0 +1,5 @@
+-- Migration: add_lobby_required_to_meeting_settings
+-- Created: 2026-10-19 16:47:30
+
+-- Who must go through the lobby: none, guests, uninvited
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS lobby_required VARCHAR(20) NOT NULL DEFAULT 'none';
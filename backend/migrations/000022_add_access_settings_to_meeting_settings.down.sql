@@ -0,0 +1,12 @@
+-- Migration Rollback: add_access_settings_to_meeting_settings
+-- Created: 2026-10-19 17:12:48
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS summarizer_allowed;
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS locked;
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS max_participants;
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS allow_guests;
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS passcode;
@@ -0,0 +1,14 @@
+-- Migration: add_access_settings_to_meeting_settings
+-- Created: 2026-10-19 17:12:48
+
+-- bcrypt hash of the meeting passcode, empty when none is set
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS passcode VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS allow_guests BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- 0 means no limit
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS max_participants INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS summarizer_allowed BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,6 @@
+-- Migration Rollback: add_participant_roles
+-- Created: 2026-10-19 17:40:16
+
+ALTER TABLE meeting_invitees DROP COLUMN IF EXISTS role;
+
+ALTER TABLE meeting_settings DROP COLUMN IF EXISTS default_role;
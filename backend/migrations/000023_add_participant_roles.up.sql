@@ -0,0 +1,8 @@
+-- Migration: add_participant_roles
+-- Created: 2026-10-19 17:40:16
+
+-- Role of participants who are not hosts; empty means user or guest
+ALTER TABLE meeting_settings ADD COLUMN IF NOT EXISTS default_role VARCHAR(20) NOT NULL DEFAULT '';
+
+-- Role an invitee joins with; empty means the meeting default
+ALTER TABLE meeting_invitees ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT '';
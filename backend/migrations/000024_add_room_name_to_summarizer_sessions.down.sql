@@ -0,0 +1,4 @@
+-- Migration Rollback: add_room_name_to_summarizer_sessions
+-- Created: 2026-10-19 18:05:42
+
+ALTER TABLE summarizer_sessions DROP COLUMN IF EXISTS room_name;
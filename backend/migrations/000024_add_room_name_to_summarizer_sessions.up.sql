@@ -0,0 +1,5 @@
+-- Migration: add_room_name_to_summarizer_sessions
+-- Created: 2026-10-19 18:05:42
+
+-- Breakout room a session captured; empty for the main room of the meeting
+ALTER TABLE summarizer_sessions ADD COLUMN IF NOT EXISTS room_name VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Migration Rollback: create_chat_messages
+-- Created: 2026-10-19 18:41:27
+
+DROP TABLE IF EXISTS chat_messages;
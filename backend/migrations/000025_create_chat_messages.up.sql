@@ -0,0 +1,22 @@
+-- Migration: create_chat_messages
+-- Created: 2026-10-19 18:41:27
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    room_name VARCHAR(64) NOT NULL DEFAULT '',
+    message_id VARCHAR(64) NOT NULL DEFAULT '',
+    identity VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    message TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    edited_at TIMESTAMPTZ,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_meeting_id_sent_at ON chat_messages(meeting_id, sent_at);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_user_id ON chat_messages(user_id);
+
+-- The same message recorded twice (e.g. by two recorders) is stored once
+CREATE UNIQUE INDEX IF NOT EXISTS uq_chat_messages_message_id ON chat_messages(meeting_id, identity, message_id) WHERE message_id <> '';
@@ -0,0 +1,4 @@
+-- Migration Rollback: create_speaker_calls
+-- Created: 2026-10-19 19:12:53
+
+DROP TABLE IF EXISTS speaker_calls;
@@ -0,0 +1,18 @@
+-- Migration: create_speaker_calls
+-- Created: 2026-10-19 19:12:53
+
+CREATE TABLE IF NOT EXISTS speaker_calls (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    room_name VARCHAR(64) NOT NULL DEFAULT '',
+    identity VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    called_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    raised_at TIMESTAMPTZ,
+    called_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_speaker_calls_meeting_id ON speaker_calls(meeting_id);
+CREATE INDEX IF NOT EXISTS idx_speaker_calls_user_id ON speaker_calls(user_id);
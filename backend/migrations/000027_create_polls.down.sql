@@ -0,0 +1,6 @@
+-- Migration Rollback: create_polls
+-- Created: 2026-10-19 19:47:05
+
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,4 @@
+-- Migration Rollback: create_recordings
+-- Created: 2026-10-19 20:31:42
+
+DROP TABLE IF EXISTS recordings;
@@ -0,0 +1,27 @@
+-- Migration: create_recordings
+-- Created: 2026-10-19 20:31:42
+
+CREATE TABLE IF NOT EXISTS recordings (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    started_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    egress_id VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'STARTING',
+    room_name VARCHAR(255) NOT NULL DEFAULT '',
+    track_id VARCHAR(100) NOT NULL DEFAULT '',
+    participant_identity VARCHAR(255) NOT NULL DEFAULT '',
+    audio_only BOOLEAN NOT NULL DEFAULT FALSE,
+    storage VARCHAR(20) NOT NULL,
+    file_path TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    duration_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recordings_egress_id ON recordings(egress_id);
+CREATE INDEX IF NOT EXISTS idx_recordings_meeting_id ON recordings(meeting_id);
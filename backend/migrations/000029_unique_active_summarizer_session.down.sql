@@ -0,0 +1,4 @@
+-- Migration Rollback: unique_active_summarizer_session
+-- Created: 2026-10-19 21:04:17
+
+DROP INDEX IF EXISTS uq_summarizer_sessions_active_room;
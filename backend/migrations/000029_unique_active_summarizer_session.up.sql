@@ -0,0 +1,7 @@
+-- Migration: unique_active_summarizer_session
+-- Created: 2026-10-19 21:04:17
+
+-- At most one session captures a room at a time, whichever backend instance starts it
+CREATE UNIQUE INDEX IF NOT EXISTS uq_summarizer_sessions_active_room
+    ON summarizer_sessions(meeting_id, room_name)
+    WHERE status = 'STARTED';
@@ -0,0 +1,4 @@
+-- Migration Rollback: create_meeting_role_assignments
+-- Created: 2026-10-19 21:32:08
+
+DROP TABLE IF EXISTS meeting_role_assignments;
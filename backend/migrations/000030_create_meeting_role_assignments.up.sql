@@ -0,0 +1,15 @@
+-- Migration: create_meeting_role_assignments
+-- Created: 2026-10-19 21:32:08
+
+CREATE TABLE IF NOT EXISTS meeting_role_assignments (
+    id SERIAL PRIMARY KEY,
+    meeting_id INTEGER NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_meeting_role_assignments_meeting_user UNIQUE (meeting_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_role_assignments_meeting_id ON meeting_role_assignments(meeting_id);
+CREATE INDEX IF NOT EXISTS idx_meeting_role_assignments_user_id ON meeting_role_assignments(user_id);
@@ -0,0 +1,232 @@
+// Package ical writes RFC 5545 iCalendar documents with the VEVENT subset
+// needed to publish meetings to calendar applications.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+
+	// maxLineOctets is the longest content line allowed before folding
+	maxLineOctets = 75
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a VCALENDAR holding a list of events
+type Calendar struct {
+	ProdID string
+	// Name is shown by calendar applications for subscribed feeds
+	Name string
+	// Method is set for invites sent by mail, such as "REQUEST"; feeds leave it empty
+	Method string
+	Events []Event
+}
+
+// Event is a VEVENT. Times are written in UTC unless TimeZone names an IANA
+// zone, in which case they are written as local times with a TZID so that
+// recurring events keep their wall-clock time across DST changes. The calendar
+// then carries a VTIMEZONE describing that zone.
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          *time.Time
+	TimeZone     string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string
+	Organizer    string
+	Created      time.Time
+	LastModified time.Time
+
+	// RRule is the recurrence rule without the "RRULE:" prefix
+	RRule   string
+	ExDates []time.Time
+	// RecurrenceID marks the event as an override of the occurrence starting then
+	RecurrenceID *time.Time
+}
+
+// Bytes renders the calendar with CRLF line endings and folded lines
+func (c *Calendar) Bytes() []byte {
+	w := &writer{}
+	stamp := time.Now()
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		w.line("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escape(c.Name))
+	}
+
+	c.writeTimeZones(w)
+	for i := range c.Events {
+		c.Events[i].write(w, stamp)
+	}
+
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+// writeTimeZones writes a VTIMEZONE for every zone referenced by a TZID,
+// covering the times of all the events in that zone
+func (c *Calendar) writeTimeZones(w *writer) {
+	type span struct {
+		location    *time.Location
+		first, last time.Time
+	}
+	var zones []*span
+	byName := map[string]*span{}
+
+	for i := range c.Events {
+		e := &c.Events[i]
+		location := e.location()
+		if location == nil {
+			continue
+		}
+		z, ok := byName[e.TimeZone]
+		if !ok {
+			z = &span{location: location, first: e.Start, last: e.Start}
+			byName[e.TimeZone] = z
+			zones = append(zones, z)
+		}
+		for _, t := range e.times() {
+			if t.Before(z.first) {
+				z.first = t
+			}
+			if t.After(z.last) {
+				z.last = t
+			}
+		}
+	}
+
+	for _, z := range zones {
+		writeTimeZone(w, z.location, z.first, z.last)
+	}
+}
+
+// location is the zone the event's times are written in, or nil for UTC
+func (e *Event) location() *time.Location {
+	if e.TimeZone == "" || e.TimeZone == "UTC" {
+		return nil
+	}
+	location, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return nil
+	}
+	return location
+}
+
+// times lists every date-time written with the event's TZID
+func (e *Event) times() []time.Time {
+	times := append([]time.Time{e.Start}, e.ExDates...)
+	if e.End != nil {
+		times = append(times, *e.End)
+	}
+	if e.RecurrenceID != nil {
+		times = append(times, *e.RecurrenceID)
+	}
+	return times
+}
+
+func (e *Event) write(w *writer, stamp time.Time) {
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", e.UID)
+	w.line("DTSTAMP", stamp.UTC().Format(utcLayout))
+	if !e.Created.IsZero() {
+		w.line("CREATED", e.Created.UTC().Format(utcLayout))
+	}
+	if !e.LastModified.IsZero() {
+		w.line("LAST-MODIFIED", e.LastModified.UTC().Format(utcLayout))
+	}
+	if e.RecurrenceID != nil {
+		e.timeLine(w, "RECURRENCE-ID", *e.RecurrenceID)
+	}
+	e.timeLine(w, "DTSTART", e.Start)
+	if e.End != nil {
+		e.timeLine(w, "DTEND", *e.End)
+	}
+	if e.RRule != "" {
+		w.line("RRULE", e.RRule)
+	}
+	for _, exdate := range e.ExDates {
+		e.timeLine(w, "EXDATE", exdate)
+	}
+	w.line("SUMMARY", escape(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION", escape(e.Description))
+	}
+	if e.Location != "" {
+		w.line("LOCATION", escape(e.Location))
+	}
+	if e.URL != "" {
+		w.line("URL", e.URL)
+	}
+	if e.Organizer != "" {
+		w.line("ORGANIZER", "mailto:"+e.Organizer)
+	}
+	if e.Status != "" {
+		w.line("STATUS", e.Status)
+	}
+	w.line("END", "VEVENT")
+}
+
+// timeLine writes a date-time property in the event's time zone
+func (e *Event) timeLine(w *writer, name string, t time.Time) {
+	if location := e.location(); location != nil {
+		w.line(name+";TZID="+e.TimeZone, t.In(location).Format(localLayout))
+		return
+	}
+	w.line(name, t.UTC().Format(utcLayout))
+}
+
+// escape escapes a TEXT property value
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it so that no line exceeds 75 octets
+// and no UTF-8 sequence is split
+func (w *writer) line(name, value string) {
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space that counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
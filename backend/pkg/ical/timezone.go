@@ -0,0 +1,146 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// observance is a change of UTC offset in a time zone, written as the
+// STANDARD or DAYLIGHT component of a VTIMEZONE
+type observance struct {
+	at       time.Time
+	from, to int
+	name     string
+	daylight bool
+}
+
+// local is the wall-clock time at which the change happens, in the offset it
+// changes from, as DTSTART of an observance must be written
+func (o *observance) local() time.Time {
+	return o.at.In(time.FixedZone("", o.from))
+}
+
+// rule is the yearly recurrence the change follows, such as the last Sunday of
+// March at 01:00, or "" when it happened before the zone had transitions
+func (o *observance) rule() string {
+	if o.at.IsZero() {
+		return ""
+	}
+	t := o.local()
+	week := (t.Day()-1)/7 + 1
+	if t.Day()+7 > daysIn(t.Month(), t.Year()) {
+		week = -1
+	}
+	day := [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[t.Weekday()]
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", t.Month(), week, day)
+}
+
+// follows reports whether o is the next yearly occurrence of the same change as prev
+func (o *observance) follows(prev *observance) bool {
+	return prev.rule() != "" &&
+		o.rule() == prev.rule() &&
+		o.local().Year() == prev.local().Year()+1 &&
+		o.local().Format("150405") == prev.local().Format("150405") &&
+		o.from == prev.from && o.to == prev.to &&
+		o.name == prev.name && o.daylight == prev.daylight
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// observances lists the offset in effect at from and every change until to
+func observances(location *time.Location, from, to time.Time) []*observance {
+	from = from.In(location)
+	name, offset := from.Zone()
+	start, end := from.ZoneBounds()
+
+	first := &observance{at: start, from: offset, to: offset, name: name, daylight: from.IsDST()}
+	if !start.IsZero() {
+		_, first.from = start.Add(-time.Second).Zone()
+	}
+	list := []*observance{first}
+
+	for !end.IsZero() && end.Before(to) {
+		t := end.In(location)
+		name, offset := t.Zone()
+		// The zone database also ends zones where nothing changes, such as in 2038
+		prev := list[len(list)-1]
+		if offset != prev.to || name != prev.name || t.IsDST() != prev.daylight {
+			list = append(list, &observance{at: end, from: prev.to, to: offset, name: name, daylight: t.IsDST()})
+		}
+		_, end = t.ZoneBounds()
+	}
+	return list
+}
+
+// writeTimeZone writes the VTIMEZONE for location, covering every change of
+// offset from the start of the year of first until a year after last. The
+// latest yearly rule is left open-ended for recurring events that go on past it.
+func writeTimeZone(w *writer, location *time.Location, first, last time.Time) {
+	from := time.Date(first.In(location).Year(), time.January, 1, 0, 0, 0, 0, location)
+	to := time.Date(last.In(location).Year()+2, time.January, 1, 0, 0, 0, 0, location)
+	list := observances(location, from, to)
+
+	// Zones that still change offset after the window keep following their rules
+	ongoing := len(observances(location, to, to.AddDate(1, 0, 0))) > 1
+
+	// Group consecutive yearly occurrences of the same change into one component
+	var groups [][]*observance
+	current := map[bool]int{}
+	for _, o := range list {
+		if i, ok := current[o.daylight]; ok {
+			group := groups[i]
+			if o.follows(group[len(group)-1]) {
+				groups[i] = append(group, o)
+				continue
+			}
+		}
+		current[o.daylight] = len(groups)
+		groups = append(groups, []*observance{o})
+	}
+
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", location.String())
+	for i, group := range groups {
+		o := group[0]
+		kind := "STANDARD"
+		if o.daylight {
+			kind = "DAYLIGHT"
+		}
+
+		w.line("BEGIN", kind)
+		if o.at.IsZero() {
+			w.line("DTSTART", "19700101T000000")
+		} else {
+			w.line("DTSTART", o.local().Format(localLayout))
+		}
+		w.line("TZOFFSETFROM", formatOffset(o.from))
+		w.line("TZOFFSETTO", formatOffset(o.to))
+		if o.name != "" {
+			w.line("TZNAME", o.name)
+		}
+
+		open := ongoing && current[o.daylight] == i && o.rule() != ""
+		if open {
+			w.line("RRULE", o.rule())
+		} else if len(group) > 1 {
+			w.line("RRULE", o.rule()+";UNTIL="+group[len(group)-1].at.UTC().Format(utcLayout))
+		}
+		w.line("END", kind)
+	}
+	w.line("END", "VTIMEZONE")
+}
+
+// formatOffset writes a UTC offset in seconds as ±hhmm, or ±hhmmss when needed
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	if offset%60 != 0 {
+		return fmt.Sprintf("%c%02d%02d%02d", sign, offset/3600, offset/60%60, offset%60)
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset/60%60)
+}
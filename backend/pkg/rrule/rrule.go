@@ -0,0 +1,551 @@
+// Package rrule parses and expands RFC 5545 recurrence rules.
+//
+// The supported subset covers what meeting schedules need: FREQ (DAILY,
+// WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT, UNTIL, BYDAY (with ordinals
+// for MONTHLY and YEARLY rules), BYMONTHDAY, BYMONTH and WKST. Rules outside
+// it, or that can never give an occurrence, are refused by Parse.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ part of a rule
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds expansion so that a rule that stops matching cannot loop forever
+const maxPeriods = 100000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry such as "MO", "1MO" (first Monday) or "-1FR" (last Friday).
+// N is 0 when no ordinal is given.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+func (w WeekdayNum) String() string {
+	day := strings.ToUpper(w.Weekday.String()[:2])
+	if w.N == 0 {
+		return day
+	}
+	return strconv.Itoa(w.N) + day
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []int
+	WeekStart  time.Weekday
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=10".
+// A leading "RRULE:" is accepted. An UNTIL without "Z" is read as UTC; use
+// ParseInLocation for rules of an event that starts in a time zone.
+func Parse(s string) (*Rule, error) {
+	return ParseInLocation(s, time.UTC)
+}
+
+// ParseInLocation parses a rule like Parse, reading an UNTIL without "Z" as a
+// local time in location, the time zone of the event's DTSTART (RFC 5545
+// section 3.3.10)
+func ParseInLocation(s string, location *time.Location) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("empty rule")
+	}
+
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(value))
+			switch r.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parsePositive(value)
+		case "COUNT":
+			r.Count, err = parsePositive(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value, location)
+			r.Until = &until
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(value, -31, 31)
+		case "BYMONTH":
+			r.ByMonth, err = parseIntList(value, 1, 12)
+		case "WKST":
+			day, found := weekdays[strings.ToUpper(value)]
+			if !found {
+				err = fmt.Errorf("invalid WKST %q", value)
+			}
+			r.WeekStart = day
+		default:
+			err = fmt.Errorf("unsupported rule part %q", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot be combined")
+	}
+	for _, day := range r.ByDay {
+		if day.N == 0 {
+			continue
+		}
+		if r.Freq != Monthly && r.Freq != Yearly {
+			return nil, errors.New("BYDAY ordinals are only allowed with MONTHLY or YEARLY")
+		}
+		// Ordinals count the weekdays of a month, or of the year in YEARLY rules without BYMONTH
+		if r.Freq == Yearly && len(r.ByMonth) == 0 {
+			if len(r.ByMonthDay) > 0 {
+				return nil, errors.New("BYDAY ordinals with BYMONTHDAY require BYMONTH in YEARLY rules")
+			}
+		} else if day.N < -5 || day.N > 5 {
+			return nil, fmt.Errorf("BYDAY ordinal %d does not fit in a month", day.N)
+		}
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY is not allowed with WEEKLY")
+	}
+	if !r.monthDaysExist() {
+		return nil, errors.New("BYMONTHDAY never falls in the months of the rule")
+	}
+
+	return r, nil
+}
+
+// monthDaysExist reports whether some BYMONTHDAY is a day of some month the rule allows
+func (r *Rule) monthDaysExist() bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	months := r.ByMonth
+	if len(months) == 0 {
+		months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	}
+	for _, month := range months {
+		// 2000 is a leap year, so February has its 29th
+		last := time.Date(2000, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		for _, day := range r.ByMonthDay {
+			if day <= last && -day <= last {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String formats the rule back to its RFC 5545 text (without the "RRULE:" prefix)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = day.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(r.ByMonth))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.WeekStart.String()[:2]))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Between returns the occurrence start times in [from, to), in order.
+// dtstart is the first occurrence; its location drives the wall-clock time of
+// every occurrence, so a 09:00 meeting stays at 09:00 across DST changes.
+func (r *Rule) Between(dtstart, from, to time.Time) []time.Time {
+	var result []time.Time
+	r.iterate(dtstart, from, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			result = append(result, t)
+		}
+		return true
+	})
+	return result
+}
+
+// After returns the first occurrence at or after t, or false if the rule has ended
+func (r *Rule) After(dtstart, t time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.iterate(dtstart, t, func(occurrence time.Time) bool {
+		if occurrence.Before(t) {
+			return true
+		}
+		next, found = occurrence, true
+		return false
+	})
+	return next, found
+}
+
+// iterate calls fn in order with every occurrence, starting at least from the
+// ones at or after from, until fn returns false or the rule ends
+func (r *Rule) iterate(dtstart, from time.Time, fn func(time.Time) bool) {
+	count := 0
+	first := r.firstPeriod(dtstart, from)
+	for period := first; period < first+maxPeriods; period++ {
+		for _, t := range r.candidates(dtstart, period*r.Interval) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && t.After(*r.Until) {
+				return
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return
+			}
+			if !fn(t) {
+				return
+			}
+		}
+	}
+}
+
+// firstPeriod returns the period to start expanding at so that no occurrence at
+// or after from is missed. Rules with a COUNT start at dtstart, since every
+// occurrence before from counts toward it.
+func (r *Rule) firstPeriod(dtstart, from time.Time) int {
+	if r.Count > 0 || !from.After(dtstart) {
+		return 0
+	}
+	from = from.In(dtstart.Location())
+
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = daysBetween(dtstart, from)
+	case Weekly:
+		periods = daysBetween(dtstart, from) / 7
+	case Monthly:
+		periods = (from.Year()-dtstart.Year())*12 + int(from.Month()) - int(dtstart.Month())
+	case Yearly:
+		periods = from.Year() - dtstart.Year()
+	}
+	// Start a period early in case from falls just before a period boundary
+	return max(periods/r.Interval-1, 0)
+}
+
+// daysBetween counts the calendar days from a to b. It works on Unix days since
+// Time.Sub cannot span more than about 290 years.
+func daysBetween(a, b time.Time) int {
+	dayA := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+	dayB := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return int(dayB - dayA)
+}
+
+// candidates returns the sorted occurrences of the period offset periods after dtstart's period
+func (r *Rule) candidates(dtstart time.Time, offset int) []time.Time {
+	loc := dtstart.Location()
+	hour, min, sec := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, loc)
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(dtstart.Year(), dtstart.Month(), dtstart.Day()+offset)
+		if r.matchesMonth(day) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			days = append(days, day)
+		}
+
+	case Weekly:
+		shift := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-shift+7*offset)
+		for i := 0; i < 7; i++ {
+			day := at(weekStart.Year(), weekStart.Month(), weekStart.Day()+i)
+			if len(r.ByDay) == 0 && day.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			if r.matchesWeekday(day) && r.matchesMonth(day) {
+				days = append(days, day)
+			}
+		}
+
+	case Monthly:
+		first := at(dtstart.Year(), dtstart.Month()+time.Month(offset), 1)
+		if r.matchesMonth(first) {
+			days = r.daysInMonth(first, dtstart.Day())
+		}
+
+	case Yearly:
+		year := dtstart.Year() + offset
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, month := range r.ByMonth {
+				days = append(days, r.daysInMonth(at(year, time.Month(month), 1), dtstart.Day())...)
+			}
+		case len(r.ByMonthDay) > 0:
+			// BYMONTHDAY without BYMONTH applies to every month
+			for month := time.January; month <= time.December; month++ {
+				days = append(days, r.daysInMonth(at(year, month, 1), dtstart.Day())...)
+			}
+		case len(r.ByDay) > 0:
+			days = r.daysInYear(at(year, time.January, 1))
+		default:
+			days = r.daysInMonth(at(year, dtstart.Month(), 1), dtstart.Day())
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// daysInMonth expands BYMONTHDAY and BYDAY within the month starting at first.
+// When both are given a day must match both. Without either, the occurrence
+// falls on defaultDay (skipped when the month is too short).
+func (r *Rule) daysInMonth(first time.Time, defaultDay int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+
+	var candidates []int
+	switch {
+	case len(r.ByMonthDay) > 0:
+		for _, day := range r.ByMonthDay {
+			if day < 0 {
+				day = last + day + 1
+			}
+			candidates = append(candidates, day)
+		}
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			candidates = append(candidates, weekdaysInPeriod(first, last, wd)...)
+		}
+	default:
+		candidates = []int{defaultDay}
+	}
+
+	var days []time.Time
+	seen := make(map[int]struct{}, len(candidates))
+	for _, day := range candidates {
+		if day < 1 || day > last {
+			continue
+		}
+		if _, ok := seen[day]; ok {
+			continue
+		}
+		t := first.AddDate(0, 0, day-1)
+		if len(r.ByMonthDay) > 0 && len(r.ByDay) > 0 && !r.matchesByDayInMonth(t, first, last) {
+			continue
+		}
+		seen[day] = struct{}{}
+		days = append(days, t)
+	}
+	return days
+}
+
+// daysInYear expands BYDAY over the whole year starting at first, with ordinals
+// counting the weekdays of the year (e.g. 20MO is the 20th Monday of the year)
+func (r *Rule) daysInYear(first time.Time) []time.Time {
+	last := first.AddDate(1, 0, -1).YearDay()
+
+	var days []time.Time
+	seen := make(map[int]struct{})
+	for _, wd := range r.ByDay {
+		for _, day := range weekdaysInPeriod(first, last, wd) {
+			if _, ok := seen[day]; ok {
+				continue
+			}
+			seen[day] = struct{}{}
+			days = append(days, first.AddDate(0, 0, day-1))
+		}
+	}
+	return days
+}
+
+// weekdaysInPeriod returns the days, numbered from 1 at first up to last, that
+// match a BYDAY entry
+func weekdaysInPeriod(first time.Time, last int, wd WeekdayNum) []int {
+	var matches []int
+	for day := 1; day <= last; day++ {
+		if first.AddDate(0, 0, day-1).Weekday() == wd.Weekday {
+			matches = append(matches, day)
+		}
+	}
+
+	switch {
+	case wd.N == 0:
+		return matches
+	case wd.N > 0 && wd.N <= len(matches):
+		return []int{matches[wd.N-1]}
+	case wd.N < 0 && -wd.N <= len(matches):
+		return []int{matches[len(matches)+wd.N]}
+	}
+	return nil
+}
+
+// matchesByDayInMonth reports whether t matches any BYDAY entry, honoring ordinals
+func (r *Rule) matchesByDayInMonth(t, first time.Time, last int) bool {
+	for _, wd := range r.ByDay {
+		for _, day := range weekdaysInPeriod(first, last, wd) {
+			if day == t.Day() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonth(t time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, month := range r.ByMonth {
+		if time.Month(month) == t.Month() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, day := range r.ByMonthDay {
+		if day == t.Day() || (day < 0 && last+day+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePositive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid positive number %q", value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, location); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, location); err == nil {
+		// A date-only UNTIL includes the whole day
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(strings.ToUpper(value), ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+		n := 0
+		if ordinal := item[:len(item)-2]; ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(ordinal)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid BYDAY %q", item)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: day})
+	}
+	return days, nil
+}
+
+func parseIntList(value string, min, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("invalid value %q", item)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+func joinInts(values []int) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = strconv.Itoa(v)
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,372 @@
+package rrule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const layout = "2006-01-02 15:04"
+
+func mustLocation(t *testing.T) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	return location
+}
+
+func at(t *testing.T, s string) time.Time {
+	t.Helper()
+	parsed, err := time.ParseInLocation(layout, s, mustLocation(t))
+	if err != nil {
+		t.Fatalf("parse %q: %v", s, err)
+	}
+	return parsed
+}
+
+// Examples from RFC 5545 section 3.8.5.3, all with times in America/New_York.
+// Rules that never end are cut at until.
+func TestBetweenRFCExamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart string
+		until   string
+		want    []string
+	}{
+		{
+			name:    "daily for 10 occurrences",
+			rule:    "FREQ=DAILY;COUNT=10",
+			dtstart: "1997-09-02 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-02 09:00", "1997-09-03 09:00", "1997-09-04 09:00", "1997-09-05 09:00", "1997-09-06 09:00",
+				"1997-09-07 09:00", "1997-09-08 09:00", "1997-09-09 09:00", "1997-09-10 09:00", "1997-09-11 09:00",
+			},
+		},
+		{
+			name:    "every 10 days, 5 occurrences",
+			rule:    "FREQ=DAILY;INTERVAL=10;COUNT=5",
+			dtstart: "1997-09-02 09:00",
+			until:   "2000-01-01 00:00",
+			want:    []string{"1997-09-02 09:00", "1997-09-12 09:00", "1997-09-22 09:00", "1997-10-02 09:00", "1997-10-12 09:00"},
+		},
+		{
+			name:    "weekly for 10 occurrences, across the end of DST",
+			rule:    "FREQ=WEEKLY;COUNT=10",
+			dtstart: "1997-09-02 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-02 09:00", "1997-09-09 09:00", "1997-09-16 09:00", "1997-09-23 09:00", "1997-09-30 09:00",
+				"1997-10-07 09:00", "1997-10-14 09:00", "1997-10-21 09:00", "1997-10-28 09:00", "1997-11-04 09:00",
+			},
+		},
+		{
+			name:    "weekly on Tuesday and Thursday for five weeks",
+			rule:    "FREQ=WEEKLY;COUNT=10;WKST=SU;BYDAY=TU,TH",
+			dtstart: "1997-09-02 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-09 09:00", "1997-09-11 09:00", "1997-09-16 09:00",
+				"1997-09-18 09:00", "1997-09-23 09:00", "1997-09-25 09:00", "1997-09-30 09:00", "1997-10-02 09:00",
+			},
+		},
+		{
+			name:    "every other week on Tuesday and Thursday, for 8 occurrences",
+			rule:    "FREQ=WEEKLY;INTERVAL=2;COUNT=8;WKST=SU;BYDAY=TU,TH",
+			dtstart: "1997-09-02 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-02 09:00", "1997-09-04 09:00", "1997-09-16 09:00", "1997-09-18 09:00",
+				"1997-09-30 09:00", "1997-10-02 09:00", "1997-10-14 09:00", "1997-10-16 09:00",
+			},
+		},
+		{
+			name:    "monthly on the first Friday for 10 occurrences",
+			rule:    "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			dtstart: "1997-09-05 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-05 09:00", "1997-10-03 09:00", "1997-11-07 09:00", "1997-12-05 09:00", "1998-01-02 09:00",
+				"1998-02-06 09:00", "1998-03-06 09:00", "1998-04-03 09:00", "1998-05-01 09:00", "1998-06-05 09:00",
+			},
+		},
+		{
+			name:    "every other month on the first and last Sunday for 10 occurrences",
+			rule:    "FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU",
+			dtstart: "1997-09-07 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-07 09:00", "1997-09-28 09:00", "1997-11-02 09:00", "1997-11-30 09:00", "1998-01-04 09:00",
+				"1998-01-25 09:00", "1998-03-01 09:00", "1998-03-29 09:00", "1998-05-03 09:00", "1998-05-31 09:00",
+			},
+		},
+		{
+			name:    "monthly on the second-to-last Monday for 6 months",
+			rule:    "FREQ=MONTHLY;COUNT=6;BYDAY=-2MO",
+			dtstart: "1997-09-22 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-22 09:00", "1997-10-20 09:00", "1997-11-17 09:00",
+				"1997-12-22 09:00", "1998-01-19 09:00", "1998-02-16 09:00",
+			},
+		},
+		{
+			name:    "monthly on the third-to-last day of the month",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-3",
+			dtstart: "1997-09-28 09:00",
+			until:   "1998-03-01 00:00",
+			want: []string{
+				"1997-09-28 09:00", "1997-10-29 09:00", "1997-11-28 09:00",
+				"1997-12-29 09:00", "1998-01-29 09:00", "1998-02-26 09:00",
+			},
+		},
+		{
+			name:    "monthly on the 2nd and 15th for 10 occurrences",
+			rule:    "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=2,15",
+			dtstart: "1997-09-02 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-02 09:00", "1997-09-15 09:00", "1997-10-02 09:00", "1997-10-15 09:00", "1997-11-02 09:00",
+				"1997-11-15 09:00", "1997-12-02 09:00", "1997-12-15 09:00", "1998-01-02 09:00", "1998-01-15 09:00",
+			},
+		},
+		{
+			name:    "monthly on the first and last day for 10 occurrences",
+			rule:    "FREQ=MONTHLY;COUNT=10;BYMONTHDAY=1,-1",
+			dtstart: "1997-09-30 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-30 09:00", "1997-10-01 09:00", "1997-10-31 09:00", "1997-11-01 09:00", "1997-11-30 09:00",
+				"1997-12-01 09:00", "1997-12-31 09:00", "1998-01-01 09:00", "1998-01-31 09:00", "1998-02-01 09:00",
+			},
+		},
+		{
+			name:    "every 18 months on the 10th to 15th for 10 occurrences",
+			rule:    "FREQ=MONTHLY;INTERVAL=18;COUNT=10;BYMONTHDAY=10,11,12,13,14,15",
+			dtstart: "1997-09-10 09:00",
+			until:   "2000-01-01 00:00",
+			want: []string{
+				"1997-09-10 09:00", "1997-09-11 09:00", "1997-09-12 09:00", "1997-09-13 09:00", "1997-09-14 09:00",
+				"1997-09-15 09:00", "1999-03-10 09:00", "1999-03-11 09:00", "1999-03-12 09:00", "1999-03-13 09:00",
+			},
+		},
+		{
+			name:    "every Friday the 13th",
+			rule:    "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			dtstart: "1997-09-02 09:00",
+			until:   "2001-01-01 00:00",
+			want:    []string{"1998-02-13 09:00", "1998-03-13 09:00", "1998-11-13 09:00", "1999-08-13 09:00", "2000-10-13 09:00"},
+		},
+		{
+			name:    "yearly in June and July for 10 occurrences",
+			rule:    "FREQ=YEARLY;COUNT=10;BYMONTH=6,7",
+			dtstart: "1997-06-10 09:00",
+			until:   "2010-01-01 00:00",
+			want: []string{
+				"1997-06-10 09:00", "1997-07-10 09:00", "1998-06-10 09:00", "1998-07-10 09:00", "1999-06-10 09:00",
+				"1999-07-10 09:00", "2000-06-10 09:00", "2000-07-10 09:00", "2001-06-10 09:00", "2001-07-10 09:00",
+			},
+		},
+		{
+			name:    "every other year on January, February and March for 10 occurrences",
+			rule:    "FREQ=YEARLY;INTERVAL=2;COUNT=10;BYMONTH=1,2,3",
+			dtstart: "1997-03-10 09:00",
+			until:   "2010-01-01 00:00",
+			want: []string{
+				"1997-03-10 09:00", "1999-01-10 09:00", "1999-02-10 09:00", "1999-03-10 09:00", "2001-01-10 09:00",
+				"2001-02-10 09:00", "2001-03-10 09:00", "2003-01-10 09:00", "2003-02-10 09:00", "2003-03-10 09:00",
+			},
+		},
+		{
+			name:    "every 20th Monday of the year",
+			rule:    "FREQ=YEARLY;BYDAY=20MO",
+			dtstart: "1997-05-19 09:00",
+			until:   "2000-01-01 00:00",
+			want:    []string{"1997-05-19 09:00", "1998-05-18 09:00", "1999-05-17 09:00"},
+		},
+		{
+			name:    "every Thursday in March",
+			rule:    "FREQ=YEARLY;BYMONTH=3;BYDAY=TH",
+			dtstart: "1997-03-13 09:00",
+			until:   "1999-01-01 00:00",
+			want: []string{
+				"1997-03-13 09:00", "1997-03-20 09:00", "1997-03-27 09:00",
+				"1998-03-05 09:00", "1998-03-12 09:00", "1998-03-19 09:00", "1998-03-26 09:00",
+			},
+		},
+		{
+			name:    "yearly BYMONTHDAY without BYMONTH falls in every month",
+			rule:    "FREQ=YEARLY;BYMONTHDAY=1",
+			dtstart: "1997-09-01 09:00",
+			until:   "1998-01-15 00:00",
+			want:    []string{"1997-09-01 09:00", "1997-10-01 09:00", "1997-11-01 09:00", "1997-12-01 09:00", "1998-01-01 09:00"},
+		},
+		{
+			name:    "yearly BYDAY without BYMONTH falls in every week",
+			rule:    "FREQ=YEARLY;BYDAY=MO",
+			dtstart: "1997-12-15 09:00",
+			until:   "1998-01-13 00:00",
+			want:    []string{"1997-12-15 09:00", "1997-12-22 09:00", "1997-12-29 09:00", "1998-01-05 09:00", "1998-01-12 09:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			dtstart := at(t, tt.dtstart)
+
+			var got []string
+			for _, occurrence := range rule.Between(dtstart, dtstart, at(t, tt.until)) {
+				got = append(got, occurrence.Format(layout))
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Between:\n got  %v\n want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Expanding far from dtstart starts near from instead of walking every period since dtstart
+func TestBetweenFarFromStart(t *testing.T) {
+	tests := []struct {
+		rule string
+		from string
+		to   string
+		want []string
+	}{
+		{"FREQ=DAILY", "2600-01-01 00:00", "2600-01-03 00:00", []string{"2600-01-01 09:00", "2600-01-02 09:00"}},
+		{"FREQ=WEEKLY;INTERVAL=2", "2600-01-01 00:00", "2600-01-29 00:00", []string{"2600-01-14 09:00", "2600-01-28 09:00"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR", "2600-01-01 00:00", "2600-03-01 00:00", []string{"2600-01-31 09:00", "2600-02-28 09:00"}},
+	}
+
+	dtstart := at(t, "1997-09-02 09:00")
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.rule, err)
+		}
+
+		var got []string
+		for _, occurrence := range rule.Between(dtstart, at(t, tt.from), at(t, tt.to)) {
+			got = append(got, occurrence.Format(layout))
+		}
+		if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: got %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestAfter(t *testing.T) {
+	tests := []struct {
+		rule  string
+		t     string
+		want  string
+		found bool
+	}{
+		{"FREQ=WEEKLY;BYDAY=TU,TH", "1997-09-02 09:00", "1997-09-02 09:00", true},
+		{"FREQ=WEEKLY;BYDAY=TU,TH", "1997-09-02 09:01", "1997-09-04 09:00", true},
+		{"FREQ=DAILY;COUNT=3", "1997-09-04 09:01", "", false},
+		{"FREQ=DAILY;UNTIL=19970905T000000Z", "1997-09-04 09:01", "", false},
+		{"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29", "1997-09-02 09:00", "2000-02-29 09:00", true},
+	}
+
+	dtstart := at(t, "1997-09-02 09:00")
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.rule, err)
+		}
+
+		next, found := rule.After(dtstart, at(t, tt.t))
+		if found != tt.found || (found && next.Format(layout) != tt.want) {
+			t.Errorf("%s after %s: got %v %v, want %v %v", tt.rule, tt.t, next.Format(layout), found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		rule string
+		err  string
+	}{
+		{"", "empty rule"},
+		{"INTERVAL=2", "FREQ is required"},
+		{"FREQ=HOURLY", "unsupported FREQ"},
+		{"FREQ=DAILY;BYSETPOS=1", "unsupported rule part"},
+		{"FREQ=DAILY;COUNT=2;UNTIL=19971224T000000Z", "COUNT and UNTIL cannot be combined"},
+		{"FREQ=WEEKLY;BYDAY=1MO", "only allowed with MONTHLY or YEARLY"},
+		{"FREQ=MONTHLY;BYDAY=6MO", "does not fit in a month"},
+		{"FREQ=YEARLY;BYMONTH=1;BYDAY=-6MO", "does not fit in a month"},
+		{"FREQ=YEARLY;BYMONTHDAY=13;BYDAY=1FR", "require BYMONTH"},
+		{"FREQ=WEEKLY;BYMONTHDAY=1", "not allowed with WEEKLY"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31;BYMONTH=2", "never falls"},
+		{"FREQ=YEARLY;BYMONTHDAY=-31;BYMONTH=4,6,9,11", "never falls"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.rule)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q): got error %v, want one containing %q", tt.rule, err, tt.err)
+		}
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;INTERVAL=2;COUNT=8;BYDAY=TU,TH;WKST=SU",
+		"FREQ=MONTHLY;UNTIL=19971224T000000Z;BYDAY=1SU,-1SU",
+		"FREQ=YEARLY;BYDAY=20MO",
+		"FREQ=YEARLY;BYMONTHDAY=-3;BYMONTH=6,7",
+	} {
+		rule, err := Parse("RRULE:" + s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", s, err)
+		}
+		if got := rule.String(); got != s {
+			t.Errorf("String: got %q, want %q", got, s)
+		}
+	}
+}
+
+// An UNTIL without "Z" is a local time in the time zone of DTSTART
+func TestParseInLocationFloatingUntil(t *testing.T) {
+	location := mustLocation(t)
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"FREQ=DAILY;UNTIL=19970910T090000", "1997-09-10 09:00"},
+		{"FREQ=DAILY;UNTIL=19970910", "1997-09-10 23:59"},
+		{"FREQ=DAILY;UNTIL=19970910T130000Z", "1997-09-10 09:00"},
+	}
+
+	for _, tt := range tests {
+		rule, err := ParseInLocation(tt.rule, location)
+		if err != nil {
+			t.Fatalf("ParseInLocation(%q): %v", tt.rule, err)
+		}
+		if got := rule.Until.In(location).Format(layout); got != tt.want {
+			t.Errorf("%s: UNTIL is %s, want %s", tt.rule, got, tt.want)
+		}
+
+		// The last occurrence at 09:00 local time is on September 10th either way
+		dtstart := at(t, "1997-09-02 09:00")
+		got := rule.Between(dtstart, dtstart, at(t, "1997-09-20 00:00"))
+		if last := got[len(got)-1].Format(layout); last != "1997-09-10 09:00" {
+			t.Errorf("%s: last occurrence %s, want 1997-09-10 09:00", tt.rule, last)
+		}
+	}
+
+	// Written back in UTC, so that the stored rule no longer depends on the zone
+	rule, _ := ParseInLocation("FREQ=DAILY;UNTIL=19970910T090000", location)
+	if got, want := rule.String(), "FREQ=DAILY;UNTIL=19970910T130000Z"; got != want {
+		t.Errorf("String: got %q, want %q", got, want)
+	}
+}
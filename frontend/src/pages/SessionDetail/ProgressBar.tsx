@@ -29,7 +29,9 @@ export const ProgressBar: React.FC<ProgressBarProps> = ({
     );
   }
 
-  const currentIdx = PROCESSING_STEPS.indexOf(status);
+  const currentIdx = PROCESSING_STEPS.indexOf(
+    status as (typeof PROCESSING_STEPS)[number],
+  );
 
   return (
     <div className="flex items-center gap-1.5">
@@ -4,6 +4,12 @@ export const STATUS_CONFIG: Record<
   SummarizerSession["status"],
   { label: string; color: string; bg: string; dot: string }
 > = {
+  IMPORTING: {
+    label: "Importing",
+    color: "text-gray-700",
+    bg: "bg-gray-50",
+    dot: "bg-gray-500",
+  },
   STARTED: {
     label: "Recording",
     color: "text-yellow-700",
@@ -34,6 +40,12 @@ export const STATUS_CONFIG: Record<
     bg: "bg-green-50",
     dot: "bg-green-500",
   },
+  FAILED: {
+    label: "Failed",
+    color: "text-red-700",
+    bg: "bg-red-50",
+    dot: "bg-red-500",
+  },
 };
 
 export const PROCESS_STEPS = [
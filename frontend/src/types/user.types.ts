@@ -1,6 +1,13 @@
 export interface SummarizerSession {
   id: number;
-  status: "STARTED" | "CAPTURED" | "TRANSCRIBED" | "NORMALIZED" | "SUMMARIZED";
+  status:
+    | "IMPORTING"
+    | "STARTED"
+    | "CAPTURED"
+    | "TRANSCRIBED"
+    | "NORMALIZED"
+    | "SUMMARIZED"
+    | "FAILED";
   error: string | null;
   transcript: string | null;
   summary: string | null;
@@ -10,7 +17,14 @@ export interface SummarizerSession {
 
 export interface SummarizerSessionList {
   id: number;
-  status: "STARTED" | "CAPTURED" | "TRANSCRIBED" | "NORMALIZED" | "SUMMARIZED";
+  status:
+    | "IMPORTING"
+    | "STARTED"
+    | "CAPTURED"
+    | "TRANSCRIBED"
+    | "NORMALIZED"
+    | "SUMMARIZED"
+    | "FAILED";
   error: string | null;
   started_at: string;
 }